@@ -28,47 +28,68 @@ import "C"
 
 import (
 	"fmt"
-	"log"
 	"sync"
 	"unsafe"
 )
 
 var (
+	libMu             sync.Mutex
 	ixdcgmLibHandler  unsafe.Pointer
+	ixdcgmLibRefs     int
 	ixdcgmInitCounter int
-	connection        Interface
 )
 
 var (
 	uptDirMu     sync.Mutex
 	ixdcgmBinDir = "/usr/local/ixdcgm/bin"
-	ixdcgmLibDir = "/usr/local/ixdcgm/lib64"
+	ixdcgmLibDir = defaultIxDcgmLibDir
 )
 
 const ixdcgmLib = "libixdcgm.so"
 
-func initIxDcgm(m int) (err error) {
-	lib := string2Char(ixdcgmLib)
-	defer freeCString(lib)
+// loadIxDcgm loads libixdcgm.so and initializes IXDCGM on its first call,
+// the library is shared by all clients and reference counted.
+func loadIxDcgm() (err error) {
+	libMu.Lock()
+	defer libMu.Unlock()
+	if ixdcgmLibRefs > 0 {
+		ixdcgmLibRefs += 1
+		return nil
+	}
 
-	ixdcgmLibHandler = C.dlopen(lib, C.RTLD_LAZY|C.RTLD_GLOBAL)
-	if ixdcgmLibHandler == nil {
-		errMsg := C.GoString(C.dlerror())
-		log.Printf("failed to load %s from system library path: %s\ntry to load from %s\n",
-			ixdcgmLib, errMsg, ixdcgmLibDir)
+	if ixdcgmLibHandler, err = openIxDcgmLib(); err != nil {
+		return err
+	}
 
-		abslib := string2Char(ixdcgmLibDir + "/" + ixdcgmLib)
-		defer freeCString(abslib)
-		ixdcgmLibHandler = C.dlopen(abslib, C.RTLD_LAZY|C.RTLD_GLOBAL)
+	result := C.dcgmInit()
+	if err = errorString(result); err != nil {
+		C.dlclose(ixdcgmLibHandler)
+		loadedLib = nil
+		return fmt.Errorf("failed to initialize dcgm: %w", err)
 	}
-	if ixdcgmLibHandler == nil {
-		errMsg := C.GoString(C.dlerror())
-		return fmt.Errorf("failed to load %s, err: %s", ixdcgmLib, errMsg)
+
+	ixdcgmLibRefs = 1
+	return nil
+}
+
+// unloadIxDcgm shuts down IXDCGM and unloads libixdcgm.so once the last client is closed
+func unloadIxDcgm() (err error) {
+	libMu.Lock()
+	defer libMu.Unlock()
+	if ixdcgmLibRefs <= 0 {
+		return fmt.Errorf("ixdcgm already shutdown")
 	}
 
-	connection, err = New(m)
-	if err != nil {
-		return err
+	ixdcgmLibRefs -= 1
+	if ixdcgmLibRefs > 0 {
+		return nil
+	}
+
+	result := C.dcgmShutdown()
+	C.dlclose(ixdcgmLibHandler)
+	loadedLib = nil
+	if err = errorString(result); err != nil {
+		return fmt.Errorf("failed to shutdown dcgm: %w", err)
 	}
 	return nil
 }
@@ -80,16 +101,11 @@ func shutdown() (err error) {
 		return fmt.Errorf("ixdcgm already shutdown")
 	}
 
-	if ixdcgmInitCounter == 1 {
-		err = connection.Shutdown()
-		if err != nil {
-			return err
-		}
-	}
-
-	C.dlclose(ixdcgmLibHandler)
 	ixdcgmInitCounter -= 1
-	return nil
+	if ixdcgmInitCounter == 0 {
+		err = defaultClient.Close()
+	}
+	return err
 }
 
 func SetIxDcgmBinDir(dir string) error {
@@ -113,5 +129,6 @@ func SetIxDcgmLibDir(dir string) error {
 		return err
 	}
 	ixdcgmLibDir = path
+	ixdcgmLibDirSet = true
 	return nil
 }
@@ -27,8 +27,11 @@ import (
 )
 
 var (
-	mux    sync.Mutex
-	handle DcgmHandle
+	mux sync.Mutex
+
+	// defaultClient is the client used by the package level functions, it is connected by Init
+	// and disconnected by its cleanup, but never replaced so that it can be used concurrently
+	defaultClient = &Client{}
 )
 
 // Init starts IXDCGM, based on the user selected mode
@@ -37,82 +40,121 @@ var (
 // 2. Standalone: Connect to an already running ix-hostengine at the specified address
 // Connection address can be passed as command line args: -connect "IP:PORT/Socket" -socket "isSocket"
 // 3. StartHostengine: Open an Unix socket to start and connect to the ix-hostengine and terminate before exiting
+//
+// Init is kept for compatibility, new code should use InitWithConfig.
 func Init(m int, args ...string) (cleanup func(), err error) {
+	cfg, err := configFromArgs(m, args...)
+	if err != nil {
+		return nil, err
+	}
+	return InitWithConfig(cfg)
+}
+
+// InitWithConfig starts IXDCGM with the given config, which is validated before anything is started.
+// Only the first call returns a cleanup, later calls return a nil cleanup and keep the running IXDCGM.
+func InitWithConfig(cfg Config) (cleanup func(), err error) {
+	if err = cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid ixdcgm config: %w", err)
+	}
+
 	mux.Lock()
 	defer mux.Unlock()
-	if ixdcgmInitCounter < 0 {
-		return nil, fmt.Errorf("ixdcgm already initialized %d", ixdcgmInitCounter)
+	if ixdcgmInitCounter > 0 {
+		return nil, nil
 	}
-	if ixdcgmInitCounter == 0 {
-		err = initIxDcgm(m)
-		if err != nil {
-			return nil, err
-		}
-
-		handle, err = connection.Start(args...)
-		if err != nil {
-			return nil, err
-		}
-		cleanup = func() {
-			shutdown()
-		}
+
+	client, err := newClient(cfg)
+	if err != nil {
+		return nil, err
 	}
+	defaultClient.adopt(client)
 
 	ixdcgmInitCounter += 1
-
-	return cleanup, err
+	cleanup = func() {
+		shutdown()
+	}
+	return cleanup, nil
 }
 
 func GetAllDeviceCount() (uint, error) {
-	return getAllDeviceCount()
+	return defaultClient.GetAllDeviceCount()
 }
 
 func GetSupportedDevices() ([]uint, error) {
-	return getSupportedDevices()
+	return defaultClient.GetSupportedDevices()
 }
 
 // GetDeviceInfo describes the given device
 func GetDeviceInfo(gpuId uint) (DeviceInfo, error) {
-	return getDeviceInfo(gpuId)
+	return defaultClient.GetDeviceInfo(gpuId)
+}
+
+// GetDeviceInfoCtx is GetDeviceInfo returning ctx.Err() as soon as ctx is done
+func GetDeviceInfoCtx(ctx context.Context, gpuId uint) (DeviceInfo, error) {
+	return defaultClient.GetDeviceInfoCtx(ctx, gpuId)
 }
 
 // GetDeviceStatus monitors GPU status including its power, memory and GPU utilization
 func GetDeviceStatus(gpuId uint) (DeviceStatus, error) {
-	return getDeviceStatus(gpuId)
+	return defaultClient.GetDeviceStatus(gpuId)
+}
+
+// GetDeviceStatusCtx is GetDeviceStatus returning ctx.Err() as soon as ctx is done
+func GetDeviceStatusCtx(ctx context.Context, gpuId uint) (DeviceStatus, error) {
+	return defaultClient.GetDeviceStatusCtx(ctx, gpuId)
 }
 
 // GetDeviceProfStatus monitors GPM info including SM_ACTIVE, SM_OCCUPANCY and DRAM_ACTIVE
 func GetDeviceProfStatus(gpuId uint) (DeviceProfStatus, error) {
-	return getDeviceProfStatus(gpuId)
+	return defaultClient.GetDeviceProfStatus(gpuId)
+}
+
+// GetDeviceProfStatusCtx is GetDeviceProfStatus returning ctx.Err() as soon as ctx is done
+func GetDeviceProfStatusCtx(ctx context.Context, gpuId uint) (DeviceProfStatus, error) {
+	return defaultClient.GetDeviceProfStatusCtx(ctx, gpuId)
 }
 
 // GetDeviceRunningProcess get the running process infos for the given gpu id
 func GetDeviceRunningProcesses(gpuId uint) ([]DeviceProcessInfo, error) {
-	return getDeviceRunningProcesses(gpuId)
+	return defaultClient.GetDeviceRunningProcesses(gpuId)
+}
+
+// GetDeviceRunningProcessesCtx is GetDeviceRunningProcesses returning ctx.Err() as soon as ctx is done
+func GetDeviceRunningProcessesCtx(ctx context.Context, gpuId uint) ([]DeviceProcessInfo, error) {
+	return defaultClient.GetDeviceRunningProcessesCtx(ctx, gpuId)
 }
 
 // GetDeviceRunning checks whether the two GPUs are on the same board
 func GetDeviceOnSameBoard(gpuId1, gpuId2 uint) (bool, error) {
-	return getDeviceOnSameBoard(gpuId1, gpuId2)
+	return defaultClient.GetDeviceOnSameBoard(gpuId1, gpuId2)
 }
 
 // HealthCheckByGpuId monitors GPU health for any errors/failures/warnings
 func HealthCheckByGpuId(gpuId uint) (DeviceHealth, error) {
-	return healthCheckByGpuId(gpuId)
+	return defaultClient.HealthCheckByGpuId(gpuId)
+}
+
+// HealthCheckByGpuIdCtx is HealthCheckByGpuId returning ctx.Err() as soon as ctx is done
+func HealthCheckByGpuIdCtx(ctx context.Context, gpuId uint) (DeviceHealth, error) {
+	return defaultClient.HealthCheckByGpuIdCtx(ctx, gpuId)
 }
 
 // GetDeviceTopology returns device topology corresponding to the gpuId
 func GetDeviceTopology(gpuId uint) ([]P2PLink, error) {
-	return getDeviceTopology(gpuId)
+	return defaultClient.GetDeviceTopology(gpuId)
+}
+
+// GetDeviceTopologyCtx is GetDeviceTopology returning ctx.Err() as soon as ctx is done
+func GetDeviceTopologyCtx(ctx context.Context, gpuId uint) ([]P2PLink, error) {
+	return defaultClient.GetDeviceTopologyCtx(ctx, gpuId)
 }
 
 // ListenForPolicyViolationsForAllGPUs sets GPU usage and error policies and notifies in case of any violations on all GPUs
 func ListenForPolicyViolationsForAllGPUs(ctx context.Context, params *PolicyConditionParams) (<-chan PolicyViolation, error) {
-	groupId := GroupAllGPUs()
-	return registerPolicy(ctx, groupId, params)
+	return defaultClient.ListenForPolicyViolationsForAllGPUs(ctx, params)
 }
 
 // ListenForPolicyViolationsForGPUs sets GPU usage and error policies and notifies in case of any violations on special GPUs
 func ListenForPolicyViolationsForGPUs(ctx context.Context, params *PolicyConditionParams, gpuIds ...uint) (<-chan PolicyViolation, error) {
-	return registerPolicyForGpus(ctx, params, gpuIds...)
+	return defaultClient.ListenForPolicyViolationsForGPUs(ctx, params, gpuIds...)
 }
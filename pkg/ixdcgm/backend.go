@@ -0,0 +1,440 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+*/
+import "C"
+import "fmt"
+
+// backend is what the API of a Client is built on: the IXDCGM library reached through cgo,
+// or a simulation such as FakeBackend. Group and field group ids are the ids of the backend.
+type backend interface {
+	// devices
+	getAllDevices() ([]uint, error)
+	getSupportedDevices() ([]uint, error)
+	getDeviceAttributes(gpuId uint) (deviceAttributes, error)
+	getDeviceTopology(gpuId uint) ([]P2PLink, error)
+	getDeviceOnSameBoard(gpuId1, gpuId2 uint) (bool, error)
+	getGroupTopology(groupId C.dcgmGpuGrp_t) (groupTopology, error)
+	selectGpusByTopology(inputMask uint64, numGpus uint32, hintFlags uint64) (uint64, error)
+	getDeviceRunningProcesses(gpuId uint) ([]processMemory, error)
+	getEntities(group Field_Entity_Group, onlySupported bool) ([]uint, error)
+
+	// groups
+	groupCreate(groupType GroupType, groupName string) (C.dcgmGpuGrp_t, error)
+	groupAddDevice(groupId C.dcgmGpuGrp_t, gpuId uint) error
+	groupAddEntity(groupId C.dcgmGpuGrp_t, entityGroupId Field_Entity_Group, entityId uint) error
+	groupRemoveDevice(groupId C.dcgmGpuGrp_t, gpuId uint) error
+	groupRemoveEntity(groupId C.dcgmGpuGrp_t, entityGroupId Field_Entity_Group, entityId uint) error
+	groupDestroy(groupId C.dcgmGpuGrp_t) error
+	groupGetInfo(groupId C.dcgmGpuGrp_t) (*GroupInfo, error)
+	groupGetAllIds() ([]C.dcgmGpuGrp_t, error)
+
+	// field groups, watches and values
+	fieldGroupCreate(groupName string, fields []Short) (C.dcgmFieldGrp_t, error)
+	fieldGroupDestroy(fieldGroupId C.dcgmFieldGrp_t) error
+	watchFields(groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t, updateFreq int64, maxKeepAge float64, maxKeepSamples int32) error
+	unwatchFields(groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t) error
+	updateAllFields(waitForUpdate bool) error
+	getValuesSince(groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t, since int64) ([]entityValue, int64, error)
+	getLatestValuesForFields(gpuId uint, fields []Short) ([]FieldValue_v1, error)
+	entityGetLatestValues(entity GroupEntityPair, fields []Short) ([]FieldValue_v1, error)
+
+	// health
+	healthSet(groupId C.dcgmGpuGrp_t, systems HealthSystem) error
+	healthGet(groupId C.dcgmGpuGrp_t) (HealthSystem, error)
+	healthCheck(groupId C.dcgmGpuGrp_t) (HealthResponse, error)
+
+	// diagnostics
+	runDiag(groupId C.dcgmGpuGrp_t, diagType DiagType) (DiagResults, error)
+
+	// policies, the violations of a registration are delivered through deliverPolicyViolation with its slot
+	policySet(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t, paramList []policyConditionParam) error
+	policyRegister(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t, slot int) error
+	policyUnregister(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t) error
+}
+
+var (
+	_ backend = cgoBackend{}
+	_ backend = (*FakeBackend)(nil)
+	_ backend = guardedBackend{}
+)
+
+// cgoBackend calls the IXDCGM library through the connection handle
+type cgoBackend struct {
+	handle C.dcgmHandle_t
+}
+
+// backend returns the backend of the client, each call goes to the current connection,
+// which is neither closed nor replaced by a reconnect until the call returns
+func (c *Client) backend() backend {
+	return guardedBackend{c}
+}
+
+// backendLocked is the backend of the current connection for the callers holding c.mu
+func (c *Client) backendLocked() backend {
+	var b backend = cgoBackend{c.handle.handle}
+	if c.be != nil {
+		b = c.be
+	}
+	if c.rec != nil {
+		b = recordingBackend{b, c.rec}
+	}
+	return b
+}
+
+// acquire returns the backend of the current connection, which is kept until release is called
+func (c *Client) acquire() (b backend, release func(), err error) {
+	c.mu.RLock()
+	if c.be == nil && c.conn == nil {
+		c.mu.RUnlock()
+		return nil, nil, errNotConnected()
+	}
+	return c.backendLocked(), c.mu.RUnlock, nil
+}
+
+// libHandle returns the handle of the current connection for the calls which are only
+// available through the IXDCGM library, the handle is valid until release is called
+func (c *Client) libHandle() (h C.dcgmHandle_t, release func(), err error) {
+	c.mu.RLock()
+	if c.be != nil {
+		c.mu.RUnlock()
+		return 0, nil, fmt.Errorf("not available without the IXDCGM library: %w", ErrNotSupported)
+	}
+	if c.conn == nil {
+		c.mu.RUnlock()
+		return 0, nil, errNotConnected()
+	}
+	return c.handle.handle, c.mu.RUnlock, nil
+}
+
+// withBackend calls f with the backend of the current connection, which is held until f returns.
+// f resolves the ids of the groups and field groups through c.res and records what it sets up,
+// so that a reconnect can neither change the ids in between nor miss what is set up.
+func (c *Client) withBackend(f func(b backend) error) error {
+	b, release, err := c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return f(b)
+}
+
+func errNotConnected() error {
+	return fmt.Errorf("ixdcgm client is not connected: %w", ErrUninitialized)
+}
+
+// guardedBackend holds the connection of the client for the duration of each call
+type guardedBackend struct {
+	c *Client
+}
+
+func (g guardedBackend) getAllDevices() ([]uint, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return b.getAllDevices()
+}
+
+func (g guardedBackend) getSupportedDevices() ([]uint, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return b.getSupportedDevices()
+}
+
+func (g guardedBackend) getDeviceAttributes(gpuId uint) (deviceAttributes, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return deviceAttributes{}, err
+	}
+	defer release()
+	return b.getDeviceAttributes(gpuId)
+}
+
+func (g guardedBackend) getDeviceTopology(gpuId uint) ([]P2PLink, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return b.getDeviceTopology(gpuId)
+}
+
+func (g guardedBackend) getDeviceOnSameBoard(gpuId1, gpuId2 uint) (bool, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return false, err
+	}
+	defer release()
+	return b.getDeviceOnSameBoard(gpuId1, gpuId2)
+}
+
+func (g guardedBackend) getGroupTopology(groupId C.dcgmGpuGrp_t) (groupTopology, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return groupTopology{}, err
+	}
+	defer release()
+	return b.getGroupTopology(groupId)
+}
+
+func (g guardedBackend) selectGpusByTopology(inputMask uint64, numGpus uint32, hintFlags uint64) (uint64, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	return b.selectGpusByTopology(inputMask, numGpus, hintFlags)
+}
+
+func (g guardedBackend) getDeviceRunningProcesses(gpuId uint) ([]processMemory, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return b.getDeviceRunningProcesses(gpuId)
+}
+
+func (g guardedBackend) getEntities(group Field_Entity_Group, onlySupported bool) ([]uint, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return b.getEntities(group, onlySupported)
+}
+
+func (g guardedBackend) groupCreate(groupType GroupType, groupName string) (C.dcgmGpuGrp_t, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	return b.groupCreate(groupType, groupName)
+}
+
+func (g guardedBackend) groupAddDevice(groupId C.dcgmGpuGrp_t, gpuId uint) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.groupAddDevice(groupId, gpuId)
+}
+
+func (g guardedBackend) groupAddEntity(groupId C.dcgmGpuGrp_t, entityGroupId Field_Entity_Group, entityId uint) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.groupAddEntity(groupId, entityGroupId, entityId)
+}
+
+func (g guardedBackend) groupRemoveDevice(groupId C.dcgmGpuGrp_t, gpuId uint) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.groupRemoveDevice(groupId, gpuId)
+}
+
+func (g guardedBackend) groupRemoveEntity(groupId C.dcgmGpuGrp_t, entityGroupId Field_Entity_Group, entityId uint) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.groupRemoveEntity(groupId, entityGroupId, entityId)
+}
+
+func (g guardedBackend) groupDestroy(groupId C.dcgmGpuGrp_t) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.groupDestroy(groupId)
+}
+
+func (g guardedBackend) groupGetInfo(groupId C.dcgmGpuGrp_t) (*GroupInfo, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return b.groupGetInfo(groupId)
+}
+
+func (g guardedBackend) groupGetAllIds() ([]C.dcgmGpuGrp_t, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return b.groupGetAllIds()
+}
+
+func (g guardedBackend) fieldGroupCreate(groupName string, fields []Short) (C.dcgmFieldGrp_t, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	return b.fieldGroupCreate(groupName, fields)
+}
+
+func (g guardedBackend) fieldGroupDestroy(fieldGroupId C.dcgmFieldGrp_t) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.fieldGroupDestroy(fieldGroupId)
+}
+
+func (g guardedBackend) watchFields(
+	groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t, updateFreq int64, maxKeepAge float64, maxKeepSamples int32,
+) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.watchFields(groupId, fieldGroupId, updateFreq, maxKeepAge, maxKeepSamples)
+}
+
+func (g guardedBackend) unwatchFields(groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.unwatchFields(groupId, fieldGroupId)
+}
+
+func (g guardedBackend) updateAllFields(waitForUpdate bool) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.updateAllFields(waitForUpdate)
+}
+
+func (g guardedBackend) getValuesSince(
+	groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t, since int64,
+) ([]entityValue, int64, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return nil, since, err
+	}
+	defer release()
+	return b.getValuesSince(groupId, fieldGroupId, since)
+}
+
+func (g guardedBackend) getLatestValuesForFields(gpuId uint, fields []Short) ([]FieldValue_v1, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return b.getLatestValuesForFields(gpuId, fields)
+}
+
+func (g guardedBackend) entityGetLatestValues(entity GroupEntityPair, fields []Short) ([]FieldValue_v1, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return b.entityGetLatestValues(entity, fields)
+}
+
+func (g guardedBackend) healthSet(groupId C.dcgmGpuGrp_t, systems HealthSystem) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.healthSet(groupId, systems)
+}
+
+func (g guardedBackend) healthGet(groupId C.dcgmGpuGrp_t) (HealthSystem, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	return b.healthGet(groupId)
+}
+
+func (g guardedBackend) healthCheck(groupId C.dcgmGpuGrp_t) (HealthResponse, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return HealthResponse{}, err
+	}
+	defer release()
+	return b.healthCheck(groupId)
+}
+
+func (g guardedBackend) runDiag(groupId C.dcgmGpuGrp_t, diagType DiagType) (DiagResults, error) {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return DiagResults{}, err
+	}
+	defer release()
+	return b.runDiag(groupId, diagType)
+}
+
+func (g guardedBackend) policySet(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t, paramList []policyConditionParam) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.policySet(groupId, condition, paramList)
+}
+
+func (g guardedBackend) policyRegister(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t, slot int) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.policyRegister(groupId, condition, slot)
+}
+
+func (g guardedBackend) policyUnregister(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t) error {
+	b, release, err := g.c.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return b.policyUnregister(groupId, condition)
+}
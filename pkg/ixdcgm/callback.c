@@ -1,11 +1,70 @@
-int violationPolicyNotify(void *p)
+#include <stddef.h>
+#include "include/dcgm_structs.h"
+
+int ViolationPolicyRegistration(void *, int);
+
+// A policy callback gets no user data, so every policy listener is registered with the callback of
+// its own slot, see policyListenerSlots.
+#define VIOLATION_POLICY_NOTIFY(slot)                \
+    static int violationPolicyNotify##slot(void *p)  \
+    {                                                \
+        return ViolationPolicyRegistration(p, slot); \
+    }
+
+VIOLATION_POLICY_NOTIFY(0) VIOLATION_POLICY_NOTIFY(1) VIOLATION_POLICY_NOTIFY(2) VIOLATION_POLICY_NOTIFY(3) VIOLATION_POLICY_NOTIFY(4) VIOLATION_POLICY_NOTIFY(5) VIOLATION_POLICY_NOTIFY(6) VIOLATION_POLICY_NOTIFY(7)
+VIOLATION_POLICY_NOTIFY(8) VIOLATION_POLICY_NOTIFY(9) VIOLATION_POLICY_NOTIFY(10) VIOLATION_POLICY_NOTIFY(11) VIOLATION_POLICY_NOTIFY(12) VIOLATION_POLICY_NOTIFY(13) VIOLATION_POLICY_NOTIFY(14) VIOLATION_POLICY_NOTIFY(15)
+VIOLATION_POLICY_NOTIFY(16) VIOLATION_POLICY_NOTIFY(17) VIOLATION_POLICY_NOTIFY(18) VIOLATION_POLICY_NOTIFY(19) VIOLATION_POLICY_NOTIFY(20) VIOLATION_POLICY_NOTIFY(21) VIOLATION_POLICY_NOTIFY(22) VIOLATION_POLICY_NOTIFY(23)
+VIOLATION_POLICY_NOTIFY(24) VIOLATION_POLICY_NOTIFY(25) VIOLATION_POLICY_NOTIFY(26) VIOLATION_POLICY_NOTIFY(27) VIOLATION_POLICY_NOTIFY(28) VIOLATION_POLICY_NOTIFY(29) VIOLATION_POLICY_NOTIFY(30) VIOLATION_POLICY_NOTIFY(31)
+VIOLATION_POLICY_NOTIFY(32) VIOLATION_POLICY_NOTIFY(33) VIOLATION_POLICY_NOTIFY(34) VIOLATION_POLICY_NOTIFY(35) VIOLATION_POLICY_NOTIFY(36) VIOLATION_POLICY_NOTIFY(37) VIOLATION_POLICY_NOTIFY(38) VIOLATION_POLICY_NOTIFY(39)
+VIOLATION_POLICY_NOTIFY(40) VIOLATION_POLICY_NOTIFY(41) VIOLATION_POLICY_NOTIFY(42) VIOLATION_POLICY_NOTIFY(43) VIOLATION_POLICY_NOTIFY(44) VIOLATION_POLICY_NOTIFY(45) VIOLATION_POLICY_NOTIFY(46) VIOLATION_POLICY_NOTIFY(47)
+VIOLATION_POLICY_NOTIFY(48) VIOLATION_POLICY_NOTIFY(49) VIOLATION_POLICY_NOTIFY(50) VIOLATION_POLICY_NOTIFY(51) VIOLATION_POLICY_NOTIFY(52) VIOLATION_POLICY_NOTIFY(53) VIOLATION_POLICY_NOTIFY(54) VIOLATION_POLICY_NOTIFY(55)
+VIOLATION_POLICY_NOTIFY(56) VIOLATION_POLICY_NOTIFY(57) VIOLATION_POLICY_NOTIFY(58) VIOLATION_POLICY_NOTIFY(59) VIOLATION_POLICY_NOTIFY(60) VIOLATION_POLICY_NOTIFY(61) VIOLATION_POLICY_NOTIFY(62) VIOLATION_POLICY_NOTIFY(63)
+VIOLATION_POLICY_NOTIFY(64) VIOLATION_POLICY_NOTIFY(65) VIOLATION_POLICY_NOTIFY(66) VIOLATION_POLICY_NOTIFY(67) VIOLATION_POLICY_NOTIFY(68) VIOLATION_POLICY_NOTIFY(69) VIOLATION_POLICY_NOTIFY(70) VIOLATION_POLICY_NOTIFY(71)
+VIOLATION_POLICY_NOTIFY(72) VIOLATION_POLICY_NOTIFY(73) VIOLATION_POLICY_NOTIFY(74) VIOLATION_POLICY_NOTIFY(75) VIOLATION_POLICY_NOTIFY(76) VIOLATION_POLICY_NOTIFY(77) VIOLATION_POLICY_NOTIFY(78) VIOLATION_POLICY_NOTIFY(79)
+VIOLATION_POLICY_NOTIFY(80) VIOLATION_POLICY_NOTIFY(81) VIOLATION_POLICY_NOTIFY(82) VIOLATION_POLICY_NOTIFY(83) VIOLATION_POLICY_NOTIFY(84) VIOLATION_POLICY_NOTIFY(85) VIOLATION_POLICY_NOTIFY(86) VIOLATION_POLICY_NOTIFY(87)
+VIOLATION_POLICY_NOTIFY(88) VIOLATION_POLICY_NOTIFY(89) VIOLATION_POLICY_NOTIFY(90) VIOLATION_POLICY_NOTIFY(91) VIOLATION_POLICY_NOTIFY(92) VIOLATION_POLICY_NOTIFY(93) VIOLATION_POLICY_NOTIFY(94) VIOLATION_POLICY_NOTIFY(95)
+VIOLATION_POLICY_NOTIFY(96) VIOLATION_POLICY_NOTIFY(97) VIOLATION_POLICY_NOTIFY(98) VIOLATION_POLICY_NOTIFY(99) VIOLATION_POLICY_NOTIFY(100) VIOLATION_POLICY_NOTIFY(101) VIOLATION_POLICY_NOTIFY(102) VIOLATION_POLICY_NOTIFY(103)
+VIOLATION_POLICY_NOTIFY(104) VIOLATION_POLICY_NOTIFY(105) VIOLATION_POLICY_NOTIFY(106) VIOLATION_POLICY_NOTIFY(107) VIOLATION_POLICY_NOTIFY(108) VIOLATION_POLICY_NOTIFY(109) VIOLATION_POLICY_NOTIFY(110) VIOLATION_POLICY_NOTIFY(111)
+VIOLATION_POLICY_NOTIFY(112) VIOLATION_POLICY_NOTIFY(113) VIOLATION_POLICY_NOTIFY(114) VIOLATION_POLICY_NOTIFY(115) VIOLATION_POLICY_NOTIFY(116) VIOLATION_POLICY_NOTIFY(117) VIOLATION_POLICY_NOTIFY(118) VIOLATION_POLICY_NOTIFY(119)
+VIOLATION_POLICY_NOTIFY(120) VIOLATION_POLICY_NOTIFY(121) VIOLATION_POLICY_NOTIFY(122) VIOLATION_POLICY_NOTIFY(123) VIOLATION_POLICY_NOTIFY(124) VIOLATION_POLICY_NOTIFY(125) VIOLATION_POLICY_NOTIFY(126) VIOLATION_POLICY_NOTIFY(127)
+
+static const fpRecvUpdates violationPolicyNotifiers[] = {
+    violationPolicyNotify0, violationPolicyNotify1, violationPolicyNotify2, violationPolicyNotify3, violationPolicyNotify4, violationPolicyNotify5, violationPolicyNotify6, violationPolicyNotify7,
+    violationPolicyNotify8, violationPolicyNotify9, violationPolicyNotify10, violationPolicyNotify11, violationPolicyNotify12, violationPolicyNotify13, violationPolicyNotify14, violationPolicyNotify15,
+    violationPolicyNotify16, violationPolicyNotify17, violationPolicyNotify18, violationPolicyNotify19, violationPolicyNotify20, violationPolicyNotify21, violationPolicyNotify22, violationPolicyNotify23,
+    violationPolicyNotify24, violationPolicyNotify25, violationPolicyNotify26, violationPolicyNotify27, violationPolicyNotify28, violationPolicyNotify29, violationPolicyNotify30, violationPolicyNotify31,
+    violationPolicyNotify32, violationPolicyNotify33, violationPolicyNotify34, violationPolicyNotify35, violationPolicyNotify36, violationPolicyNotify37, violationPolicyNotify38, violationPolicyNotify39,
+    violationPolicyNotify40, violationPolicyNotify41, violationPolicyNotify42, violationPolicyNotify43, violationPolicyNotify44, violationPolicyNotify45, violationPolicyNotify46, violationPolicyNotify47,
+    violationPolicyNotify48, violationPolicyNotify49, violationPolicyNotify50, violationPolicyNotify51, violationPolicyNotify52, violationPolicyNotify53, violationPolicyNotify54, violationPolicyNotify55,
+    violationPolicyNotify56, violationPolicyNotify57, violationPolicyNotify58, violationPolicyNotify59, violationPolicyNotify60, violationPolicyNotify61, violationPolicyNotify62, violationPolicyNotify63,
+    violationPolicyNotify64, violationPolicyNotify65, violationPolicyNotify66, violationPolicyNotify67, violationPolicyNotify68, violationPolicyNotify69, violationPolicyNotify70, violationPolicyNotify71,
+    violationPolicyNotify72, violationPolicyNotify73, violationPolicyNotify74, violationPolicyNotify75, violationPolicyNotify76, violationPolicyNotify77, violationPolicyNotify78, violationPolicyNotify79,
+    violationPolicyNotify80, violationPolicyNotify81, violationPolicyNotify82, violationPolicyNotify83, violationPolicyNotify84, violationPolicyNotify85, violationPolicyNotify86, violationPolicyNotify87,
+    violationPolicyNotify88, violationPolicyNotify89, violationPolicyNotify90, violationPolicyNotify91, violationPolicyNotify92, violationPolicyNotify93, violationPolicyNotify94, violationPolicyNotify95,
+    violationPolicyNotify96, violationPolicyNotify97, violationPolicyNotify98, violationPolicyNotify99, violationPolicyNotify100, violationPolicyNotify101, violationPolicyNotify102, violationPolicyNotify103,
+    violationPolicyNotify104, violationPolicyNotify105, violationPolicyNotify106, violationPolicyNotify107, violationPolicyNotify108, violationPolicyNotify109, violationPolicyNotify110, violationPolicyNotify111,
+    violationPolicyNotify112, violationPolicyNotify113, violationPolicyNotify114, violationPolicyNotify115, violationPolicyNotify116, violationPolicyNotify117, violationPolicyNotify118, violationPolicyNotify119,
+    violationPolicyNotify120, violationPolicyNotify121, violationPolicyNotify122, violationPolicyNotify123, violationPolicyNotify124, violationPolicyNotify125, violationPolicyNotify126, violationPolicyNotify127,
+};
+
+fpRecvUpdates violationPolicyNotifier(int slot)
 {
-    int ViolationPolicyRegistration(void *);
-    return ViolationPolicyRegistration(p);
+    if (slot < 0 || slot >= (int)(sizeof(violationPolicyNotifiers) / sizeof(violationPolicyNotifiers[0])))
+    {
+        return NULL;
+    }
+    return violationPolicyNotifiers[slot];
 }
 
 int voidPolicyCallback(void *p)
 {
     int VoidPolicyCallback(void *);
     return VoidPolicyCallback(p);
-}
\ No newline at end of file
+}
+
+int valuesSinceEnumeration(dcgm_field_entity_group_t entityGroupId, dcgm_field_eid_t entityId,
+                           dcgmFieldValue_v1 *values, int numValues, void *userData)
+{
+    int valuesSinceCallback(dcgm_field_entity_group_t, dcgm_field_eid_t, dcgmFieldValue_v1 *, int, void *);
+    return valuesSinceCallback(entityGroupId, entityId, values, numValues, userData);
+}
@@ -0,0 +1,221 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Client is a connection to an ix-hostengine. Groups and field groups created by a client
+// belong to its connection and must only be used with the same client.
+// Several clients can be connected at the same time, e.g. to different remote ix-hostengines in Standalone mode.
+//
+// A client is safe for concurrent use by multiple goroutines, as are the package level functions.
+// Close and reconnects wait for the calls in progress, the calls made once the client is closed
+// return an error wrapping ErrUninitialized.
+type Client struct {
+	// mu guards handle, conn and be, which are replaced on reconnect and close.
+	// The calls to the backend hold it for reading until they return.
+	mu     sync.RWMutex
+	handle DcgmHandle
+	conn   Interface
+
+	// be replaces the IXDCGM library for a client which is not connected through cgo, e.g. a FakeBackend
+	be backend
+	// rec records the calls to the backend, see StartRecording
+	rec *recorder
+
+	// res records what is set up through the client to restore it on reconnect
+	res clientResources
+
+	// supervisor is the running connection supervisor, if any
+	supervisor *supervisor
+}
+
+// NewClient starts IXDCGM and connects to the ix-hostengine described by cfg.
+// The returned client must be closed by Close once it is no longer used.
+func NewClient(cfg Config) (*Client, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid ixdcgm config: %w", err)
+	}
+	return newClient(cfg)
+}
+
+func newClient(cfg Config) (*Client, error) {
+	if cfg.Logger != nil {
+		SetLogger(cfg.Logger)
+	}
+
+	if err := loadIxDcgm(); err != nil {
+		return nil, err
+	}
+
+	conn, err := newMode(cfg.Mode)
+	if err != nil {
+		_ = unloadIxDcgm()
+		return nil, err
+	}
+
+	h, err := conn.start(cfg)
+	if err != nil {
+		_ = unloadIxDcgm()
+		return nil, err
+	}
+
+	client := &Client{handle: h, conn: conn}
+	if cfg.VersionCheck == VersionCheckNone {
+		return client, nil
+	}
+
+	if err = applyVersionCheck(cfg.VersionCheck, client.CheckCompatibility()); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// adopt makes c use the connection of other, which is not used anymore
+func (c *Client) adopt(other *Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handle, c.conn, c.be = other.handle, other.conn, other.be
+}
+
+// Close destroys the groups and field groups left by the client and disconnects it from the ix-hostengine
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.be != nil {
+		c.destroyResourcesLocked()
+		c.be = nil
+		return nil
+	}
+	if c.conn == nil {
+		return errNotConnected()
+	}
+	if c.supervisor != nil {
+		c.supervisor.cancel()
+		c.supervisor = nil
+	}
+
+	c.destroyResourcesLocked()
+	err := c.conn.Shutdown()
+	c.conn = nil
+	c.handle = DcgmHandle{}
+	if uerr := unloadIxDcgm(); err == nil {
+		err = uerr
+	}
+	return err
+}
+
+// destroyResourcesLocked destroys the groups and field groups of the client, the caller holds c.mu
+func (c *Client) destroyResourcesLocked() {
+	if err := c.res.destroyAll(c.backendLocked()); err != nil {
+		getLogger().Warn("Failed to destroy the resources left by the client", "error", err)
+	}
+}
+
+func (c *Client) GetAllDeviceCount() (uint, error) {
+	return c.getAllDeviceCount()
+}
+
+func (c *Client) GetSupportedDevices() ([]uint, error) {
+	return c.getSupportedDevices()
+}
+
+// GetDeviceInfo describes the given device
+func (c *Client) GetDeviceInfo(gpuId uint) (DeviceInfo, error) {
+	return c.getDeviceInfo(context.Background(), gpuId)
+}
+
+// GetDeviceInfoCtx is GetDeviceInfo returning ctx.Err() as soon as ctx is done
+func (c *Client) GetDeviceInfoCtx(ctx context.Context, gpuId uint) (DeviceInfo, error) {
+	return c.getDeviceInfo(ctx, gpuId)
+}
+
+// GetDeviceStatus monitors GPU status including its power, memory and GPU utilization
+func (c *Client) GetDeviceStatus(gpuId uint) (DeviceStatus, error) {
+	return c.getDeviceStatus(context.Background(), gpuId)
+}
+
+// GetDeviceStatusCtx is GetDeviceStatus returning ctx.Err() as soon as ctx is done
+func (c *Client) GetDeviceStatusCtx(ctx context.Context, gpuId uint) (DeviceStatus, error) {
+	return c.getDeviceStatus(ctx, gpuId)
+}
+
+// GetDeviceProfStatus monitors GPM info including SM_ACTIVE, SM_OCCUPANCY and DRAM_ACTIVE
+func (c *Client) GetDeviceProfStatus(gpuId uint) (DeviceProfStatus, error) {
+	return c.getDeviceProfStatus(context.Background(), gpuId)
+}
+
+// GetDeviceProfStatusCtx is GetDeviceProfStatus returning ctx.Err() as soon as ctx is done
+func (c *Client) GetDeviceProfStatusCtx(ctx context.Context, gpuId uint) (DeviceProfStatus, error) {
+	return c.getDeviceProfStatus(ctx, gpuId)
+}
+
+// GetDeviceRunningProcesses get the running process infos for the given gpu id
+func (c *Client) GetDeviceRunningProcesses(gpuId uint) ([]DeviceProcessInfo, error) {
+	return c.getDeviceRunningProcesses(context.Background(), gpuId)
+}
+
+// GetDeviceRunningProcessesCtx is GetDeviceRunningProcesses returning ctx.Err() as soon as ctx is done
+func (c *Client) GetDeviceRunningProcessesCtx(ctx context.Context, gpuId uint) ([]DeviceProcessInfo, error) {
+	return c.getDeviceRunningProcesses(ctx, gpuId)
+}
+
+// GetDeviceOnSameBoard checks whether the two GPUs are on the same board
+func (c *Client) GetDeviceOnSameBoard(gpuId1, gpuId2 uint) (bool, error) {
+	return c.getDeviceOnSameBoard(gpuId1, gpuId2)
+}
+
+// HealthCheckByGpuId monitors GPU health for any errors/failures/warnings
+func (c *Client) HealthCheckByGpuId(gpuId uint) (DeviceHealth, error) {
+	return c.healthCheckByGpuId(context.Background(), gpuId)
+}
+
+// HealthCheckByGpuIdCtx is HealthCheckByGpuId returning ctx.Err() as soon as ctx is done
+func (c *Client) HealthCheckByGpuIdCtx(ctx context.Context, gpuId uint) (DeviceHealth, error) {
+	return c.healthCheckByGpuId(ctx, gpuId)
+}
+
+// GetDeviceTopology returns device topology corresponding to the gpuId
+func (c *Client) GetDeviceTopology(gpuId uint) ([]P2PLink, error) {
+	return c.getDeviceTopology(context.Background(), gpuId)
+}
+
+// GetDeviceTopologyCtx is GetDeviceTopology returning ctx.Err() as soon as ctx is done
+func (c *Client) GetDeviceTopologyCtx(ctx context.Context, gpuId uint) ([]P2PLink, error) {
+	return c.getDeviceTopology(ctx, gpuId)
+}
+
+// ListenForPolicyViolationsForAllGPUs sets GPU usage and error policies and notifies in case of any violations on all GPUs
+func (c *Client) ListenForPolicyViolationsForAllGPUs(ctx context.Context, params *PolicyConditionParams) (<-chan PolicyViolation, error) {
+	return c.registerPolicyForAllGpus(ctx, params)
+}
+
+// ListenForPolicyViolationsForGPUs sets GPU usage and error policies and notifies in case of any violations on special GPUs
+func (c *Client) ListenForPolicyViolationsForGPUs(ctx context.Context, params *PolicyConditionParams, gpuIds ...uint) (<-chan PolicyViolation, error) {
+	return c.registerPolicyForGpus(ctx, params, gpuIds...)
+}
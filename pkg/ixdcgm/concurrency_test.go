@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestConcurrentCalls(t *testing.T) {
+	const gpuCount = 8
+	fake := ixdcgm.NewFakeBackend(gpuCount)
+	for gpuId := uint(0); gpuId < gpuCount; gpuId++ {
+		fake.SetFieldInt64(gpuId, ixdcgm.DCGM_FI_DEV_GPU_TEMP, int64(40+gpuId))
+	}
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, gpuCount)
+	for gpuId := uint(0); gpuId < gpuCount; gpuId++ {
+		wg.Add(1)
+		go func(gpuId uint) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				status, err := client.GetDeviceStatus(gpuId)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if status.Id != gpuId {
+					t.Errorf("GetDeviceStatus(%d) returned the status of gpu %d", gpuId, status.Id)
+				}
+				if _, err = client.HealthCheckByGpuId(gpuId); err != nil {
+					errs <- err
+					return
+				}
+				if _, err = client.GetDeviceInfo(gpuId); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(gpuId)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent call failed: %v", err)
+	}
+
+	if counts := client.GetResourceCounts(); counts != (ixdcgm.ResourceCounts{}) {
+		t.Errorf("GetResourceCounts after the concurrent calls = %+v", counts)
+	}
+	if n := fake.GroupCount(); n != 0 {
+		t.Errorf("%d groups left after the concurrent calls", n)
+	}
+}
+
+func TestCallsDuringCleanup(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	cleanup, err := ixdcgm.InitFake(fake)
+	if err != nil {
+		t.Fatalf("InitFake failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(gpuId uint) {
+			defer wg.Done()
+			<-start
+			for j := 0; j < 50; j++ {
+				_, err := ixdcgm.GetDeviceStatus(gpuId)
+				if err != nil && !errors.Is(err, ixdcgm.ErrUninitialized) {
+					t.Errorf("GetDeviceStatus during cleanup = %v, want nil or ErrUninitialized", err)
+					return
+				}
+			}
+		}(uint(i % 2))
+	}
+	close(start)
+	cleanup()
+	wg.Wait()
+
+	if _, err = ixdcgm.GetDeviceStatus(0); !errors.Is(err, ixdcgm.ErrUninitialized) {
+		t.Errorf("GetDeviceStatus after cleanup = %v, want ErrUninitialized", err)
+	}
+}
+
+func TestConcurrentPolicyListeners(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	params := &ixdcgm.PolicyConditionParams{XidPolicyEnabled: true}
+	var listeners [2]<-chan ixdcgm.PolicyViolation
+	for gpuId := range listeners {
+		client := ixdcgm.NewFakeClient(fake)
+		defer client.Close()
+		violations, err := client.ListenForPolicyViolationsForGPUs(ctx, params, uint(gpuId))
+		if err != nil {
+			t.Fatalf("ListenForPolicyViolationsForGPUs failed: %v", err)
+		}
+		listeners[gpuId] = violations
+	}
+
+	// fewer violations than the capacity of the channels, which are read once all are sent
+	const perGpu = 5
+	var wg sync.WaitGroup
+	for gpuId := range listeners {
+		wg.Add(1)
+		go func(gpuId uint) {
+			defer wg.Done()
+			for i := 0; i < perGpu; i++ {
+				fake.InjectPolicyViolation(ixdcgm.PolicyViolation{
+					Condition: ixdcgm.XidPolicy,
+					Data:      ixdcgm.XidPolicyCondition{ErrNum: uint(i), GpuId: gpuId},
+				})
+			}
+		}(uint(gpuId))
+	}
+	wg.Wait()
+
+	// each listener receives all the violations of its GPU and only them
+	for gpuId, violations := range listeners {
+		for i := 0; i < perGpu; i++ {
+			v := receiveViolation(t, violations)
+			if xid, ok := v.Data.(ixdcgm.XidPolicyCondition); !ok || xid.GpuId != uint(gpuId) {
+				t.Errorf("listener of gpu %d received %+v", gpuId, v)
+			}
+		}
+	}
+
+	cancel()
+	for _, violations := range listeners {
+		waitClosed(t, violations)
+	}
+}
+
+func TestClosedClient(t *testing.T) {
+	client := ixdcgm.NewFakeClient(ixdcgm.NewFakeBackend(1))
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if err := client.Close(); !errors.Is(err, ixdcgm.ErrUninitialized) {
+		t.Errorf("Close of a closed client = %v, want ErrUninitialized", err)
+	}
+	if _, err := client.Supervise(context.Background(), nil); !errors.Is(err, ixdcgm.ErrUninitialized) {
+		t.Errorf("Supervise of a closed client = %v, want ErrUninitialized", err)
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultHostengineAddress = "localhost:5777"
+
+// Config describes how IXDCGM is started and how to connect to the ix-hostengine
+type Config struct {
+	// Mode is the ix-hostengine running mode: Embedded, Standalone or StartHostengine.
+	Mode int
+
+	// Address is the ix-hostengine address used in Standalone mode.
+	// It is either "IP", "IP:PORT" or a unix socket filename if UnixSocket is set.
+	// Default is "localhost:5777".
+	Address string
+
+	// UnixSocket indicates whether Address is a unix socket filename (true) or a TCP/IP address (false).
+	UnixSocket bool
+
+	// Timeout is how long to wait when connecting to the ix-hostengine before giving up.
+	// Zero means the IXDCGM default.
+	Timeout time.Duration
+
+	// LogLevel is the severity the embedded ix-hostengine logs at. Default is LogNone.
+	// Only used in Embedded mode.
+	LogLevel LogSeverity
+
+	// LogFile is the file the embedded ix-hostengine logs to. Empty means the default log file.
+	// Only used in Embedded mode.
+	LogFile string
+
+	// DenyModules lists the modules the embedded ix-hostengine must not load.
+	// Only used in Embedded mode.
+	DenyModules []ModuleId
+
+	// HostengineArgs are extra command line flags for the ix-hostengine child process, which must not
+	// repeat the --no-daemon and --domain-socket flags it is always run with. Only used in StartHostengine mode.
+	HostengineArgs []string
+
+	// StartTimeout is how long to wait for the ix-hostengine child process to accept connections.
+	// Zero means 10s. Only used in StartHostengine mode.
+	StartTimeout time.Duration
+
+	// StopTimeout is how long the ix-hostengine child process is given to exit after SIGTERM
+	// before it is killed. Zero means 5s. Only used in StartHostengine mode.
+	StopTimeout time.Duration
+
+	// Logger, if set, replaces the logger of the package as SetLogger does.
+	Logger *slog.Logger
+
+	// VersionCheck selects whether the versions of the ix-hostengine and of the IX driver are
+	// checked for compatibility with the IXDCGM library once connected. Default is VersionCheckNone.
+	VersionCheck VersionCheck
+}
+
+// validate checks the config and fills in the defaults
+func (c *Config) validate() error {
+	switch c.Mode {
+	case Embedded, Standalone, StartHostengine:
+	default:
+		return fmt.Errorf("unknown mode: %d", c.Mode)
+	}
+
+	if c.Timeout < 0 {
+		return fmt.Errorf("invalid timeout %v: must not be negative", c.Timeout)
+	}
+	if c.Timeout.Milliseconds() > math.MaxUint32 {
+		return fmt.Errorf("invalid timeout %v: too large", c.Timeout)
+	}
+
+	if c.Mode == Standalone {
+		if c.Address == "" {
+			if c.UnixSocket {
+				return fmt.Errorf("missing unix socket filename of ix-hostengine")
+			}
+			c.Address = defaultHostengineAddress
+		}
+	} else if c.Address != "" || c.UnixSocket {
+		return fmt.Errorf("address is only supported in Standalone mode")
+	}
+
+	if c.VersionCheck < VersionCheckNone || c.VersionCheck > VersionCheckStrict {
+		return fmt.Errorf("invalid version check: %d", c.VersionCheck)
+	}
+
+	if c.Mode == StartHostengine {
+		if c.StartTimeout < 0 || c.StopTimeout < 0 {
+			return fmt.Errorf("invalid ix-hostengine start or stop timeout: must not be negative")
+		}
+		for _, arg := range c.HostengineArgs {
+			if name, _, _ := strings.Cut(arg, "="); name == "--no-daemon" || name == "--domain-socket" {
+				return fmt.Errorf("invalid ix-hostengine arg %s: it is set by the library", arg)
+			}
+		}
+	} else if len(c.HostengineArgs) > 0 || c.StartTimeout != 0 || c.StopTimeout != 0 {
+		return fmt.Errorf("ix-hostengine args and start or stop timeout are only supported in StartHostengine mode")
+	}
+
+	if c.Mode != Embedded {
+		if c.LogFile != "" {
+			return fmt.Errorf("log file is only supported in Embedded mode")
+		}
+		if len(c.DenyModules) > 0 {
+			return fmt.Errorf("module deny list is only supported in Embedded mode")
+		}
+		return nil
+	}
+
+	if !c.LogLevel.valid() {
+		return fmt.Errorf("invalid log level: %d", c.LogLevel)
+	}
+
+	seen := make(map[ModuleId]bool, len(c.DenyModules))
+	for _, id := range c.DenyModules {
+		if id >= moduleCount {
+			return fmt.Errorf("invalid module id: %d", id)
+		}
+		if id == ModuleCore {
+			return fmt.Errorf("core module cannot be added to the deny list")
+		}
+		if seen[id] {
+			return fmt.Errorf("duplicated module id in deny list: %d", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// configFromArgs converts the positional args of Init to a Config
+func configFromArgs(m int, args ...string) (Config, error) {
+	cfg := Config{Mode: m}
+
+	switch m {
+	case Embedded:
+		if len(args) > 0 {
+			sev, err := ParseLogSeverity(args[0])
+			if err != nil {
+				return cfg, err
+			}
+			cfg.LogLevel = sev
+		}
+	case Standalone:
+		if len(args) < 2 {
+			return cfg, fmt.Errorf("missing dcgm address or port")
+		}
+		sck, err := strconv.ParseInt(args[1], 10, 32)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to parse dcgm sock: %w", err)
+		}
+		cfg.Address = args[0]
+		cfg.UnixSocket = sck != 0
+	}
+	return cfg, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		want    Config
+		wantErr bool
+	}{
+		{name: "embedded", cfg: Config{Mode: Embedded, LogLevel: LogDebug, DenyModules: []ModuleId{ModuleDiag}},
+			want: Config{Mode: Embedded, LogLevel: LogDebug, DenyModules: []ModuleId{ModuleDiag}}},
+		{name: "default address", cfg: Config{Mode: Standalone}, want: Config{Mode: Standalone, Address: defaultHostengineAddress}},
+		{name: "unix socket", cfg: Config{Mode: Standalone, Address: "/tmp/sock", UnixSocket: true},
+			want: Config{Mode: Standalone, Address: "/tmp/sock", UnixSocket: true}},
+		{name: "hostengine args", cfg: Config{Mode: StartHostengine, HostengineArgs: []string{"--port=5555"}, StartTimeout: time.Second},
+			want: Config{Mode: StartHostengine, HostengineArgs: []string{"--port=5555"}, StartTimeout: time.Second}},
+		{name: "unknown mode", cfg: Config{Mode: 3}, wantErr: true},
+		{name: "negative timeout", cfg: Config{Mode: Embedded, Timeout: -time.Second}, wantErr: true},
+		{name: "too large timeout", cfg: Config{Mode: Embedded, Timeout: 50 * 24 * time.Hour}, wantErr: true},
+		{name: "missing unix socket", cfg: Config{Mode: Standalone, UnixSocket: true}, wantErr: true},
+		{name: "address not standalone", cfg: Config{Mode: Embedded, Address: "localhost"}, wantErr: true},
+		{name: "invalid version check", cfg: Config{Mode: Embedded, VersionCheck: VersionCheckStrict + 1}, wantErr: true},
+		{name: "negative start timeout", cfg: Config{Mode: StartHostengine, StartTimeout: -time.Second}, wantErr: true},
+		{name: "reserved hostengine arg", cfg: Config{Mode: StartHostengine, HostengineArgs: []string{"--domain-socket=/tmp/sock"}}, wantErr: true},
+		{name: "hostengine args not start hostengine", cfg: Config{Mode: Standalone, HostengineArgs: []string{"--port=5555"}}, wantErr: true},
+		{name: "log file not embedded", cfg: Config{Mode: Standalone, LogFile: "/tmp/log"}, wantErr: true},
+		{name: "deny modules not embedded", cfg: Config{Mode: Standalone, DenyModules: []ModuleId{ModuleDiag}}, wantErr: true},
+		{name: "invalid log level", cfg: Config{Mode: Embedded, LogLevel: LogVerb + 1}, wantErr: true},
+		{name: "invalid module", cfg: Config{Mode: Embedded, DenyModules: []ModuleId{moduleCount}}, wantErr: true},
+		{name: "core module", cfg: Config{Mode: Embedded, DenyModules: []ModuleId{ModuleCore}}, wantErr: true},
+		{name: "duplicated module", cfg: Config{Mode: Embedded, DenyModules: []ModuleId{ModuleDiag, ModuleDiag}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("validate() of %+v succeeded, want an error", tt.cfg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validate() failed: %v", err)
+			}
+			if !reflect.DeepEqual(tt.cfg, tt.want) {
+				t.Errorf("validated config = %+v, want %+v", tt.cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigFromArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    int
+		args    []string
+		want    Config
+		wantErr bool
+	}{
+		{name: "embedded", mode: Embedded, want: Config{Mode: Embedded}},
+		{name: "embedded log level", mode: Embedded, args: []string{"debug"}, want: Config{Mode: Embedded, LogLevel: LogDebug}},
+		{name: "invalid log level", mode: Embedded, args: []string{"loud"}, wantErr: true},
+		{name: "standalone", mode: Standalone, args: []string{"localhost:5555", "0"}, want: Config{Mode: Standalone, Address: "localhost:5555"}},
+		{name: "standalone unix socket", mode: Standalone, args: []string{"/tmp/sock", "1"},
+			want: Config{Mode: Standalone, Address: "/tmp/sock", UnixSocket: true}},
+		{name: "missing socket flag", mode: Standalone, args: []string{"localhost"}, wantErr: true},
+		{name: "invalid socket flag", mode: Standalone, args: []string{"localhost", "yes"}, wantErr: true},
+		{name: "start hostengine", mode: StartHostengine, args: []string{"ignored"}, want: Config{Mode: StartHostengine}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := configFromArgs(tt.mode, tt.args...)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("configFromArgs(%d, %q) = %+v, want an error", tt.mode, tt.args, cfg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("configFromArgs(%d, %q) failed: %v", tt.mode, tt.args, err)
+			}
+			if !reflect.DeepEqual(cfg, tt.want) {
+				t.Errorf("configFromArgs(%d, %q) = %+v, want %+v", tt.mode, tt.args, cfg, tt.want)
+			}
+		})
+	}
+}
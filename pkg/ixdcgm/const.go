@@ -25,13 +25,16 @@ import "C"
 
 type Short C.ushort
 
+// FieldValue_v1 is a value of a field, decoded from the 4 KiB dcgmFieldValue_v1 of the library
 type FieldValue_v1 struct {
 	Version   uint
 	FieldId   uint
 	FieldType uint
-	Status    int
+	Status    ReturnCode
 	Ts        int64
-	Value     [4096]byte
+
+	num  uint64 // bits of an int64, timestamp or double value
+	data []byte // string up to its NUL or binary value, referencing the buffer of the library
 }
 
 const (
@@ -475,62 +478,121 @@ const (
 	DCGM_FI_DEV_CPU_VENDOR                          = 1140
 	DCGM_FI_DEV_CPU_MODEL                           = 1141
 	DCGM_FI_MAX_FIELDS                              = 1142
+)
+
+// Return codes of IXDCGM, see ReturnCode
+const (
+	DCGM_ST_OK                          ReturnCode = 0
+	DCGM_ST_BADPARAM                    ReturnCode = -1
+	DCGM_ST_GENERIC_ERROR               ReturnCode = -3
+	DCGM_ST_MEMORY                      ReturnCode = -4
+	DCGM_ST_NOT_CONFIGURED              ReturnCode = -5
+	DCGM_ST_NOT_SUPPORTED               ReturnCode = -6
+	DCGM_ST_INIT_ERROR                  ReturnCode = -7
+	DCGM_ST_NVML_ERROR                  ReturnCode = -8
+	DCGM_ST_PENDING                     ReturnCode = -9
+	DCGM_ST_UNINITIALIZED               ReturnCode = -10
+	DCGM_ST_TIMEOUT                     ReturnCode = -11
+	DCGM_ST_VER_MISMATCH                ReturnCode = -12
+	DCGM_ST_UNKNOWN_FIELD               ReturnCode = -13
+	DCGM_ST_NO_DATA                     ReturnCode = -14
+	DCGM_ST_STALE_DATA                  ReturnCode = -15
+	DCGM_ST_NOT_WATCHED                 ReturnCode = -16
+	DCGM_ST_NO_PERMISSION               ReturnCode = -17
+	DCGM_ST_GPU_IS_LOST                 ReturnCode = -18
+	DCGM_ST_RESET_REQUIRED              ReturnCode = -19
+	DCGM_ST_FUNCTION_NOT_FOUND          ReturnCode = -20
+	DCGM_ST_CONNECTION_NOT_VALID        ReturnCode = -21
+	DCGM_ST_GPU_NOT_SUPPORTED           ReturnCode = -22
+	DCGM_ST_GROUP_INCOMPATIBLE          ReturnCode = -23
+	DCGM_ST_MAX_LIMIT                   ReturnCode = -24
+	DCGM_ST_LIBRARY_NOT_FOUND           ReturnCode = -25
+	DCGM_ST_DUPLICATE_KEY               ReturnCode = -26
+	DCGM_ST_GPU_IN_SYNC_BOOST_GROUP     ReturnCode = -27
+	DCGM_ST_GPU_NOT_IN_SYNC_BOOST_GROUP ReturnCode = -28
+	DCGM_ST_REQUIRES_ROOT               ReturnCode = -29
+	DCGM_ST_NVVS_ERROR                  ReturnCode = -30
+	DCGM_ST_INSUFFICIENT_SIZE           ReturnCode = -31
+	DCGM_ST_FIELD_UNSUPPORTED_BY_API    ReturnCode = -32
+	DCGM_ST_MODULE_NOT_LOADED           ReturnCode = -33
+	DCGM_ST_IN_USE                      ReturnCode = -34
+	DCGM_ST_GROUP_IS_EMPTY              ReturnCode = -35
+	DCGM_ST_PROFILING_NOT_SUPPORTED     ReturnCode = -36
+	DCGM_ST_PROFILING_LIBRARY_ERROR     ReturnCode = -37
+	DCGM_ST_PROFILING_MULTI_PASS        ReturnCode = -38
+	DCGM_ST_DIAG_ALREADY_RUNNING        ReturnCode = -39
+	DCGM_ST_DIAG_BAD_JSON               ReturnCode = -40
+	DCGM_ST_DIAG_BAD_LAUNCH             ReturnCode = -41
+	DCGM_ST_DIAG_UNUSED                 ReturnCode = -42
+	DCGM_ST_DIAG_THRESHOLD_EXCEEDED     ReturnCode = -43
+	DCGM_ST_INSUFFICIENT_DRIVER_VERSION ReturnCode = -44
+	DCGM_ST_INSTANCE_NOT_FOUND          ReturnCode = -45
+	DCGM_ST_COMPUTE_INSTANCE_NOT_FOUND  ReturnCode = -46
+	DCGM_ST_CHILD_NOT_KILLED            ReturnCode = -47
+	DCGM_ST_3RD_PARTY_LIBRARY_ERROR     ReturnCode = -48
+	DCGM_ST_INSUFFICIENT_RESOURCES      ReturnCode = -49
+	DCGM_ST_PLUGIN_EXCEPTION            ReturnCode = -50
+	DCGM_ST_NVVS_ISOLATE_ERROR          ReturnCode = -51
+	DCGM_ST_NVVS_BINARY_NOT_FOUND       ReturnCode = -52
+	DCGM_ST_NVVS_KILLED                 ReturnCode = -53
+	DCGM_ST_PAUSED                      ReturnCode = -54
+	DCGM_ST_ALREADY_INITIALIZED         ReturnCode = -55
 
-	DCGM_ST_OK                          = 0
-	DCGM_ST_BADPARAM                    = -1
-	DCGM_ST_GENERIC_ERROR               = -3
-	DCGM_ST_MEMORY                      = -4
-	DCGM_ST_NOT_CONFIGURED              = -5
-	DCGM_ST_NOT_SUPPORTED               = -6
-	DCGM_ST_INIT_ERROR                  = -7
-	DCGM_ST_NVML_ERROR                  = -8
-	DCGM_ST_PENDING                     = -9
-	DCGM_ST_UNINITIALIZED               = -10
-	DCGM_ST_TIMEOUT                     = -11
-	DCGM_ST_VER_MISMATCH                = -12
-	DCGM_ST_UNKNOWN_FIELD               = -13
-	DCGM_ST_NO_DATA                     = -14
-	DCGM_ST_STALE_DATA                  = -15
-	DCGM_ST_NOT_WATCHED                 = -16
-	DCGM_ST_NO_PERMISSION               = -17
-	DCGM_ST_GPU_IS_LOST                 = -18
-	DCGM_ST_RESET_REQUIRED              = -19
-	DCGM_ST_FUNCTION_NOT_FOUND          = -20
-	DCGM_ST_CONNECTION_NOT_VALID        = -21
-	DCGM_ST_GPU_NOT_SUPPORTED           = -22
-	DCGM_ST_GROUP_INCOMPATIBLE          = -23
-	DCGM_ST_MAX_LIMIT                   = -24
-	DCGM_ST_LIBRARY_NOT_FOUND           = -25
-	DCGM_ST_DUPLICATE_KEY               = -26
-	DCGM_ST_GPU_IN_SYNC_BOOST_GROUP     = -27
-	DCGM_ST_GPU_NOT_IN_SYNC_BOOST_GROUP = -28
-	DCGM_ST_REQUIRES_ROOT               = -29
-	DCGM_ST_NVVS_ERROR                  = -30
-	DCGM_ST_INSUFFICIENT_SIZE           = -31
-	DCGM_ST_FIELD_UNSUPPORTED_BY_API    = -32
-	DCGM_ST_MODULE_NOT_LOADED           = -33
-	DCGM_ST_IN_USE                      = -34
-	DCGM_ST_GROUP_IS_EMPTY              = -35
-	DCGM_ST_PROFILING_NOT_SUPPORTED     = -36
-	DCGM_ST_PROFILING_LIBRARY_ERROR     = -37
-	DCGM_ST_PROFILING_MULTI_PASS        = -38
-	DCGM_ST_DIAG_ALREADY_RUNNING        = -39
-	DCGM_ST_DIAG_BAD_JSON               = -40
-	DCGM_ST_DIAG_BAD_LAUNCH             = -41
-	DCGM_ST_DIAG_UNUSED                 = -42
-	DCGM_ST_DIAG_THRESHOLD_EXCEEDED     = -43
-	DCGM_ST_INSUFFICIENT_DRIVER_VERSION = -44
-	DCGM_ST_INSTANCE_NOT_FOUND          = -45
-	DCGM_ST_COMPUTE_INSTANCE_NOT_FOUND  = -46
-	DCGM_ST_CHILD_NOT_KILLED            = -47
-	DCGM_ST_3RD_PARTY_LIBRARY_ERROR     = -48
-	DCGM_ST_INSUFFICIENT_RESOURCES      = -49
-	DCGM_ST_PLUGIN_EXCEPTION            = -50
-	DCGM_ST_NVVS_ISOLATE_ERROR          = -51
-	DCGM_ST_NVVS_BINARY_NOT_FOUND       = -52
-	DCGM_ST_NVVS_KILLED                 = -53
-	DCGM_ST_PAUSED                      = -54
-	DCGM_ST_ALREADY_INITIALIZED         = -55
+	IXDCGM_RET_OK                          ReturnCode = 0
+	IXDCGM_RET_BADPARAM                    ReturnCode = -1
+	IXDCGM_RET_GENERIC_ERROR               ReturnCode = -3
+	IXDCGM_RET_MEMORY                      ReturnCode = -4
+	IXDCGM_RET_NOT_CONFIGURED              ReturnCode = -5
+	IXDCGM_RET_NOT_SUPPORTED               ReturnCode = -6
+	IXDCGM_RET_INIT_ERROR                  ReturnCode = -7
+	IXDCGM_RET_NVML_ERROR                  ReturnCode = -8
+	IXDCGM_RET_PENDING                     ReturnCode = -9
+	IXDCGM_RET_UNINITIALIZED               ReturnCode = -10
+	IXDCGM_RET_TIMEOUT                     ReturnCode = -11
+	IXDCGM_RET_VER_MISMATCH                ReturnCode = -12
+	IXDCGM_RET_UNKNOWN_FIELD               ReturnCode = -13
+	IXDCGM_RET_NO_DATA                     ReturnCode = -14
+	IXDCGM_RET_STALE_DATA                  ReturnCode = -15
+	IXDCGM_RET_NOT_WATCHED                 ReturnCode = -16
+	IXDCGM_RET_NO_PERMISSION               ReturnCode = -17
+	IXDCGM_RET_GPU_IS_LOST                 ReturnCode = -18
+	IXDCGM_RET_RESET_REQUIRED              ReturnCode = -19
+	IXDCGM_RET_FUNCTION_NOT_FOUND          ReturnCode = -20
+	IXDCGM_RET_CONNECTION_NOT_VALID        ReturnCode = -21
+	IXDCGM_RET_GPU_NOT_SUPPORTED           ReturnCode = -22
+	IXDCGM_RET_GROUP_INCOMPATIBLE          ReturnCode = -23
+	IXDCGM_RET_MAX_LIMIT                   ReturnCode = -24
+	IXDCGM_RET_LIBRARY_NOT_FOUND           ReturnCode = -25
+	IXDCGM_RET_DUPLICATE_KEY               ReturnCode = -26
+	IXDCGM_RET_GPU_IN_SYNC_BOOST_GROUP     ReturnCode = -27
+	IXDCGM_RET_GPU_NOT_IN_SYNC_BOOST_GROUP ReturnCode = -28
+	IXDCGM_RET_REQUIRES_ROOT               ReturnCode = -29
+	IXDCGM_RET_IXVS_ERROR                  ReturnCode = -30
+	IXDCGM_RET_INSUFFICIENT_SIZE           ReturnCode = -31
+	IXDCGM_RET_FIELD_UNSUPPORTED_BY_API    ReturnCode = -32
+	IXDCGM_RET_MODULE_NOT_LOADED           ReturnCode = -33
+	IXDCGM_RET_IN_USE                      ReturnCode = -34
+	IXDCGM_RET_GROUP_IS_EMPTY              ReturnCode = -35
+	IXDCGM_RET_PROFILING_NOT_SUPPORTED     ReturnCode = -36
+	IXDCGM_RET_PROFILING_LIBRARY_ERROR     ReturnCode = -37
+	IXDCGM_RET_PROFILING_MULTI_PASS        ReturnCode = -38
+	IXDCGM_RET_DIAG_ALREADY_RUNNING        ReturnCode = -39
+	IXDCGM_RET_DIAG_BAD_JSON               ReturnCode = -40
+	IXDCGM_RET_DIAG_BAD_LAUNCH             ReturnCode = -41
+	IXDCGM_RET_DIAG_UNUSED                 ReturnCode = -42
+	IXDCGM_RET_DIAG_THRESHOLD_EXCEEDED     ReturnCode = -43
+	IXDCGM_RET_INSUFFICIENT_DRIVER_VERSION ReturnCode = -44
+	IXDCGM_RET_INSTANCE_NOT_FOUND          ReturnCode = -45
+	IXDCGM_RET_COMPUTE_INSTANCE_NOT_FOUND  ReturnCode = -46
+	IXDCGM_RET_CHILD_NOT_KILLED            ReturnCode = -47
+	IXDCGM_RET_3RD_PARTY_LIBRARY_ERROR     ReturnCode = -48
+	IXDCGM_RET_INSUFFICIENT_RESOURCES      ReturnCode = -49
+	IXDCGM_RET_PLUGIN_EXCEPTION            ReturnCode = -50
+	IXDCGM_RET_IXVS_ISOLATE_ERROR          ReturnCode = -51
+	IXDCGM_RET_IXVS_BINARY_NOT_FOUND       ReturnCode = -52
+	IXDCGM_RET_IXVS_KILLED                 ReturnCode = -53
+	IXDCGM_RET_PAUSED                      ReturnCode = -54
+	IXDCGM_RET_ALREADY_INITIALIZED         ReturnCode = -55
 )
 
 var DCGM_FI = map[string]Short{
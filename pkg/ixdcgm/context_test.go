@@ -0,0 +1,122 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestGetDeviceProfStatusCtxDeadline(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(1)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.GetDeviceProfStatusCtx(ctx, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetDeviceProfStatusCtx = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetDeviceProfStatusCtx returned after %v", elapsed)
+	}
+
+	if n := fake.GroupCount(); n != 0 {
+		t.Errorf("%d groups left after GetDeviceProfStatusCtx", n)
+	}
+	if n := fake.FieldGroupCount(); n != 0 {
+		t.Errorf("%d field groups left after GetDeviceProfStatusCtx", n)
+	}
+}
+
+func TestCtxCancelled(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := map[string]func() error{
+		"GetDeviceInfoCtx": func() error {
+			_, err := client.GetDeviceInfoCtx(ctx, 0)
+			return err
+		},
+		"GetDeviceStatusCtx": func() error {
+			_, err := client.GetDeviceStatusCtx(ctx, 0)
+			return err
+		},
+		"GetDeviceRunningProcessesCtx": func() error {
+			_, err := client.GetDeviceRunningProcessesCtx(ctx, 0)
+			return err
+		},
+		"GetDeviceTopologyCtx": func() error {
+			_, err := client.GetDeviceTopologyCtx(ctx, 0)
+			return err
+		},
+		"HealthCheckByGpuIdCtx": func() error {
+			_, err := client.HealthCheckByGpuIdCtx(ctx, 0)
+			return err
+		},
+		"RunDiagCtx": func() error {
+			_, err := client.RunDiagCtx(ctx, ixdcgm.DiagQuick, ixdcgm.GroupAllGPUs())
+			return err
+		},
+		"WatchFieldsCtx": func() error {
+			fieldGrp, err := client.FieldGroupCreate("ctxFields", []ixdcgm.Short{ixdcgm.DCGM_FI_DEV_GPU_TEMP})
+			if err != nil {
+				return err
+			}
+			defer client.FieldGroupDestroy(fieldGrp)
+			_, err = client.WatchFieldsCtx(ctx, []uint{0, 1}, fieldGrp, "ctxGroup")
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s with a cancelled context = %v, want Canceled", name, err)
+		}
+	}
+
+	if n := fake.GroupCount(); n != 0 {
+		t.Errorf("%d groups left after the cancelled calls", n)
+	}
+	if n := fake.FieldGroupCount(); n != 0 {
+		t.Errorf("%d field groups left after the cancelled calls", n)
+	}
+}
+
+func TestRunDiagWithTimeout(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	results, err := client.RunDiagWithTimeout(ixdcgm.DiagQuick, ixdcgm.GroupAllGPUs(), time.Second)
+	if err != nil {
+		t.Fatalf("RunDiagWithTimeout failed: %v", err)
+	}
+	if len(results.PerGpu) != 2 {
+		t.Errorf("RunDiagWithTimeout returned the results of %d GPUs, want 2", len(results.PerGpu))
+	}
+}
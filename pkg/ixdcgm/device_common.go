@@ -22,12 +22,17 @@ package ixdcgm
 #include "include/ixdcgmApiExport.h"
 */
 import "C"
+import "fmt"
 
-func getDeviceOnSameBoard(gpuId1, gpuId2 uint) (isOnSameBoard bool, err error) {
+func (c *Client) getDeviceOnSameBoard(gpuId1, gpuId2 uint) (bool, error) {
+	return c.backend().getDeviceOnSameBoard(gpuId1, gpuId2)
+}
+
+func (b cgoBackend) getDeviceOnSameBoard(gpuId1, gpuId2 uint) (isOnSameBoard bool, err error) {
 	var onSameBoard C.int
-	ret := C.ixdcgmDeviceOnSameBoard(C.ulong(handle.handle), C.uint(gpuId1), C.uint(gpuId2), &onSameBoard)
+	ret := C.ixdcgmDeviceOnSameBoard(C.ulong(b.handle), C.uint(gpuId1), C.uint(gpuId2), &onSameBoard)
 	if err = ixdcgmErrorString(ret); err != nil {
-		return false, err
+		return false, fmt.Errorf("Error checking whether gpu %d and gpu %d are on the same board: %w", gpuId1, gpuId2, err)
 	}
 	if onSameBoard == 0 {
 		isOnSameBoard = false
@@ -23,8 +23,8 @@ package ixdcgm
 */
 import "C"
 import (
+	"context"
 	"fmt"
-	"math/rand"
 	"unsafe"
 
 	"github.com/bits-and-blooms/bitset"
@@ -64,20 +64,31 @@ type DeviceInfo struct {
 	NUMAAffinity    string
 }
 
-func getAllDeviceCount() (gpuCount uint, err error) {
+func (c *Client) getAllDeviceCount() (gpuCount uint, err error) {
+	gpus, err := c.backend().getAllDevices()
+	if err != nil {
+		return gpuCount, err
+	}
+	return uint(len(gpus)), nil
+}
+
+func (b cgoBackend) getAllDevices() (gpus []uint, err error) {
 	var gpuIdList [C.DCGM_MAX_NUM_DEVICES]C.uint
 	var count C.int
 
-	r := C.dcgmGetAllDevices(C.ulong(handle.handle), &gpuIdList[0], &count)
+	r := C.dcgmGetAllDevices(C.ulong(b.handle), &gpuIdList[0], &count)
 	if err = errorString(r); err != nil {
-		return gpuCount, err
+		return gpus, fmt.Errorf("Error getting device count: %w", err)
 	}
 
-	gpuCount = uint(count)
+	gpus = make([]uint, int(count))
+	for i := range gpus {
+		gpus[i] = uint(gpuIdList[i])
+	}
 	return
 }
 
-func getPciBandwidth(gpuId uint) (int64, error) {
+func (c *Client) getPciBandwidth(ctx context.Context, gpuId uint) (int64, error) {
 	const (
 		maxLinkGen int = iota
 		maxLinkWidth
@@ -89,24 +100,15 @@ func getPciBandwidth(gpuId uint) (int64, error) {
 		C.DCGM_FI_DEV_PCIE_MAX_LINK_WIDTH,
 	}
 
-	fieldName := fmt.Sprintf("pciBandwidthFields%d", gpuId)
-	fieldsId, err := FieldGroupCreate(fieldName, pciFields)
+	release, err := c.watchTemporary(ctx, "pciBandwidth", []uint{gpuId}, pciFields)
 	if err != nil {
 		return 0, err
 	}
+	defer release()
 
-	groupName := fmt.Sprintf("pciBandwidth%d", gpuId)
-	groupId, err := WatchFields([]uint{gpuId}, fieldsId, groupName)
+	values, err := c.GetLatestValuesForFieldsCtx(ctx, gpuId, pciFields)
 	if err != nil {
-		FieldGroupDestroy(fieldsId)
-		return 0, err
-	}
-
-	values, err := GetLatestValuesForFields(gpuId, pciFields)
-	if err != nil {
-		FieldGroupDestroy(fieldsId)
-		DestroyGroup(groupId)
-		return 0, fmt.Errorf("failed to get pcie bandwidgth: %s", err)
+		return 0, fmt.Errorf("failed to get pcie bandwidgth: %w", err)
 	}
 
 	var genMap = map[int64]int64{
@@ -116,9 +118,6 @@ func getPciBandwidth(gpuId uint) (int64, error) {
 		4: 1969,
 	}
 
-	FieldGroupDestroy(fieldsId)
-	DestroyGroup(groupId)
-
 	gen := values[maxLinkGen].Int64()
 	width := values[maxLinkWidth].Int64()
 
@@ -126,17 +125,17 @@ func getPciBandwidth(gpuId uint) (int64, error) {
 	return bandwidth, nil
 }
 
-func getDeviceInfo(gpuId uint) (DeviceInfo, error) {
-	var dcgmAttr C.dcgmDeviceAttributes_t
-	dcgmAttr.version = C.uint(makeVersion3(unsafe.Sizeof(dcgmAttr)))
-
-	res := C.dcgmGetDeviceAttributes(C.ulong(handle.handle), C.uint(gpuId), &dcgmAttr)
-	if err := errorString(res); err != nil {
+func (c *Client) getDeviceInfo(ctx context.Context, gpuId uint) (DeviceInfo, error) {
+	b := c.backend()
+	attr, err := callCtx(ctx, func() (deviceAttributes, error) {
+		return b.getDeviceAttributes(gpuId)
+	})
+	if err != nil {
 		return DeviceInfo{}, err
 	}
 
 	// check if the given GPU is IxDCGM supported
-	gpus, err := getSupportedDevices()
+	gpus, err := callCtx(ctx, b.getSupportedDevices)
 	if err != nil {
 		return DeviceInfo{}, err
 	}
@@ -149,54 +148,53 @@ func getDeviceInfo(gpuId uint) (DeviceInfo, error) {
 		}
 	}
 
-	cpuAffinity, err := getAffinity(gpuId, "CPU")
+	cpuAffinity, err := c.getAffinity(ctx, gpuId, "CPU")
 	if err != nil {
-		fmt.Printf("Error getting cpu affinity, set CPU Affinity to N/A, err: %s", err)
+		getLogger().Warn("Error getting cpu affinity, set CPU Affinity to N/A", "gpuId", gpuId, "error", err)
 	}
-	numaAffinity, err := getAffinity(gpuId, "NUMA")
+	numaAffinity, err := c.getAffinity(ctx, gpuId, "NUMA")
 	if err != nil {
-		fmt.Printf("Error getting numa affinity, set NUMA Affinity to N/A, err: %s", err)
+		getLogger().Warn("Error getting numa affinity, set NUMA Affinity to N/A", "gpuId", gpuId, "error", err)
+	}
+	if err = ctx.Err(); err != nil {
+		return DeviceInfo{}, err
 	}
 
 	var topology []P2PLink
 	var bandwidth int64
 	if supported == "Y" {
-		topology, err = getDeviceTopology(gpuId)
+		topology, err = c.getDeviceTopology(ctx, gpuId)
 		if err != nil {
 			return DeviceInfo{}, err
 		}
-		bandwidth, err = getPciBandwidth(gpuId)
+		bandwidth, err = c.getPciBandwidth(ctx, gpuId)
 		if err != nil {
 			return DeviceInfo{}, err
 		}
 	}
 
-	uuid := cChar2String(&dcgmAttr.identifiers.uuid[0])
-	powerLimit := uint(dcgmAttr.powerLimits.defaultPowerLimit)
-	busId := cChar2String(&dcgmAttr.identifiers.pciBusId[0])
-
 	pci := PciInfo{
-		BusId:     busId,
+		BusId:     attr.BusId,
 		Bandwidth: bandwidth,
 	}
 
 	id := DeviceIdentifier{
-		ProductName:   cChar2String(&dcgmAttr.identifiers.deviceName[0]),
-		Serial:        cChar2String(&dcgmAttr.identifiers.serial[0]),
-		DriverVersion: cChar2String(&dcgmAttr.identifiers.driverVersion[0]),
+		ProductName:   attr.DeviceName,
+		Serial:        attr.Serial,
+		DriverVersion: attr.DriverVersion,
 	}
 
 	memInfo := MemoryUsageInfo{
-		Total: uint(dcgmAttr.memoryUsage.fbTotal),
-		Used:  uint(dcgmAttr.memoryUsage.fbUsed),
-		Free:  uint(dcgmAttr.memoryUsage.fbFree),
+		Total: attr.FbTotal,
+		Used:  attr.FbUsed,
+		Free:  attr.FbFree,
 	}
 
 	return DeviceInfo{
 		GPUId:           gpuId,
 		IxDCGMSupported: supported,
-		Uuid:            uuid,
-		PowerLimit:      powerLimit,
+		Uuid:            attr.Uuid,
+		PowerLimit:      attr.PowerLimit,
 		PCI:             pci,
 		MemoryUsage:     memInfo,
 		Identifiers:     id,
@@ -206,13 +204,52 @@ func getDeviceInfo(gpuId uint) (DeviceInfo, error) {
 	}, nil
 }
 
-func getSupportedDevices() (gpus []uint, err error) {
+// deviceAttributes holds the attributes of a device used by the package
+type deviceAttributes struct {
+	Uuid          string
+	BusId         string
+	DeviceName    string
+	Serial        string
+	DriverVersion string
+	PowerLimit    uint // W, default power limit
+	FbTotal       uint // MB
+	FbUsed        uint // MB
+	FbFree        uint // MB
+}
+
+func (b cgoBackend) getDeviceAttributes(gpuId uint) (deviceAttributes, error) {
+	var dcgmAttr C.dcgmDeviceAttributes_t
+	dcgmAttr.version = C.uint(makeVersion3(unsafe.Sizeof(dcgmAttr)))
+
+	res := C.dcgmGetDeviceAttributes(C.ulong(b.handle), C.uint(gpuId), &dcgmAttr)
+	if err := errorString(res); err != nil {
+		return deviceAttributes{}, fmt.Errorf("Error getting attributes of gpu %d: %w", gpuId, err)
+	}
+
+	return deviceAttributes{
+		Uuid:          cChar2String(&dcgmAttr.identifiers.uuid[0]),
+		BusId:         cChar2String(&dcgmAttr.identifiers.pciBusId[0]),
+		DeviceName:    cChar2String(&dcgmAttr.identifiers.deviceName[0]),
+		Serial:        cChar2String(&dcgmAttr.identifiers.serial[0]),
+		DriverVersion: cChar2String(&dcgmAttr.identifiers.driverVersion[0]),
+		PowerLimit:    uint(dcgmAttr.powerLimits.defaultPowerLimit),
+		FbTotal:       uint(dcgmAttr.memoryUsage.fbTotal),
+		FbUsed:        uint(dcgmAttr.memoryUsage.fbUsed),
+		FbFree:        uint(dcgmAttr.memoryUsage.fbFree),
+	}, nil
+}
+
+func (c *Client) getSupportedDevices() ([]uint, error) {
+	return c.backend().getSupportedDevices()
+}
+
+func (b cgoBackend) getSupportedDevices() (gpus []uint, err error) {
 	var gpuIdList [C.DCGM_MAX_NUM_DEVICES]C.uint
 	var count C.int
 
-	r := C.dcgmGetAllSupportedDevices(C.ulong(handle.handle), &gpuIdList[0], &count)
+	r := C.dcgmGetAllSupportedDevices(C.ulong(b.handle), &gpuIdList[0], &count)
 	if err = errorString(r); err != nil {
-		return gpus, err
+		return gpus, fmt.Errorf("Error getting supported devices: %w", err)
 	}
 	numGpus := uint(count)
 	gpus = make([]uint, numGpus)
@@ -222,68 +259,64 @@ func getSupportedDevices() (gpus []uint, err error) {
 	return
 }
 
-// if err is not nil, return "N/A" as result
-func getAffinity(gpuId uint, typ string) (result string, err error) {
-	const (
-		affinity0 int = iota
-		affinity1
-		affinity2
-		affinity3
-		fieldsCount
-	)
-
-	affFields := make([]Short, fieldsCount)
+// affinityFields are the fields of the CPU or NUMA affinity mask of a GPU, in the order of the words of the mask
+func affinityFields(typ string) ([]Short, error) {
 	switch typ {
 	case "CPU":
-		affFields[affinity0] = DCGM_FI_DEV_CPU_AFFINITY_0
-		affFields[affinity1] = DCGM_FI_DEV_CPU_AFFINITY_1
-		affFields[affinity2] = DCGM_FI_DEV_CPU_AFFINITY_2
-		affFields[affinity3] = DCGM_FI_DEV_CPU_AFFINITY_3
+		return []Short{
+			DCGM_FI_DEV_CPU_AFFINITY_0, DCGM_FI_DEV_CPU_AFFINITY_1, DCGM_FI_DEV_CPU_AFFINITY_2, DCGM_FI_DEV_CPU_AFFINITY_3,
+		}, nil
 	case "NUMA":
-		affFields[affinity0] = DCGM_FI_DEV_MEM_AFFINITY_0
-		affFields[affinity1] = DCGM_FI_DEV_MEM_AFFINITY_1
-		affFields[affinity2] = DCGM_FI_DEV_MEM_AFFINITY_2
-		affFields[affinity3] = DCGM_FI_DEV_MEM_AFFINITY_3
-	default:
-		return "N/A", fmt.Errorf("not supported affinity type: %s", typ)
+		return []Short{
+			DCGM_FI_DEV_MEM_AFFINITY_0, DCGM_FI_DEV_MEM_AFFINITY_1, DCGM_FI_DEV_MEM_AFFINITY_2, DCGM_FI_DEV_MEM_AFFINITY_3,
+		}, nil
 	}
+	return nil, fmt.Errorf("not supported affinity type: %s", typ)
+}
 
-	fieldGrpName := fmt.Sprintf("%sAffFields%d", typ, rand.Uint64())
-	fieldGrpHdl, err := FieldGroupCreate(fieldGrpName, affFields)
-	if err != nil {
-		return "N/A", err
+// affinityMask decodes the values of affinityFields, it returns false if a value is invalid
+func affinityMask(values []FieldValue_v1) (*bitset.BitSet, bool) {
+	ubits := make([]uint64, len(values))
+	for i, fv := range values {
+		bit, ok := fv.TypedValue().Int64()
+		if !ok {
+			// Retrieved affinity value is invalid.
+			return nil, false
+		}
+		ubits[i] = uint64(bit)
 	}
-	defer FieldGroupDestroy(fieldGrpHdl)
+	return bitset.From(ubits), true
+}
 
-	gpuGrpName := fmt.Sprintf("%sAff%d", typ, rand.Uint64())
-	gpuGrpHdl, err := WatchFields([]uint{gpuId}, fieldGrpHdl, gpuGrpName)
+// affinityString formats an affinity mask as ranges, e.g. "0-19,40-59", "N/A" if it is empty
+func affinityString(mask *bitset.BitSet) string {
+	if mask.None() {
+		return "N/A"
+	}
+	return convertBitsetStr(mask.String())
+}
+
+// if err is not nil, return "N/A" as result
+func (c *Client) getAffinity(ctx context.Context, gpuId uint, typ string) (result string, err error) {
+	affFields, err := affinityFields(typ)
 	if err != nil {
 		return "N/A", err
 	}
-	defer DestroyGroup(gpuGrpHdl)
 
-	values, err := GetLatestValuesForFields(gpuId, affFields)
+	release, err := c.watchTemporary(ctx, typ+"Aff", []uint{gpuId}, affFields)
 	if err != nil {
-		return "N/A", fmt.Errorf("Error getting %s affinity: %s", typ, err)
+		return "N/A", err
 	}
+	defer release()
 
-	bits := make([]int64, 4)
-	bits[0] = values[affinity0].Int64()
-	bits[1] = values[affinity1].Int64()
-	bits[2] = values[affinity2].Int64()
-	bits[3] = values[affinity3].Int64()
-	for _, bit := range bits {
-		if bit >= DCGM_FT_INT64_BLANK {
-			// Retrieved affinity value is invalid.
-			return "N/A", nil
-		}
+	values, err := c.GetLatestValuesForFieldsCtx(ctx, gpuId, affFields)
+	if err != nil {
+		return "N/A", fmt.Errorf("Error getting %s affinity: %w", typ, err)
 	}
 
-	ubits := make([]uint64, len(bits))
-	for i, val := range bits {
-		ubits[i] = uint64(val)
+	mask, valid := affinityMask(values)
+	if !valid {
+		return "N/A", nil
 	}
-	b := bitset.From(ubits)
-	str := convertBitsetStr(b.String())
-	return str, nil
+	return affinityString(mask), nil
 }
@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"reflect"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestGetDeviceInfo(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	err := fake.SetGPU(0, ixdcgm.FakeGPU{
+		Uuid:          "GPU-6d2e1a3f-0000-0000-0000-000000000000",
+		BusId:         "00000000:8A:00.0",
+		Name:          "Iluvatar BI-V150",
+		Serial:        "SN0001",
+		DriverVersion: "4.2.0",
+		PowerLimit:    350,
+		MemoryTotal:   65536,
+		MemoryUsed:    4096,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.SetP2PLink(0, 1, ixdcgm.P2PLinkIXLINK1)
+	fake.SetFieldInt64(0, ixdcgm.DCGM_FI_DEV_PCIE_MAX_LINK_GEN, 4)
+	fake.SetFieldInt64(0, ixdcgm.DCGM_FI_DEV_PCIE_MAX_LINK_WIDTH, 16)
+	fake.SetFieldInt64(0, ixdcgm.DCGM_FI_DEV_CPU_AFFINITY_0, 0xf0f)
+	fake.SetFieldInt64(0, ixdcgm.DCGM_FI_DEV_CPU_AFFINITY_1, 0)
+	fake.SetFieldInt64(0, ixdcgm.DCGM_FI_DEV_CPU_AFFINITY_2, 0)
+	fake.SetFieldInt64(0, ixdcgm.DCGM_FI_DEV_CPU_AFFINITY_3, 0)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	info, err := client.GetDeviceInfo(0)
+	if err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+
+	want := ixdcgm.DeviceInfo{
+		GPUId:           0,
+		IxDCGMSupported: "Y",
+		Uuid:            "GPU-6d2e1a3f-0000-0000-0000-000000000000",
+		PowerLimit:      350,
+		PCI:             ixdcgm.PciInfo{BusId: "00000000:8A:00.0", Bandwidth: 1969 * 16},
+		MemoryUsage:     ixdcgm.MemoryUsageInfo{Total: 65536, Used: 4096, Free: 61440},
+		Identifiers: ixdcgm.DeviceIdentifier{
+			ProductName:   "Iluvatar BI-V150",
+			Serial:        "SN0001",
+			DriverVersion: "4.2.0",
+		},
+		Topology:     []ixdcgm.P2PLink{{GPU: 1, BusID: "00000000:8A:00.0", Link: ixdcgm.P2PLinkIXLINK1}},
+		CPUAffinity:  "0-3,8-11",
+		NUMAAffinity: "N/A",
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("GetDeviceInfo = %+v, want %+v", info, want)
+	}
+
+	if n := fake.GroupCount(); n != 0 {
+		t.Errorf("%d groups left after GetDeviceInfo", n)
+	}
+	if n := fake.FieldGroupCount(); n != 0 {
+		t.Errorf("%d field groups left after GetDeviceInfo", n)
+	}
+}
+
+func TestGetDeviceInfoUnsupportedGpu(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	if err := fake.SetGPU(1, ixdcgm.FakeGPU{Uuid: "GPU-1", Unsupported: true}); err != nil {
+		t.Fatal(err)
+	}
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	count, err := client.GetAllDeviceCount()
+	if err != nil || count != 2 {
+		t.Errorf("GetAllDeviceCount = %d, %v, want 2", count, err)
+	}
+	supported, err := client.GetSupportedDevices()
+	if err != nil || !reflect.DeepEqual(supported, []uint{0}) {
+		t.Errorf("GetSupportedDevices = %v, %v, want [0]", supported, err)
+	}
+
+	info, err := client.GetDeviceInfo(1)
+	if err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	if info.IxDCGMSupported != "N" || info.Topology != nil || info.PCI.Bandwidth != 0 {
+		t.Errorf("GetDeviceInfo of an unsupported GPU = %+v", info)
+	}
+}
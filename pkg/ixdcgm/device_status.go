@@ -23,11 +23,14 @@ package ixdcgm
 */
 import "C"
 import (
+	"context"
 	"fmt"
-	"math/rand"
 	"time"
 )
 
+// profSampleTime is how long the profiling fields are watched before they are read
+const profSampleTime = 2000 * time.Millisecond
+
 type PerfState uint
 
 const (
@@ -65,6 +68,7 @@ type MemoryUsage struct {
 	Free  int64 // Free Memory (Frame Buffer) in MB
 }
 
+// DeviceStatus is the status of a GPU, its int64 values are 0 when the field is blank
 type DeviceStatus struct {
 	Id          uint
 	Power       string // "N/A" or float64 str, W
@@ -87,7 +91,7 @@ type DeviceProfStatus struct {
 	DramActive  string // "N/A" or float64 str, %
 }
 
-func getDeviceStatus(gpuId uint) (status DeviceStatus, err error) {
+func (c *Client) getDeviceStatus(ctx context.Context, gpuId uint) (status DeviceStatus, err error) {
 	const (
 		IdxPower int = iota
 		IdxGpuTemp
@@ -124,46 +128,37 @@ func getDeviceStatus(gpuId uint) (status DeviceStatus, err error) {
 		DCGM_FI_DEV_FB_FREE,
 	}
 
-	fieldGrpName := fmt.Sprintf("devStatusFields%d", rand.Uint64())
-	fieldGrp, err := FieldGroupCreate(fieldGrpName, fields)
-	if err != nil {
-		return
-	}
-
-	gpuGrpName := fmt.Sprintf("devStatusGrp%d", rand.Uint64())
-	gpuGrpHdl, err := WatchFields([]uint{gpuId}, fieldGrp, gpuGrpName)
+	release, err := c.watchTemporary(ctx, "devStatus", []uint{gpuId}, fields)
 	if err != nil {
-		_ = FieldGroupDestroy(fieldGrp)
 		return
 	}
+	defer release()
 
-	values, err := GetLatestValuesForFields(gpuId, fields)
+	values, err := c.GetLatestValuesForFieldsCtx(ctx, gpuId, fields)
 	if err != nil {
-		_ = FieldGroupDestroy(fieldGrp)
-		_ = DestroyGroup(gpuGrpHdl)
 		return status, err
 	}
 
 	clocks := ClockInfo{
-		Sm:  values[IdxSmClock].Int64(),
-		Mem: values[IdxMemClock].Int64(),
+		Sm:  int64Value(values[IdxSmClock]),
+		Mem: int64Value(values[IdxMemClock]),
 	}
 
 	utilInfo := UtilizationInfo{
-		Gpu: values[IdxGpuUtil].Int64(),
-		Mem: values[IdxMemUtil].Int64(),
+		Gpu: int64Value(values[IdxGpuUtil]),
+		Mem: int64Value(values[IdxMemUtil]),
 	}
 
 	pciInfo := PCIStatusInfo{
-		Rx:            values[IdxPcieRxThroughput].Int64(),
-		Tx:            values[IdxPcieTxThroughput].Int64(),
-		ReplayCounter: values[IdxPcieReplayCounter].Int64(),
+		Rx:            int64Value(values[IdxPcieRxThroughput]),
+		Tx:            int64Value(values[IdxPcieTxThroughput]),
+		ReplayCounter: int64Value(values[IdxPcieReplayCounter]),
 	}
 
 	memUsage := MemoryUsage{
-		Total: values[IdxMemTotal].Int64(),
-		Free:  values[IdxMemFree].Int64(),
-		Used:  values[IdxMemUsed].Int64(),
+		Total: int64Value(values[IdxMemTotal]),
+		Free:  int64Value(values[IdxMemFree]),
+		Used:  int64Value(values[IdxMemUsed]),
 	}
 
 	status = DeviceStatus{
@@ -178,13 +173,10 @@ func getDeviceStatus(gpuId uint) (status DeviceStatus, err error) {
 		EccSbeVolDev: GetFieldValueStr(values[IdxEccSbeVolDev], "int64"),
 		EccDbeVolDev: GetFieldValueStr(values[IdxEccDbeVolDev], "int64"),
 	}
-
-	_ = FieldGroupDestroy(fieldGrp)
-	_ = DestroyGroup(gpuGrpHdl)
 	return
 }
 
-func getDeviceProfStatus(gpuId uint) (status DeviceProfStatus, err error) {
+func (c *Client) getDeviceProfStatus(ctx context.Context, gpuId uint) (status DeviceProfStatus, err error) {
 	const (
 		IdxSmActive int = iota
 		IdxSmOccupancy
@@ -197,24 +189,18 @@ func getDeviceProfStatus(gpuId uint) (status DeviceProfStatus, err error) {
 		DCGM_FI_PROF_DRAM_ACTIVE,
 	}
 
-	fieldGrpName := fmt.Sprintf("devProfStatusFields%d", rand.Uint64())
-	fieldGrp, err := FieldGroupCreate(fieldGrpName, fields)
+	release, err := c.watchTemporary(ctx, "devProfStatus", []uint{gpuId}, fields)
 	if err != nil {
 		return
 	}
+	defer release()
 
-	grpName := fmt.Sprintf("devProfStatusGrp%d", rand.Uint64())
-	grpId, err := WatchFields([]uint{gpuId}, fieldGrp, grpName)
-	if err != nil {
-		_ = FieldGroupDestroy(fieldGrp)
+	// the profiling metrics are averaged over the time they are watched
+	if err = sleepCtx(ctx, profSampleTime); err != nil {
 		return
 	}
-
-	time.Sleep(2000 * time.Millisecond)
-	values, err := GetLatestValuesForFields(gpuId, fields)
+	values, err := c.GetLatestValuesForFieldsCtx(ctx, gpuId, fields)
 	if err != nil {
-		_ = FieldGroupDestroy(fieldGrp)
-		_ = DestroyGroup(grpId)
 		return status, err
 	}
 
@@ -223,9 +209,11 @@ func getDeviceProfStatus(gpuId uint) (status DeviceProfStatus, err error) {
 		SmOccupancy: GetFieldValueStr(values[IdxSmOccupancy], "float64"),
 		DramActive:  GetFieldValueStr(values[IdxDramActive], "float64"),
 	}
-
-	_ = FieldGroupDestroy(fieldGrp)
-	_ = DestroyGroup(grpId)
 	return
+}
 
+// int64Value returns the value of an int64 field, 0 if it is blank or could not be read
+func int64Value(fv FieldValue_v1) int64 {
+	value, _ := fv.TypedValue().Int64()
+	return value
 }
@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"errors"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestGetDeviceStatus(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	fake.SetFieldFloat64(1, ixdcgm.DCGM_FI_DEV_POWER_USAGE, 123.4567)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_GPU_TEMP, 45, 46)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_GPU_UTIL, 80)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_MEM_COPY_UTIL, 30)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_SM_CLOCK, 1500)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_MEM_CLOCK, 1200)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_PCIE_RX_THROUGHPUT, 1000)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_PCIE_TX_THROUGHPUT, 2000)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_PCIE_REPLAY_COUNTER, 3)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_ECC_SBE_VOL_DEV, 0)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_ECC_DBE_VOL_DEV, ixdcgm.DCGM_FT_INT64_NOT_SUPPORTED)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_FB_TOTAL, 32768)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_FB_USED, 1024)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_FB_FREE, 31744)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	status, err := client.GetDeviceStatus(1)
+	if err != nil {
+		t.Fatalf("GetDeviceStatus failed: %v", err)
+	}
+
+	want := ixdcgm.DeviceStatus{
+		Id:           1,
+		Power:        "123.457",
+		Temperature:  "45",
+		Utilization:  ixdcgm.UtilizationInfo{Gpu: 80, Mem: 30},
+		Clocks:       ixdcgm.ClockInfo{Sm: 1500, Mem: 1200},
+		PCI:          ixdcgm.PCIStatusInfo{Rx: 1000, Tx: 2000, ReplayCounter: 3},
+		MemUsage:     ixdcgm.MemoryUsage{Total: 32768, Used: 1024, Free: 31744},
+		FanSpeed:     "N/A",
+		EccSbeVolDev: "0",
+		EccDbeVolDev: "N/A",
+	}
+	if status != want {
+		t.Errorf("GetDeviceStatus = %+v, want %+v", status, want)
+	}
+
+	// scripted values are returned in order
+	status, err = client.GetDeviceStatus(1)
+	if err != nil {
+		t.Fatalf("GetDeviceStatus failed: %v", err)
+	}
+	if status.Temperature != "46" {
+		t.Errorf("Temperature of the second status = %s, want 46", status.Temperature)
+	}
+
+	if n := fake.GroupCount(); n != 0 {
+		t.Errorf("%d groups left after GetDeviceStatus", n)
+	}
+	if n := fake.FieldGroupCount(); n != 0 {
+		t.Errorf("%d field groups left after GetDeviceStatus", n)
+	}
+}
+
+func TestGetDeviceStatusWithoutData(t *testing.T) {
+	client := ixdcgm.NewFakeClient(ixdcgm.NewFakeBackend(1))
+	defer client.Close()
+
+	status, err := client.GetDeviceStatus(0)
+	if err != nil {
+		t.Fatalf("GetDeviceStatus failed: %v", err)
+	}
+	if status.Power != "N/A" || status.Temperature != "N/A" || status.FanSpeed != "N/A" {
+		t.Errorf("GetDeviceStatus of a GPU without data = %+v, want N/A values", status)
+	}
+	if status.MemUsage != (ixdcgm.MemoryUsage{}) || status.Clocks != (ixdcgm.ClockInfo{}) {
+		t.Errorf("GetDeviceStatus of a GPU without data = %+v, want 0 for the blank values", status)
+	}
+}
+
+func TestGetDeviceStatusUnknownGpu(t *testing.T) {
+	client := ixdcgm.NewFakeClient(ixdcgm.NewFakeBackend(1))
+	defer client.Close()
+
+	_, err := client.GetDeviceStatus(4)
+	if !errors.Is(err, ixdcgm.ErrBadParam) {
+		t.Errorf("GetDeviceStatus of an unknown GPU returned %v, want ErrBadParam", err)
+	}
+}
@@ -24,6 +24,7 @@ package ixdcgm
 import "C"
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 	"unsafe"
@@ -170,43 +171,57 @@ func diagLevel(diagType DiagType) C.dcgmDiagnosticLevel_t {
 // RunDiagWithTimeout executes a diagnostic with a specified timeout.
 // If the diagnostic does not complete within the timeout duration, an error is returned.
 func RunDiagWithTimeout(diagType DiagType, groupId GroupHandle, t time.Duration) (DiagResults, error) {
+	return defaultClient.RunDiagWithTimeout(diagType, groupId, t)
+}
+
+// RunDiagWithTimeout executes a diagnostic with a specified timeout.
+// If the diagnostic does not complete within the timeout duration, an error is returned.
+func (c *Client) RunDiagWithTimeout(diagType DiagType, groupId GroupHandle, t time.Duration) (DiagResults, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), t)
 	defer cancel()
 
-	// Channels to receive the diagnostic results or error
-	resultChan := make(chan DiagResults, 1)
-	errChan := make(chan error, 1)
-
-	// Run the diagnostic in a separate goroutine
-	go func() {
-		result, err := RunDiag(diagType, groupId)
-		if err != nil {
-			errChan <- err
-		} else {
-			resultChan <- result
-		}
-	}()
-
-	// Wait for the diagnostic to complete or the timeout to occur
-	select {
-	case <-ctx.Done():
-		return DiagResults{}, fmt.Errorf("Error: diagnostic execution timed out after %v", t)
-	case err := <-errChan:
-		return DiagResults{}, err
-	case result := <-resultChan:
-		return result, nil
+	result, err := c.RunDiagCtx(ctx, diagType, groupId)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return DiagResults{}, fmt.Errorf("Error: diagnostic execution timed out after %v: %w", t, err)
 	}
+	return result, err
 }
 
 func RunDiag(diagType DiagType, groupId GroupHandle) (DiagResults, error) {
+	return defaultClient.RunDiag(diagType, groupId)
+}
+
+func (c *Client) RunDiag(diagType DiagType, groupId GroupHandle) (DiagResults, error) {
+	return c.RunDiagCtx(context.Background(), diagType, groupId)
+}
+
+// RunDiagCtx is RunDiag returning ctx.Err() as soon as ctx is done,
+// the diagnostic keeps running on the hostengine until it completes.
+func RunDiagCtx(ctx context.Context, diagType DiagType, groupId GroupHandle) (DiagResults, error) {
+	return defaultClient.RunDiagCtx(ctx, diagType, groupId)
+}
+
+// RunDiagCtx is RunDiag returning ctx.Err() as soon as ctx is done,
+// the diagnostic keeps running on the hostengine until it completes.
+func (c *Client) RunDiagCtx(ctx context.Context, diagType DiagType, groupId GroupHandle) (DiagResults, error) {
+	return callCtx(ctx, func() (results DiagResults, err error) {
+		err = c.withBackend(func(b backend) (err error) {
+			results, err = b.runDiag(c.res.groupId(groupId), diagType)
+			return err
+		})
+		return results, err
+	})
+}
+
+func (b cgoBackend) runDiag(groupId C.dcgmGpuGrp_t, diagType DiagType) (DiagResults, error) {
 	var diagResults C.dcgmDiagResponse_v10
 	diagResults.version = makeVersion10(unsafe.Sizeof(diagResults))
 
-	result := C.dcgmRunDiagnostic(handle.handle, groupId.handle, diagLevel(diagType), (*C.dcgmDiagResponse_v10)(unsafe.Pointer(&diagResults)))
+	result := C.dcgmRunDiagnostic(b.handle, groupId, diagLevel(diagType), (*C.dcgmDiagResponse_v10)(unsafe.Pointer(&diagResults)))
 	if err := errorString(result); err != nil {
-		return DiagResults{}, &DcgmError{msg: C.GoString(C.errorString(result)), Code: result}
+		return DiagResults{}, fmt.Errorf("Error running diagnostic: %w", err)
 	}
-	defer C.dcgmStopDiagnostic(handle.handle)
+	defer C.dcgmStopDiagnostic(b.handle)
 
 	var diagRun DiagResults
 	diagRun.gpuCount = uint(diagResults.gpuCount)
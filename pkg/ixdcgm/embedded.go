@@ -26,79 +26,49 @@ import "C"
 import "fmt"
 
 type embedded struct {
+	handle DcgmHandle
 }
 
 func (e *embedded) Shutdown() error {
-	result := C.dcgmStopEmbedded(handle.handle)
+	result := C.dcgmStopEmbedded(e.handle.handle)
 	if err := errorString(result); err != nil {
-		return fmt.Errorf("failed to stop embedded dcgm: %v", err)
-	}
-
-	result = C.dcgmShutdown()
-	if err := errorString(result); err != nil {
-		return fmt.Errorf("failed to shutdown dcgm: %v", err)
+		return fmt.Errorf("failed to stop embedded dcgm: %w", err)
 	}
 	return nil
 }
 
 func (e *embedded) Start(args ...string) (DcgmHandle, error) {
-	fmt.Println("Start ixdcgm based on Embedded mode.")
-
-	result := C.dcgmInit()
-	if err := errorString(result); err != nil {
-		return DcgmHandle{}, fmt.Errorf("failed to initialize dcgm: %v", err)
-	}
+	return startWithArgs(e, Embedded, args...)
+}
 
-	logLevel := C.DcgmLoggingSeverityNone
-	if len(args) > 0 {
-		logLevelStr := args[0]
-		switch logLevelStr {
-		case "LogNone":
-			logLevel = C.DcgmLoggingSeverityNone
-		case "LogFatal":
-			logLevel = C.DcgmLoggingSeverityFatal
-		case "LogError":
-			logLevel = C.DcgmLoggingSeverityError
-		case "LogWarn":
-			logLevel = C.DcgmLoggingSeverityWarning
-		case "LogInfo":
-			logLevel = C.DcgmLoggingSeverityInfo
-		case "LogDebug":
-			logLevel = C.DcgmLoggingSeverityDebug
-		case "LogVerb":
-			logLevel = C.DcgmLoggingSeverityVerbose
-		default:
-			errMsg := fmt.Sprintf("Invalid log level: %s", logLevelStr)
-			fmt.Println(errMsg)
-			fmt.Println("The following log levels are supported: LogNone, LogFatal, LogError, LogWarn, LogInfo, LogDebug, LogVerb.")
-			fmt.Println(" - LogNone  : No logging")
-			fmt.Println(" - LogFatal : Fatal errors")
-			fmt.Println(" - LogError : Errors")
-			fmt.Println(" - LogWarn  : Warnings")
-			fmt.Println(" - LogInfo  : Informative, will generate medium logs")
-			fmt.Println(" - LogDebug : Debug infomation, will generate large logs")
-			fmt.Println(" - LogVerb  : Verbose debugging information, will generate more large logs")
-			fmt.Println()
-			return DcgmHandle{}, fmt.Errorf("%v", errMsg)
-		}
-	}
+func (e *embedded) start(cfg Config) (DcgmHandle, error) {
+	getLogger().Info("Start ixdcgm based on Embedded mode")
 
 	params := C.dcgmStartEmbeddedV2Params_v1{
 		version:       C.dcgmStartEmbeddedV2Params_version1,
 		opMode:        C.dcgmOperationMode_t(C.DCGM_OPERATION_MODE_AUTO),
 		dcgmHandle:    C.dcgmHandle_t(0),
 		logFile:       nil, // use default log file
-		severity:      C.DcgmLoggingSeverity_t(logLevel),
-		denyListCount: 0, // no deny list
+		severity:      C.DcgmLoggingSeverity_t(cfg.LogLevel),
+		denyListCount: C.uint(len(cfg.DenyModules)),
 		denyList:      [C.DcgmModuleIdCount]C.uint{0},
 	}
+	for i, id := range cfg.DenyModules {
+		params.denyList[i] = C.uint(id)
+	}
+	if cfg.LogFile != "" {
+		logFile := string2Char(cfg.LogFile)
+		defer freeCString(logFile)
+		params.logFile = logFile
+	}
 
 	// Use dcgmStartEmbedded_v2 but dcgmStartEmbedded which using verbose log
-	result = C.dcgmStartEmbedded_v2(&params)
+	result := C.dcgmStartEmbedded_v2(&params)
 	if err := errorString(result); err != nil {
-		return DcgmHandle{}, fmt.Errorf("failed to start embedded dcgm: %v", err)
+		return DcgmHandle{}, fmt.Errorf("failed to start embedded dcgm: %w", err)
 	}
 
 	var cHandler C.dcgmHandle_t = params.dcgmHandle
-	return DcgmHandle{handle: cHandler}, nil
+	e.handle = DcgmHandle{handle: cHandler}
+	return e.handle, nil
 }
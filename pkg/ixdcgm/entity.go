@@ -0,0 +1,115 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"unsafe"
+)
+
+// GetEntities lists the entities of an entity group, see Client.GetEntities
+func GetEntities(group Field_Entity_Group, onlySupported bool) ([]GroupEntityPair, error) {
+	return defaultClient.GetEntities(group, onlySupported)
+}
+
+// GetEntities lists the entities of an entity group, e.g. the links or the CPUs of the node.
+// If onlySupported is true, the entities which are not supported by IXDCGM are left out.
+func (c *Client) GetEntities(group Field_Entity_Group, onlySupported bool) ([]GroupEntityPair, error) {
+	ids, err := c.backend().getEntities(group, onlySupported)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]GroupEntityPair, len(ids))
+	for i, id := range ids {
+		entities[i] = GroupEntityPair{EntityGroupId: group, EntityId: id}
+	}
+	return entities, nil
+}
+
+func (b cgoBackend) getEntities(group Field_Entity_Group, onlySupported bool) ([]uint, error) {
+	var flags C.uint
+	if onlySupported {
+		flags = C.DCGM_GEGE_FLAG_ONLY_SUPPORTED
+	}
+
+	entities := make([]C.dcgm_field_eid_t, C.DCGM_GROUP_MAX_ENTITIES)
+	count := C.int(len(entities))
+	res := C.dcgmGetEntityGroupEntities(b.handle, C.dcgm_field_entity_group_t(group), &entities[0], &count, flags)
+	if res == C.DCGM_ST_INSUFFICIENT_SIZE {
+		// count is the number of entities of the group
+		entities = make([]C.dcgm_field_eid_t, count)
+		res = C.dcgmGetEntityGroupEntities(b.handle, C.dcgm_field_entity_group_t(group), &entities[0], &count, flags)
+	}
+	if err := errorString(res); err != nil {
+		return nil, fmt.Errorf("Error getting entities of %s: %w", group, err)
+	}
+
+	ids := make([]uint, int(count))
+	for i := range ids {
+		ids[i] = uint(entities[i])
+	}
+	return ids, nil
+}
+
+// GetEntityLatestValues is GetLatestValuesForFields for an entity of any entity group
+func GetEntityLatestValues(entity GroupEntityPair, fields []Short) ([]FieldValue_v1, error) {
+	return defaultClient.GetEntityLatestValues(entity, fields)
+}
+
+// GetEntityLatestValues is GetLatestValuesForFields for an entity of any entity group, e.g. a link or a CPU.
+// The fields must be watched on a group holding the entity.
+func (c *Client) GetEntityLatestValues(entity GroupEntityPair, fields []Short) ([]FieldValue_v1, error) {
+	return c.backend().entityGetLatestValues(entity, fields)
+}
+
+// GetEntityLatestValuesCtx is GetEntityLatestValues returning ctx.Err() as soon as ctx is done
+func GetEntityLatestValuesCtx(ctx context.Context, entity GroupEntityPair, fields []Short) ([]FieldValue_v1, error) {
+	return defaultClient.GetEntityLatestValuesCtx(ctx, entity, fields)
+}
+
+// GetEntityLatestValuesCtx is GetEntityLatestValues returning ctx.Err() as soon as ctx is done
+func (c *Client) GetEntityLatestValuesCtx(ctx context.Context, entity GroupEntityPair, fields []Short) ([]FieldValue_v1, error) {
+	b := c.backend()
+	return callCtx(ctx, func() ([]FieldValue_v1, error) {
+		return b.entityGetLatestValues(entity, fields)
+	})
+}
+
+func (b cgoBackend) entityGetLatestValues(entity GroupEntityPair, fields []Short) ([]FieldValue_v1, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("error getting latest DCGM fields values of %s %d: %w",
+			entity.EntityGroupId, entity.EntityId, newDcgmError(DCGM_ST_BADPARAM))
+	}
+	buf := getFieldValueBuf(len(fields))
+	values := *buf
+	cFields := *(*[]C.ushort)(unsafe.Pointer(&fields))
+	res := C.dcgmEntityGetLatestValues(b.handle, C.dcgm_field_entity_group_t(entity.EntityGroupId), C.int(entity.EntityId),
+		&cFields[0], C.uint(len(fields)), &values[0])
+	if err := errorString(res); err != nil {
+		fieldValueBufs.Put(buf)
+		return nil, fmt.Errorf("error getting latest DCGM fields values of %s %d: %w", entity.EntityGroupId, entity.EntityId, err)
+	}
+	return decodeFieldValueBuf(buf), nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"errors"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestGetEntities(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(3)
+	if err := fake.SetGPU(1, ixdcgm.FakeGPU{Unsupported: true}); err != nil {
+		t.Fatal(err)
+	}
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	all, err := client.GetEntities(ixdcgm.FE_GPU, false)
+	if err != nil {
+		t.Fatalf("GetEntities failed: %v", err)
+	}
+	if len(all) != 3 || all[2] != (ixdcgm.GroupEntityPair{EntityGroupId: ixdcgm.FE_GPU, EntityId: 2}) {
+		t.Errorf("GetEntities(FE_GPU, false) = %v, want the 3 GPUs", all)
+	}
+	supported, err := client.GetEntities(ixdcgm.FE_GPU, true)
+	if err != nil {
+		t.Fatalf("GetEntities failed: %v", err)
+	}
+	if len(supported) != 2 || supported[1].EntityId != 2 {
+		t.Errorf("GetEntities(FE_GPU, true) = %v, want GPUs 0 and 2", supported)
+	}
+
+	// the fake simulates GPUs only
+	links, err := client.GetEntities(ixdcgm.FE_LINK, false)
+	if err != nil || len(links) != 0 {
+		t.Errorf("GetEntities(FE_LINK, false) = %v, %v, want no link", links, err)
+	}
+}
+
+func TestGetEntityLatestValues(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(1)
+	fake.SetFieldInt64(0, ixdcgm.DCGM_FI_DEV_GPU_TEMP, 50)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	fields := []ixdcgm.Short{ixdcgm.DCGM_FI_DEV_GPU_TEMP}
+	fieldGroup, err := client.FieldGroupCreate("temperature", fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.WatchFields([]uint{0}, fieldGroup, "temperature"); err != nil {
+		t.Fatal(err)
+	}
+
+	gpu := ixdcgm.GroupEntityPair{EntityGroupId: ixdcgm.FE_GPU, EntityId: 0}
+	values, err := client.GetEntityLatestValues(gpu, fields)
+	if err != nil {
+		t.Fatalf("GetEntityLatestValues failed: %v", err)
+	}
+	if values[0].Status != ixdcgm.DCGM_ST_OK || values[0].Int64() != 50 {
+		t.Errorf("GetEntityLatestValues = %d (status %d), want 50", values[0].Int64(), values[0].Status)
+	}
+
+	cpu := ixdcgm.GroupEntityPair{EntityGroupId: ixdcgm.FE_CPU, EntityId: 0}
+	if _, err = client.GetEntityLatestValues(cpu, fields); !errors.Is(err, ixdcgm.ErrBadParam) {
+		t.Errorf("GetEntityLatestValues of an unknown CPU returned %v, want ErrBadParam", err)
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ReturnCode is a dcgmReturn_t or ixdcgmReturn_t value, see the DCGM_ST_* and IXDCGM_RET_* constants.
+// Both kinds of return codes share the same values.
+type ReturnCode int
+
+var returnCodeNames = map[ReturnCode]string{
+	DCGM_ST_OK:                          "DCGM_ST_OK",
+	DCGM_ST_BADPARAM:                    "DCGM_ST_BADPARAM",
+	DCGM_ST_GENERIC_ERROR:               "DCGM_ST_GENERIC_ERROR",
+	DCGM_ST_MEMORY:                      "DCGM_ST_MEMORY",
+	DCGM_ST_NOT_CONFIGURED:              "DCGM_ST_NOT_CONFIGURED",
+	DCGM_ST_NOT_SUPPORTED:               "DCGM_ST_NOT_SUPPORTED",
+	DCGM_ST_INIT_ERROR:                  "DCGM_ST_INIT_ERROR",
+	DCGM_ST_NVML_ERROR:                  "DCGM_ST_NVML_ERROR",
+	DCGM_ST_PENDING:                     "DCGM_ST_PENDING",
+	DCGM_ST_UNINITIALIZED:               "DCGM_ST_UNINITIALIZED",
+	DCGM_ST_TIMEOUT:                     "DCGM_ST_TIMEOUT",
+	DCGM_ST_VER_MISMATCH:                "DCGM_ST_VER_MISMATCH",
+	DCGM_ST_UNKNOWN_FIELD:               "DCGM_ST_UNKNOWN_FIELD",
+	DCGM_ST_NO_DATA:                     "DCGM_ST_NO_DATA",
+	DCGM_ST_STALE_DATA:                  "DCGM_ST_STALE_DATA",
+	DCGM_ST_NOT_WATCHED:                 "DCGM_ST_NOT_WATCHED",
+	DCGM_ST_NO_PERMISSION:               "DCGM_ST_NO_PERMISSION",
+	DCGM_ST_GPU_IS_LOST:                 "DCGM_ST_GPU_IS_LOST",
+	DCGM_ST_RESET_REQUIRED:              "DCGM_ST_RESET_REQUIRED",
+	DCGM_ST_FUNCTION_NOT_FOUND:          "DCGM_ST_FUNCTION_NOT_FOUND",
+	DCGM_ST_CONNECTION_NOT_VALID:        "DCGM_ST_CONNECTION_NOT_VALID",
+	DCGM_ST_GPU_NOT_SUPPORTED:           "DCGM_ST_GPU_NOT_SUPPORTED",
+	DCGM_ST_GROUP_INCOMPATIBLE:          "DCGM_ST_GROUP_INCOMPATIBLE",
+	DCGM_ST_MAX_LIMIT:                   "DCGM_ST_MAX_LIMIT",
+	DCGM_ST_LIBRARY_NOT_FOUND:           "DCGM_ST_LIBRARY_NOT_FOUND",
+	DCGM_ST_DUPLICATE_KEY:               "DCGM_ST_DUPLICATE_KEY",
+	DCGM_ST_GPU_IN_SYNC_BOOST_GROUP:     "DCGM_ST_GPU_IN_SYNC_BOOST_GROUP",
+	DCGM_ST_GPU_NOT_IN_SYNC_BOOST_GROUP: "DCGM_ST_GPU_NOT_IN_SYNC_BOOST_GROUP",
+	DCGM_ST_REQUIRES_ROOT:               "DCGM_ST_REQUIRES_ROOT",
+	DCGM_ST_NVVS_ERROR:                  "DCGM_ST_NVVS_ERROR",
+	DCGM_ST_INSUFFICIENT_SIZE:           "DCGM_ST_INSUFFICIENT_SIZE",
+	DCGM_ST_FIELD_UNSUPPORTED_BY_API:    "DCGM_ST_FIELD_UNSUPPORTED_BY_API",
+	DCGM_ST_MODULE_NOT_LOADED:           "DCGM_ST_MODULE_NOT_LOADED",
+	DCGM_ST_IN_USE:                      "DCGM_ST_IN_USE",
+	DCGM_ST_GROUP_IS_EMPTY:              "DCGM_ST_GROUP_IS_EMPTY",
+	DCGM_ST_PROFILING_NOT_SUPPORTED:     "DCGM_ST_PROFILING_NOT_SUPPORTED",
+	DCGM_ST_PROFILING_LIBRARY_ERROR:     "DCGM_ST_PROFILING_LIBRARY_ERROR",
+	DCGM_ST_PROFILING_MULTI_PASS:        "DCGM_ST_PROFILING_MULTI_PASS",
+	DCGM_ST_DIAG_ALREADY_RUNNING:        "DCGM_ST_DIAG_ALREADY_RUNNING",
+	DCGM_ST_DIAG_BAD_JSON:               "DCGM_ST_DIAG_BAD_JSON",
+	DCGM_ST_DIAG_BAD_LAUNCH:             "DCGM_ST_DIAG_BAD_LAUNCH",
+	DCGM_ST_DIAG_UNUSED:                 "DCGM_ST_DIAG_UNUSED",
+	DCGM_ST_DIAG_THRESHOLD_EXCEEDED:     "DCGM_ST_DIAG_THRESHOLD_EXCEEDED",
+	DCGM_ST_INSUFFICIENT_DRIVER_VERSION: "DCGM_ST_INSUFFICIENT_DRIVER_VERSION",
+	DCGM_ST_INSTANCE_NOT_FOUND:          "DCGM_ST_INSTANCE_NOT_FOUND",
+	DCGM_ST_COMPUTE_INSTANCE_NOT_FOUND:  "DCGM_ST_COMPUTE_INSTANCE_NOT_FOUND",
+	DCGM_ST_CHILD_NOT_KILLED:            "DCGM_ST_CHILD_NOT_KILLED",
+	DCGM_ST_3RD_PARTY_LIBRARY_ERROR:     "DCGM_ST_3RD_PARTY_LIBRARY_ERROR",
+	DCGM_ST_INSUFFICIENT_RESOURCES:      "DCGM_ST_INSUFFICIENT_RESOURCES",
+	DCGM_ST_PLUGIN_EXCEPTION:            "DCGM_ST_PLUGIN_EXCEPTION",
+	DCGM_ST_NVVS_ISOLATE_ERROR:          "DCGM_ST_NVVS_ISOLATE_ERROR",
+	DCGM_ST_NVVS_BINARY_NOT_FOUND:       "DCGM_ST_NVVS_BINARY_NOT_FOUND",
+	DCGM_ST_NVVS_KILLED:                 "DCGM_ST_NVVS_KILLED",
+	DCGM_ST_PAUSED:                      "DCGM_ST_PAUSED",
+	DCGM_ST_ALREADY_INITIALIZED:         "DCGM_ST_ALREADY_INITIALIZED",
+}
+
+func (r ReturnCode) String() string {
+	if name, ok := returnCodeNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("ReturnCode(%d)", int(r))
+}
+
+// Sentinel errors of the most common return codes. Errors returned by the package
+// wrap a *DcgmError, which matches the sentinel of its code with errors.Is, e.g.
+//
+//	if errors.Is(err, ixdcgm.ErrNotSupported) { ... }
+var (
+	ErrBadParam                  = errors.New("bad parameter")
+	ErrGenericError              = errors.New("generic error")
+	ErrMemory                    = errors.New("out of memory")
+	ErrNotConfigured             = errors.New("not configured")
+	ErrNotSupported              = errors.New("not supported")
+	ErrInitError                 = errors.New("initialization error")
+	ErrPending                   = errors.New("pending")
+	ErrUninitialized             = errors.New("uninitialized")
+	ErrTimeout                   = errors.New("timeout")
+	ErrVersionMismatch           = errors.New("version mismatch")
+	ErrUnknownField              = errors.New("unknown field")
+	ErrNoData                    = errors.New("no data")
+	ErrStaleData                 = errors.New("stale data")
+	ErrNotWatched                = errors.New("field is not watched")
+	ErrNoPermission              = errors.New("no permission")
+	ErrGpuIsLost                 = errors.New("gpu is lost")
+	ErrResetRequired             = errors.New("gpu reset required")
+	ErrConnectionNotValid        = errors.New("connection not valid")
+	ErrGpuNotSupported           = errors.New("gpu not supported")
+	ErrGroupIncompatible         = errors.New("group incompatible")
+	ErrMaxLimit                  = errors.New("max limit reached")
+	ErrLibraryNotFound           = errors.New("library not found")
+	ErrDuplicateKey              = errors.New("duplicate key")
+	ErrRequiresRoot              = errors.New("requires root")
+	ErrInsufficientSize          = errors.New("insufficient size")
+	ErrFieldUnsupportedByApi     = errors.New("field unsupported by api")
+	ErrModuleNotLoaded           = errors.New("module not loaded")
+	ErrInUse                     = errors.New("in use")
+	ErrGroupIsEmpty              = errors.New("group is empty")
+	ErrProfilingNotSupported     = errors.New("profiling not supported")
+	ErrDiagAlreadyRunning        = errors.New("diag already running")
+	ErrInsufficientDriverVersion = errors.New("insufficient driver version")
+	ErrInsufficientResources     = errors.New("insufficient resources")
+	ErrPaused                    = errors.New("paused")
+	ErrAlreadyInitialized        = errors.New("already initialized")
+)
+
+var returnCodeErrors = map[ReturnCode]error{
+	DCGM_ST_BADPARAM:                    ErrBadParam,
+	DCGM_ST_GENERIC_ERROR:               ErrGenericError,
+	DCGM_ST_MEMORY:                      ErrMemory,
+	DCGM_ST_NOT_CONFIGURED:              ErrNotConfigured,
+	DCGM_ST_NOT_SUPPORTED:               ErrNotSupported,
+	DCGM_ST_INIT_ERROR:                  ErrInitError,
+	DCGM_ST_PENDING:                     ErrPending,
+	DCGM_ST_UNINITIALIZED:               ErrUninitialized,
+	DCGM_ST_TIMEOUT:                     ErrTimeout,
+	DCGM_ST_VER_MISMATCH:                ErrVersionMismatch,
+	DCGM_ST_UNKNOWN_FIELD:               ErrUnknownField,
+	DCGM_ST_NO_DATA:                     ErrNoData,
+	DCGM_ST_STALE_DATA:                  ErrStaleData,
+	DCGM_ST_NOT_WATCHED:                 ErrNotWatched,
+	DCGM_ST_NO_PERMISSION:               ErrNoPermission,
+	DCGM_ST_GPU_IS_LOST:                 ErrGpuIsLost,
+	DCGM_ST_RESET_REQUIRED:              ErrResetRequired,
+	DCGM_ST_CONNECTION_NOT_VALID:        ErrConnectionNotValid,
+	DCGM_ST_GPU_NOT_SUPPORTED:           ErrGpuNotSupported,
+	DCGM_ST_GROUP_INCOMPATIBLE:          ErrGroupIncompatible,
+	DCGM_ST_MAX_LIMIT:                   ErrMaxLimit,
+	DCGM_ST_LIBRARY_NOT_FOUND:           ErrLibraryNotFound,
+	DCGM_ST_DUPLICATE_KEY:               ErrDuplicateKey,
+	DCGM_ST_REQUIRES_ROOT:               ErrRequiresRoot,
+	DCGM_ST_INSUFFICIENT_SIZE:           ErrInsufficientSize,
+	DCGM_ST_FIELD_UNSUPPORTED_BY_API:    ErrFieldUnsupportedByApi,
+	DCGM_ST_MODULE_NOT_LOADED:           ErrModuleNotLoaded,
+	DCGM_ST_IN_USE:                      ErrInUse,
+	DCGM_ST_GROUP_IS_EMPTY:              ErrGroupIsEmpty,
+	DCGM_ST_PROFILING_NOT_SUPPORTED:     ErrProfilingNotSupported,
+	DCGM_ST_DIAG_ALREADY_RUNNING:        ErrDiagAlreadyRunning,
+	DCGM_ST_INSUFFICIENT_DRIVER_VERSION: ErrInsufficientDriverVersion,
+	DCGM_ST_INSUFFICIENT_RESOURCES:      ErrInsufficientResources,
+	DCGM_ST_PAUSED:                      ErrPaused,
+	DCGM_ST_ALREADY_INITIALIZED:         ErrAlreadyInitialized,
+}
+
+// DcgmError is an error returned by IXDCGM
+type DcgmError struct {
+	msg  string     // description of error
+	Code ReturnCode // dcgmReturn_t or ixdcgmReturn_t value of error
+}
+
+func (e *DcgmError) Error() string { return e.msg }
+
+// newDcgmError returns the error of a return code without asking IXDCGM for its description
+func newDcgmError(code ReturnCode) *DcgmError {
+	msg := code.String()
+	if sentinel, ok := returnCodeErrors[code]; ok {
+		msg = sentinel.Error()
+	}
+	return &DcgmError{msg: msg, Code: code}
+}
+
+// Is reports whether target is the sentinel error of the return code, e.g. ErrGpuIsLost
+func (e *DcgmError) Is(target error) bool {
+	sentinel, ok := returnCodeErrors[e.Code]
+	return ok && sentinel == target
+}
+
+// ReturnCodeOf returns the return code of the *DcgmError wrapped by err,
+// DCGM_ST_OK if err is nil and DCGM_ST_GENERIC_ERROR if err is not returned by IXDCGM
+func ReturnCodeOf(err error) ReturnCode {
+	if err == nil {
+		return DCGM_ST_OK
+	}
+	var dcgmErr *DcgmError
+	if errors.As(err, &dcgmErr) {
+		return dcgmErr.Code
+	}
+	return DCGM_ST_GENERIC_ERROR
+}
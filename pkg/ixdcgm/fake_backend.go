@@ -0,0 +1,797 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+*/
+import "C"
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FakeGPU describes a GPU simulated by a FakeBackend
+type FakeGPU struct {
+	Uuid          string
+	BusId         string
+	Name          string
+	Serial        string
+	DriverVersion string
+	PowerLimit    uint // W
+	MemoryTotal   uint // MB
+	MemoryUsed    uint // MB
+
+	// Unsupported GPUs are counted by GetAllDeviceCount but not listed by GetSupportedDevices
+	Unsupported bool
+	// Board identifies the board of the GPU, GPUs with the same Board are on the same board
+	Board int
+	// Processes are the processes running on the GPU
+	Processes []DeviceProcessInfo
+}
+
+type fakeFieldKey struct {
+	gpuId uint
+	field Short
+}
+
+type fakeGroup struct {
+	name   string
+	gpuIds []uint
+	health HealthSystem
+}
+
+type fakePolicy struct {
+	groupId   C.dcgmGpuGrp_t
+	condition C.dcgmPolicyCondition_t
+	slot      int
+}
+
+// FakeBackend simulates IXDCGM and its GPUs in memory, so that code using the package can be
+// tested without libixdcgm.so nor GPUs. Use NewFakeClient or InitFake to use it.
+//
+// Field values are scripted by SetFieldInt64, SetFieldFloat64 and SetFieldString, health incidents
+// are injected by InjectHealthIncident and policy violations by InjectPolicyViolation.
+// Like the ix-hostengine, it only returns the values of watched fields and refuses to create more
+// than DCGM_MAX_NUM_GROUPS groups and DCGM_MAX_NUM_FIELD_GROUPS field groups.
+type FakeBackend struct {
+	mu          sync.Mutex
+	gpus        []FakeGPU
+	links       map[[2]uint]P2PLinkType
+	values      map[fakeFieldKey][]FieldValue_v1
+	watched     map[fakeFieldKey]bool
+	incidents   []Incident
+	diag        *DiagResults
+	groups      map[C.dcgmGpuGrp_t]*fakeGroup
+	fieldGroups map[C.dcgmFieldGrp_t][]Short
+	policySets  map[C.dcgmGpuGrp_t][]policyConditionParam
+	policies    []fakePolicy
+	nextId      uintptr
+}
+
+// NewFakeBackend returns a FakeBackend simulating gpuCount supported GPUs, each on its own board
+// and connected to each other through P2PLinkSameCPU
+func NewFakeBackend(gpuCount int) *FakeBackend {
+	f := &FakeBackend{
+		links:       make(map[[2]uint]P2PLinkType),
+		values:      make(map[fakeFieldKey][]FieldValue_v1),
+		watched:     make(map[fakeFieldKey]bool),
+		groups:      make(map[C.dcgmGpuGrp_t]*fakeGroup),
+		fieldGroups: make(map[C.dcgmFieldGrp_t][]Short),
+		policySets:  make(map[C.dcgmGpuGrp_t][]policyConditionParam),
+	}
+	for i := 0; i < gpuCount; i++ {
+		f.gpus = append(f.gpus, FakeGPU{
+			Uuid:          fmt.Sprintf("GPU-00000000-0000-0000-0000-%012x", i),
+			BusId:         fmt.Sprintf("00000000:%02X:00.0", i+1),
+			Name:          "Iluvatar BI-V100",
+			Serial:        fmt.Sprintf("FAKE%08d", i),
+			DriverVersion: SupportedDriverVersion,
+			PowerLimit:    250,
+			MemoryTotal:   32768,
+			Board:         i,
+		})
+	}
+	return f
+}
+
+// SetGPU replaces the description of the GPU
+func (f *FakeBackend) SetGPU(gpuId uint, gpu FakeGPU) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if gpuId >= uint(len(f.gpus)) {
+		return fmt.Errorf("fake gpu %d does not exist: %w", gpuId, ErrBadParam)
+	}
+	f.gpus[gpuId] = gpu
+	return nil
+}
+
+// SetP2PLink sets the link between the two GPUs reported by their topology
+func (f *FakeBackend) SetP2PLink(gpuId1, gpuId2 uint, link P2PLinkType) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.links[[2]uint{gpuId1, gpuId2}] = link
+	f.links[[2]uint{gpuId2, gpuId1}] = link
+}
+
+// SetFieldInt64 scripts the values of an int64 field of the GPU: each read returns the next
+// value and the last one is returned once all have been read
+func (f *FakeBackend) SetFieldInt64(gpuId uint, field Short, values ...int64) {
+	script := make([]FieldValue_v1, len(values))
+	for i, v := range values {
+		script[i] = FieldValue_v1{FieldType: DCGM_FT_INT64}
+		script[i].setInt64(v)
+	}
+	f.setField(gpuId, field, script)
+}
+
+// SetFieldFloat64 scripts the values of a double field of the GPU, see SetFieldInt64
+func (f *FakeBackend) SetFieldFloat64(gpuId uint, field Short, values ...float64) {
+	script := make([]FieldValue_v1, len(values))
+	for i, v := range values {
+		script[i] = FieldValue_v1{FieldType: DCGM_FT_DOUBLE}
+		script[i].setFloat64(v)
+	}
+	f.setField(gpuId, field, script)
+}
+
+// SetFieldString scripts the values of a string field of the GPU, see SetFieldInt64
+func (f *FakeBackend) SetFieldString(gpuId uint, field Short, values ...string) {
+	script := make([]FieldValue_v1, len(values))
+	for i, v := range values {
+		script[i] = FieldValue_v1{FieldType: DCGM_FT_STRING}
+		script[i].setBytes([]byte(v))
+	}
+	f.setField(gpuId, field, script)
+}
+
+func (f *FakeBackend) setField(gpuId uint, field Short, script []FieldValue_v1) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := fakeFieldKey{gpuId, field}
+	if len(script) == 0 {
+		delete(f.values, key)
+		return
+	}
+	for i := range script {
+		script[i].FieldId = uint(field)
+	}
+	f.values[key] = script
+}
+
+// InjectHealthIncident makes the health checks of the groups holding the GPU of the incident,
+// which watch the health system of the incident, report it until ClearHealthIncidents is called
+func (f *FakeBackend) InjectHealthIncident(incident Incident) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if incident.EntityInfo.EntityGroupId == FE_NONE {
+		incident.EntityInfo.EntityGroupId = FE_GPU
+	}
+	f.incidents = append(f.incidents, incident)
+}
+
+// ClearHealthIncidents removes all the injected health incidents
+func (f *FakeBackend) ClearHealthIncidents() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.incidents = nil
+}
+
+// InjectPolicyViolation delivers the violation to the listeners of its condition registered
+// on this backend on a group holding its GPU. It returns false if no such listener is registered.
+func (f *FakeBackend) InjectPolicyViolation(violation PolicyViolation) bool {
+	bit := policyConditionBit(violation.Condition)
+	gpuId, ok := policyViolationGpu(violation)
+	if bit == 0 || !ok {
+		return false
+	}
+
+	f.mu.Lock()
+	var slots []int
+	for _, p := range f.policies {
+		if p.condition&bit != 0 && f.groupHoldsGpu(p.groupId, gpuId) {
+			slots = append(slots, p.slot)
+		}
+	}
+	f.mu.Unlock()
+
+	for _, slot := range slots {
+		deliverPolicyViolation(slot, violation)
+	}
+	return len(slots) > 0
+}
+
+// SetDiagResults sets the results returned by RunDiag, by default every GPU of the group passes
+func (f *FakeBackend) SetDiagResults(results DiagResults) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.diag = &results
+}
+
+// GroupCount returns the number of groups which exist in the fake
+func (f *FakeBackend) GroupCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.groups)
+}
+
+// FieldGroupCount returns the number of field groups which exist in the fake
+func (f *FakeBackend) FieldGroupCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.fieldGroups)
+}
+
+// NewFakeClient returns a client which uses f instead of the IXDCGM library
+func NewFakeClient(f *FakeBackend) *Client {
+	return &Client{be: f}
+}
+
+// InitFake makes the package level functions use f instead of the IXDCGM library,
+// it is the counterpart of InitWithConfig for tests
+func InitFake(f *FakeBackend) (cleanup func(), err error) {
+	return initWithBackend(f)
+}
+
+func initWithBackend(b backend) (cleanup func(), err error) {
+	mux.Lock()
+	defer mux.Unlock()
+	if ixdcgmInitCounter > 0 {
+		return nil, nil
+	}
+	defaultClient.adopt(&Client{be: b})
+
+	ixdcgmInitCounter += 1
+	cleanup = func() {
+		shutdown()
+	}
+	return cleanup, nil
+}
+
+func (f *FakeBackend) gpuExists(gpuId uint) bool {
+	return gpuId < uint(len(f.gpus))
+}
+
+// groupGpus returns the GPUs of the group, f.mu must be held
+func (f *FakeBackend) groupGpus(groupId C.dcgmGpuGrp_t) ([]uint, bool) {
+	if groupId == C.DCGM_GROUP_ALL_GPUS {
+		gpus := make([]uint, len(f.gpus))
+		for i := range gpus {
+			gpus[i] = uint(i)
+		}
+		return gpus, true
+	}
+	grp, exists := f.groups[groupId]
+	if !exists {
+		return nil, false
+	}
+	return grp.gpuIds, true
+}
+
+func (f *FakeBackend) groupHoldsGpu(groupId C.dcgmGpuGrp_t, gpuId uint) bool {
+	gpus, _ := f.groupGpus(groupId)
+	for _, id := range gpus {
+		if id == gpuId {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FakeBackend) getAllDevices() ([]uint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	gpus, _ := f.groupGpus(C.DCGM_GROUP_ALL_GPUS)
+	return gpus, nil
+}
+
+func (f *FakeBackend) getSupportedDevices() ([]uint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	gpus := []uint{}
+	for i, gpu := range f.gpus {
+		if !gpu.Unsupported {
+			gpus = append(gpus, uint(i))
+		}
+	}
+	return gpus, nil
+}
+
+// getEntities lists the GPUs, the entity groups other than GPUs are not simulated and have no entities
+func (f *FakeBackend) getEntities(group Field_Entity_Group, onlySupported bool) ([]uint, error) {
+	if group != FE_GPU {
+		return []uint{}, nil
+	}
+	if onlySupported {
+		return f.getSupportedDevices()
+	}
+	return f.getAllDevices()
+}
+
+func (f *FakeBackend) getDeviceAttributes(gpuId uint) (deviceAttributes, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.gpuExists(gpuId) {
+		return deviceAttributes{}, fmt.Errorf("Error getting attributes of gpu %d: %w", gpuId, newDcgmError(DCGM_ST_BADPARAM))
+	}
+	gpu := f.gpus[gpuId]
+	return deviceAttributes{
+		Uuid:          gpu.Uuid,
+		BusId:         gpu.BusId,
+		DeviceName:    gpu.Name,
+		Serial:        gpu.Serial,
+		DriverVersion: gpu.DriverVersion,
+		PowerLimit:    gpu.PowerLimit,
+		FbTotal:       gpu.MemoryTotal,
+		FbUsed:        gpu.MemoryUsed,
+		FbFree:        gpu.MemoryTotal - gpu.MemoryUsed,
+	}, nil
+}
+
+func (f *FakeBackend) getDeviceTopology(gpuId uint) ([]P2PLink, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.gpuExists(gpuId) {
+		return nil, fmt.Errorf("Error getting topology of gpu %d: %w", gpuId, newDcgmError(DCGM_ST_BADPARAM))
+	}
+
+	var links []P2PLink
+	for i := range f.gpus {
+		peer := uint(i)
+		if peer == gpuId {
+			continue
+		}
+		links = append(links, P2PLink{GPU: peer, Link: f.link(gpuId, peer)})
+	}
+	return links, nil
+}
+
+// link returns the link between two GPUs, P2PLinkSameBoard or P2PLinkSameCPU unless set by SetP2PLink, f.mu must be held
+func (f *FakeBackend) link(gpuId1, gpuId2 uint) P2PLinkType {
+	if link, exists := f.links[[2]uint{gpuId1, gpuId2}]; exists {
+		return link
+	}
+	if f.gpus[gpuId1].Board == f.gpus[gpuId2].Board {
+		return P2PLinkSameBoard
+	}
+	return P2PLinkSameCPU
+}
+
+// selectGpusByTopology is not simulated, SelectGpus selects the GPUs from their topology instead
+func (f *FakeBackend) selectGpusByTopology(inputMask uint64, numGpus uint32, hintFlags uint64) (uint64, error) {
+	return 0, fmt.Errorf("Error selecting gpus by topology: %w", newDcgmError(DCGM_ST_NOT_SUPPORTED))
+}
+
+// getGroupTopology combines the links of the GPUs of the group and their CPU affinity set by SetFieldInt64
+func (f *FakeBackend) getGroupTopology(groupId C.dcgmGpuGrp_t) (groupTopology, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	gpus, exists := f.groupGpus(groupId)
+	if !exists {
+		return groupTopology{}, fmt.Errorf("Error getting topology of group: %w", newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+
+	cpuFields, _ := affinityFields("CPU")
+	topology := groupTopology{NumaOptimal: true}
+	for i, gpuId := range gpus {
+		mask := make([]uint64, len(cpuFields))
+		for w, field := range cpuFields {
+			if script := f.values[fakeFieldKey{gpuId, field}]; len(script) > 0 {
+				mask[w] = uint64(script[0].Int64())
+			}
+		}
+		if i == 0 {
+			topology.CpuAffinityMask = mask
+		}
+		for w := range mask {
+			if mask[w] != topology.CpuAffinityMask[w] {
+				topology.NumaOptimal = false
+			}
+			topology.CpuAffinityMask[w] &= mask[w]
+		}
+
+		for _, peer := range gpus[i+1:] {
+			if link := f.link(gpuId, peer); topology.SlowestPath == P2PLinkUnknown || link < topology.SlowestPath {
+				topology.SlowestPath = link
+			}
+		}
+	}
+	return topology, nil
+}
+
+func (f *FakeBackend) getDeviceOnSameBoard(gpuId1, gpuId2 uint) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.gpuExists(gpuId1) || !f.gpuExists(gpuId2) {
+		return false, fmt.Errorf("Error checking whether gpu %d and gpu %d are on the same board: %w",
+			gpuId1, gpuId2, newDcgmError(DCGM_ST_BADPARAM))
+	}
+	return f.gpus[gpuId1].Board == f.gpus[gpuId2].Board, nil
+}
+
+func (f *FakeBackend) getDeviceRunningProcesses(gpuId uint) ([]processMemory, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.gpuExists(gpuId) {
+		return nil, fmt.Errorf("Error getting running processes of gpu %d: %w", gpuId, newDcgmError(DCGM_ST_BADPARAM))
+	}
+	processes := make([]processMemory, len(f.gpus[gpuId].Processes))
+	for i, p := range f.gpus[gpuId].Processes {
+		processes[i] = processMemory{Pid: p.Pid, UsedMemoryBytes: p.UsedGpuMemory * 1024 * 1024}
+	}
+	return processes, nil
+}
+
+func (f *FakeBackend) newId() uintptr {
+	f.nextId += 1
+	return f.nextId
+}
+
+// groupCreate creates a group, the default groups of the entities other than GPUs are empty as they are not simulated
+func (f *FakeBackend) groupCreate(groupType GroupType, groupName string) (C.dcgmGpuGrp_t, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if groupType < GroupDefault || groupType > GroupDefaultEverything {
+		return 0, fmt.Errorf("Error creating group %s: %w", groupName, newDcgmError(DCGM_ST_BADPARAM))
+	}
+	if len(f.groups) >= C.DCGM_MAX_NUM_GROUPS {
+		return 0, fmt.Errorf("Error creating group %s: %w", groupName, newDcgmError(DCGM_ST_MAX_LIMIT))
+	}
+	grp := &fakeGroup{name: groupName}
+	if groupType == GroupDefault || groupType == GroupDefaultEverything {
+		for i, gpu := range f.gpus {
+			if !gpu.Unsupported {
+				grp.gpuIds = append(grp.gpuIds, uint(i))
+			}
+		}
+	}
+	groupId := C.dcgmGpuGrp_t(f.newId())
+	f.groups[groupId] = grp
+	return groupId, nil
+}
+
+func (f *FakeBackend) groupAddDevice(groupId C.dcgmGpuGrp_t, gpuId uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	grp, exists := f.groups[groupId]
+	if !exists {
+		return fmt.Errorf("Error adding gpu %d to group: %w", gpuId, newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+	if !f.gpuExists(gpuId) || f.groupHoldsGpu(groupId, gpuId) {
+		return fmt.Errorf("Error adding gpu %d to group: %w", gpuId, newDcgmError(DCGM_ST_BADPARAM))
+	}
+	grp.gpuIds = append(grp.gpuIds, gpuId)
+	return nil
+}
+
+// groupAddEntity adds a GPU to the group, the other entities are not simulated
+func (f *FakeBackend) groupAddEntity(groupId C.dcgmGpuGrp_t, entityGroupId Field_Entity_Group, entityId uint) error {
+	if entityGroupId != FE_GPU {
+		return fmt.Errorf("Error adding %s %d to group: %w", entityGroupId, entityId, newDcgmError(DCGM_ST_BADPARAM))
+	}
+	return f.groupAddDevice(groupId, entityId)
+}
+
+func (f *FakeBackend) groupRemoveDevice(groupId C.dcgmGpuGrp_t, gpuId uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	grp, exists := f.groups[groupId]
+	if !exists {
+		return fmt.Errorf("Error removing gpu %d from group: %w", gpuId, newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+	for i, id := range grp.gpuIds {
+		if id == gpuId {
+			grp.gpuIds = append(grp.gpuIds[:i:i], grp.gpuIds[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("Error removing gpu %d from group: %w", gpuId, newDcgmError(DCGM_ST_BADPARAM))
+}
+
+func (f *FakeBackend) groupRemoveEntity(groupId C.dcgmGpuGrp_t, entityGroupId Field_Entity_Group, entityId uint) error {
+	if entityGroupId != FE_GPU {
+		return fmt.Errorf("Error removing %s %d from group: %w", entityGroupId, entityId, newDcgmError(DCGM_ST_BADPARAM))
+	}
+	return f.groupRemoveDevice(groupId, entityId)
+}
+
+func (f *FakeBackend) groupDestroy(groupId C.dcgmGpuGrp_t) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, exists := f.groups[groupId]; !exists {
+		return fmt.Errorf("Error destroying group: %w", newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+	delete(f.groups, groupId)
+
+	policies := f.policies[:0]
+	for _, p := range f.policies {
+		if p.groupId != groupId {
+			policies = append(policies, p)
+		}
+	}
+	f.policies = policies
+	return nil
+}
+
+func (f *FakeBackend) groupGetInfo(groupId C.dcgmGpuGrp_t) (*GroupInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	gpus, exists := f.groupGpus(groupId)
+	if !exists {
+		return nil, fmt.Errorf("Error getting group info: %w", newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+
+	info := &GroupInfo{Version: 2, GroupName: "DCGM_ALL_SUPPORTED_GPUS"}
+	if grp, exists := f.groups[groupId]; exists {
+		info.GroupName = grp.name
+	}
+	for _, gpuId := range gpus {
+		info.EntityList = append(info.EntityList, GroupEntityPair{EntityGroupId: FE_GPU, EntityId: gpuId})
+	}
+	return info, nil
+}
+
+func (f *FakeBackend) groupGetAllIds() ([]C.dcgmGpuGrp_t, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]C.dcgmGpuGrp_t, 0, len(f.groups))
+	for id := range f.groups {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (f *FakeBackend) fieldGroupCreate(groupName string, fields []Short) (C.dcgmFieldGrp_t, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("error creating DCGM fields group: %w", newDcgmError(DCGM_ST_BADPARAM))
+	}
+	if len(f.fieldGroups) >= C.DCGM_MAX_NUM_FIELD_GROUPS {
+		return 0, fmt.Errorf("error creating DCGM fields group: %w", newDcgmError(DCGM_ST_MAX_LIMIT))
+	}
+	fieldGroupId := C.dcgmFieldGrp_t(f.newId())
+	f.fieldGroups[fieldGroupId] = append([]Short(nil), fields...)
+	return fieldGroupId, nil
+}
+
+func (f *FakeBackend) fieldGroupDestroy(fieldGroupId C.dcgmFieldGrp_t) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, exists := f.fieldGroups[fieldGroupId]; !exists {
+		return fmt.Errorf("error destroying DCGM fields group: %w", newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+	delete(f.fieldGroups, fieldGroupId)
+	return nil
+}
+
+func (f *FakeBackend) watchFields(
+	groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t, updateFreq int64, maxKeepAge float64, maxKeepSamples int32,
+) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	gpus, exists := f.groupGpus(groupId)
+	fields, fgExists := f.fieldGroups[fieldGroupId]
+	if !exists || !fgExists {
+		return fmt.Errorf("Error watching fields: %w", newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+	for _, gpuId := range gpus {
+		for _, field := range fields {
+			f.watched[fakeFieldKey{gpuId, field}] = true
+		}
+	}
+	return nil
+}
+
+func (f *FakeBackend) unwatchFields(groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	gpus, exists := f.groupGpus(groupId)
+	fields, fgExists := f.fieldGroups[fieldGroupId]
+	if !exists || !fgExists {
+		return fmt.Errorf("error unwatching fields: %w", newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+	for _, gpuId := range gpus {
+		for _, field := range fields {
+			delete(f.watched, fakeFieldKey{gpuId, field})
+		}
+	}
+	return nil
+}
+
+func (f *FakeBackend) updateAllFields(waitForUpdate bool) error {
+	return nil
+}
+
+func (f *FakeBackend) getLatestValuesForFields(gpuId uint, fields []Short) ([]FieldValue_v1, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(fields) == 0 || !f.gpuExists(gpuId) {
+		return nil, fmt.Errorf("error getting latest DCGM fields values: %w", newDcgmError(DCGM_ST_BADPARAM))
+	}
+
+	ts := time.Now().UnixMicro()
+	values := make([]FieldValue_v1, len(fields))
+	for i, field := range fields {
+		values[i] = f.nextValue(fakeFieldKey{gpuId, field}, ts)
+	}
+	return values, nil
+}
+
+// nextValue reads the next scripted value of a field, f.mu must be held
+func (f *FakeBackend) nextValue(key fakeFieldKey, ts int64) FieldValue_v1 {
+	var fv FieldValue_v1
+	script := f.values[key]
+	switch {
+	case !f.watched[key]:
+		fv = blankFieldValue(key.field, DCGM_ST_NOT_WATCHED)
+	case len(script) == 0:
+		fv = blankFieldValue(key.field, DCGM_ST_NO_DATA)
+	default:
+		fv = script[0]
+		if len(script) > 1 {
+			f.values[key] = script[1:]
+		}
+	}
+	fv.Version = 1
+	fv.Ts = ts
+	return fv
+}
+
+// getValuesSince returns a new value of every watched field which has scripted values,
+// as if each one was updated once since the previous call
+func (f *FakeBackend) getValuesSince(
+	groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t, since int64,
+) ([]entityValue, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	gpus, exists := f.groupGpus(groupId)
+	fields, fgExists := f.fieldGroups[fieldGroupId]
+	if !exists || !fgExists {
+		return nil, since, fmt.Errorf("error getting DCGM field values since %d: %w", since, newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+
+	ts := max(time.Now().UnixMicro(), since)
+	var values []entityValue
+	for _, gpuId := range gpus {
+		for _, field := range fields {
+			key := fakeFieldKey{gpuId, field}
+			if f.watched[key] && len(f.values[key]) > 0 {
+				values = append(values, entityValue{GroupEntityPair{FE_GPU, gpuId}, f.nextValue(key, ts)})
+			}
+		}
+	}
+	return values, ts + 1, nil
+}
+
+func (f *FakeBackend) entityGetLatestValues(entity GroupEntityPair, fields []Short) ([]FieldValue_v1, error) {
+	if entity.EntityGroupId != FE_GPU {
+		return nil, fmt.Errorf("error getting latest DCGM fields values of %s %d: %w",
+			entity.EntityGroupId, entity.EntityId, newDcgmError(DCGM_ST_BADPARAM))
+	}
+	return f.getLatestValuesForFields(entity.EntityId, fields)
+}
+
+// blankFieldValue is the value of a field without any data, which is read as blank whatever its type
+func blankFieldValue(field Short, status ReturnCode) FieldValue_v1 {
+	fv := FieldValue_v1{FieldId: uint(field), FieldType: DCGM_FT_INT64, Status: status}
+	fv.setInt64(DCGM_FT_INT64_BLANK)
+	return fv
+}
+
+func (f *FakeBackend) healthSet(groupId C.dcgmGpuGrp_t, systems HealthSystem) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	grp, exists := f.groups[groupId]
+	if !exists {
+		return fmt.Errorf("error setting health watches: %w", newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+	grp.health = systems
+	return nil
+}
+
+func (f *FakeBackend) healthGet(groupId C.dcgmGpuGrp_t) (HealthSystem, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	grp, exists := f.groups[groupId]
+	if !exists {
+		return HealthSystem(0), newDcgmError(DCGM_ST_NOT_CONFIGURED)
+	}
+	return grp.health, nil
+}
+
+func (f *FakeBackend) healthCheck(groupId C.dcgmGpuGrp_t) (HealthResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	grp, exists := f.groups[groupId]
+	if !exists {
+		return HealthResponse{}, fmt.Errorf("Error checking health: %w", newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+
+	response := HealthResponse{OverallHealth: DCGM_HEALTH_RESULT_PASS, Incidents: []Incident{}}
+	for _, incident := range f.incidents {
+		if incident.System&grp.health == 0 || incident.EntityInfo.EntityGroupId != FE_GPU ||
+			!f.groupHoldsGpu(groupId, incident.EntityInfo.EntityId) {
+			continue
+		}
+		response.Incidents = append(response.Incidents, incident)
+		if incident.Health > response.OverallHealth {
+			response.OverallHealth = incident.Health
+		}
+	}
+	return response, nil
+}
+
+func (f *FakeBackend) runDiag(groupId C.dcgmGpuGrp_t, diagType DiagType) (DiagResults, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	gpus, exists := f.groupGpus(groupId)
+	if !exists {
+		return DiagResults{}, fmt.Errorf("Error running diagnostic: %w", newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+	if f.diag != nil {
+		return *f.diag, nil
+	}
+
+	results := DiagResults{}
+	for _, gpuId := range gpus {
+		results.PerGpu = append(results.PerGpu, GpuResult{GPU: gpuId})
+	}
+	return results, nil
+}
+
+func (f *FakeBackend) policySet(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t, paramList []policyConditionParam) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, exists := f.groupGpus(groupId); !exists {
+		return fmt.Errorf("Error setting policies: %w", newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+	f.policySets[groupId] = append([]policyConditionParam(nil), paramList...)
+	return nil
+}
+
+func (f *FakeBackend) policyRegister(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t, slot int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, exists := f.groupGpus(groupId); !exists {
+		return fmt.Errorf("Error registering policy: %w", newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+	f.policies = append(f.policies, fakePolicy{groupId: groupId, condition: condition, slot: slot})
+	return nil
+}
+
+// policyUnregister drops every registration of the condition on the group, as the ix-hostengine does
+func (f *FakeBackend) policyUnregister(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	policies := f.policies[:0]
+	for _, p := range f.policies {
+		if p.groupId != groupId || p.condition != condition {
+			policies = append(policies, p)
+		}
+	}
+	if len(policies) == len(f.policies) {
+		return fmt.Errorf("Error unregistering policy: %w", newDcgmError(DCGM_ST_NOT_CONFIGURED))
+	}
+	f.policies = policies
+	return nil
+}
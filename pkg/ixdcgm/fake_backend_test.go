@@ -0,0 +1,100 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"errors"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestInitFake(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(3)
+	cleanup, err := ixdcgm.InitFake(fake)
+	if err != nil {
+		t.Fatalf("InitFake failed: %v", err)
+	}
+	defer cleanup()
+
+	count, err := ixdcgm.GetAllDeviceCount()
+	if err != nil || count != 3 {
+		t.Errorf("GetAllDeviceCount = %d, %v, want 3", count, err)
+	}
+	if _, err = ixdcgm.GetHostengineMemoryUsage(); !errors.Is(err, ixdcgm.ErrNotSupported) {
+		t.Errorf("GetHostengineMemoryUsage without the library returned %v, want ErrNotSupported", err)
+	}
+
+	// only the first call returns a cleanup
+	again, err := ixdcgm.InitFake(ixdcgm.NewFakeBackend(1))
+	if err != nil || again != nil {
+		t.Errorf("second InitFake = %v, %v, want a nil cleanup", again != nil, err)
+	}
+	if count, err = ixdcgm.GetAllDeviceCount(); err != nil || count != 3 {
+		t.Errorf("GetAllDeviceCount after the second InitFake = %d, %v, want 3", count, err)
+	}
+}
+
+func TestFakeBackendLatestValuesOfUnwatchedFields(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(1)
+	fake.SetFieldInt64(0, ixdcgm.DCGM_FI_DEV_GPU_TEMP, 50)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	fields := []ixdcgm.Short{ixdcgm.DCGM_FI_DEV_GPU_TEMP}
+	values, err := client.GetLatestValuesForFields(0, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[0].Status != ixdcgm.DCGM_ST_NOT_WATCHED {
+		t.Errorf("status of an unwatched field = %d, want DCGM_ST_NOT_WATCHED", values[0].Status)
+	}
+
+	fieldGroup, err := client.FieldGroupCreate("temperature", fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.FieldGroupDestroy(fieldGroup)
+	group, err := client.WatchFields([]uint{0}, fieldGroup, "temperature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.DestroyGroup(group)
+
+	values, err = client.GetLatestValuesForFields(0, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[0].Status != ixdcgm.DCGM_ST_OK || values[0].Int64() != 50 {
+		t.Errorf("watched field = %d (status %d), want 50", values[0].Int64(), values[0].Status)
+	}
+}
+
+func TestFakeBackendMaxGroups(t *testing.T) {
+	client := ixdcgm.NewFakeClient(ixdcgm.NewFakeBackend(1))
+	defer client.Close()
+
+	// the ix-hostengine holds at most DCGM_MAX_NUM_GROUPS (64) groups
+	var err error
+	for i := 0; i <= 64 && err == nil; i++ {
+		_, err = client.CreateGroup("group")
+	}
+	if !errors.Is(err, ixdcgm.ErrMaxLimit) {
+		t.Errorf("creating too many groups returned %v, want ErrMaxLimit", err)
+	}
+}
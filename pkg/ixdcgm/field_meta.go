@@ -0,0 +1,224 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include <stdlib.h>
+#include "include/dcgm_fields.h"
+*/
+import "C"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+//go:generate go run gen_field_meta.go
+
+// FieldMeta describes a field. Id, Name and Description are known from dcgm_fields.h and the Size of the
+// 32-bit fields from the registry, the other attributes are read from the IXDCGM library and are zero until
+// it is loaded.
+type FieldMeta struct {
+	Id          Short
+	Name        string // e.g. "DCGM_FI_DEV_GPU_TEMP"
+	Description string // e.g. "Current temperature readings for the device, in degrees C"
+
+	Tag         string             // e.g. "gpu_temp"
+	Type        uint               // type of the values, e.g. DCGM_FT_INT64
+	Size        uint               // size of the raw values in bytes, e.g. 4 for 32-bit integers, 0 if it varies
+	Unit        string             // e.g. "C"
+	ShortName   string             // name of the column of the field in ixdcgmi dmon, e.g. "TMPTR"
+	EntityLevel Field_Entity_Group // entity group the field is read for, e.g. FE_GPU
+}
+
+var (
+	fieldIndexOnce sync.Once
+	fieldsById     map[Short]int
+	fieldsByName   map[string]int
+)
+
+// fieldIndex indexes fieldMetaTable by id and by name. The names are upper case, each field is found by its
+// full name, e.g. DCGM_FI_DEV_GPU_TEMP, and its name without prefix, e.g. DEV_GPU_TEMP or GPU_TEMP.
+func fieldIndex() (map[Short]int, map[string]int) {
+	fieldIndexOnce.Do(func() {
+		fieldsById = make(map[Short]int, len(fieldMetaTable))
+		fieldsByName = make(map[string]int, 3*len(fieldMetaTable))
+		for i, meta := range fieldMetaTable {
+			fieldsById[meta.Id] = i
+		}
+		for _, prefix := range []string{"", "DCGM_FI_", "DCGM_FI_DEV_"} {
+			for i, meta := range fieldMetaTable {
+				if !strings.HasPrefix(meta.Name, prefix) {
+					continue
+				}
+				// a full name or a shorter prefix wins over a shorter name
+				name := strings.TrimPrefix(meta.Name, prefix)
+				if _, exists := fieldsByName[name]; !exists {
+					fieldsByName[name] = i
+				}
+			}
+		}
+	})
+	return fieldsById, fieldsByName
+}
+
+// Fields returns the metadata of all the fields
+func Fields() []FieldMeta {
+	fields := make([]FieldMeta, len(fieldMetaTable))
+	for i, meta := range fieldMetaTable {
+		fields[i] = withLibraryMeta(meta)
+	}
+	return fields
+}
+
+// FieldByID returns the metadata of a field, it returns false if the field is unknown.
+// dcgm_fields.h does not give the type, size, unit nor entity level of the fields: until the IXDCGM library
+// is loaded, only Id, Name, Description and the Size of the 32-bit fields are set, the other attributes are zero.
+func FieldByID(id Short) (FieldMeta, bool) {
+	byId, _ := fieldIndex()
+	i, exists := byId[id]
+	if !exists {
+		return FieldMeta{}, false
+	}
+	return withLibraryMeta(fieldMetaTable[i]), true
+}
+
+// FieldByName returns the metadata of a field by its name, e.g. "DCGM_FI_DEV_POWER_USAGE", its name without
+// prefix, e.g. "power_usage", or its tag when the library is loaded, ignoring the case.
+// It returns false if the field is unknown. As for FieldByID, only Id, Name, Description and the Size of
+// the 32-bit fields are set until the IXDCGM library is loaded.
+func FieldByName(name string) (FieldMeta, bool) {
+	_, byName := fieldIndex()
+	if i, exists := byName[strings.ToUpper(strings.TrimSpace(name))]; exists {
+		return withLibraryMeta(fieldMetaTable[i]), true
+	}
+	if id, found := libraryFieldIdByTag(strings.TrimSpace(name)); found {
+		return FieldByID(id)
+	}
+	return FieldMeta{}, false
+}
+
+// ParseFieldList parses a comma separated list of fields, each given by a name accepted by FieldByName
+// or by its id, e.g. "DCGM_FI_DEV_GPU_TEMP,power_usage,203". It returns an error wrapping ErrUnknownField
+// if a field is unknown.
+func ParseFieldList(list string) ([]Short, error) {
+	var fields []Short
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if id, err := strconv.ParseUint(name, 10, 16); err == nil {
+			if _, exists := FieldByID(Short(id)); exists {
+				fields = append(fields, Short(id))
+				continue
+			}
+		}
+		meta, exists := FieldByName(name)
+		if !exists {
+			return nil, fmt.Errorf("invalid field list %q: %q: %w", list, name, ErrUnknownField)
+		}
+		fields = append(fields, meta.Id)
+	}
+	return fields, nil
+}
+
+// fieldsInitLib is the library DcgmFieldsInit was called for
+var fieldsInitLib *LibraryInfo
+
+// initLibraryFieldsLocked initializes the fields module of the loaded library, libMu must be held.
+// It returns false if the library is not loaded.
+func initLibraryFieldsLocked() bool {
+	if loadedLib == nil {
+		return false
+	}
+	if fieldsInitLib != loadedLib {
+		if ret := C.DcgmFieldsInit(); ret < 0 {
+			getLogger().Warn("Failed to initialize the fields of the IXDCGM library", "returnCode", int(ret))
+			return false
+		}
+		fieldsInitLib = loadedLib
+	}
+	return true
+}
+
+// withLibraryMeta completes meta with the metadata of the field read from the library, if it is loaded
+func withLibraryMeta(meta FieldMeta) FieldMeta {
+	libMu.Lock()
+	defer libMu.Unlock()
+	if !initLibraryFieldsLocked() {
+		return meta
+	}
+
+	p := C.DcgmFieldGetById(C.ushort(meta.Id))
+	if p == nil {
+		return meta
+	}
+	meta.Tag = cCharArrayString(p.tag[:])
+	meta.Type = uint(p.fieldType)
+	meta.Size = uint(p.size)
+	meta.EntityLevel = Field_Entity_Group(p.entityLevel)
+	if p.valueFormat != nil {
+		meta.Unit = cCharArrayString(p.valueFormat.unit[:])
+		meta.ShortName = cCharArrayString(p.valueFormat.shortName[:])
+	}
+	return meta
+}
+
+// fieldValueSize returns the size of the raw values of the field according to the library, or to
+// fieldMetaTable when it is not loaded. It returns 0 if the size is unknown.
+func fieldValueSize(id Short) uint {
+	libMu.Lock()
+	defer libMu.Unlock()
+	if !initLibraryFieldsLocked() {
+		byId, _ := fieldIndex()
+		if i, exists := byId[id]; exists {
+			return fieldMetaTable[i].Size
+		}
+		return 0
+	}
+	p := C.DcgmFieldGetById(C.ushort(id))
+	if p == nil {
+		return 0
+	}
+	return uint(p.size)
+}
+
+func libraryFieldIdByTag(tag string) (Short, bool) {
+	libMu.Lock()
+	defer libMu.Unlock()
+	if !initLibraryFieldsLocked() {
+		return 0, false
+	}
+
+	cTag := string2Char(tag)
+	defer freeCString(cTag)
+	p := C.DcgmFieldGetByTag(cTag)
+	if p == nil {
+		return 0, false
+	}
+	return Short(p.fieldId), true
+}
+
+// cCharArrayString converts a char array which is not NUL terminated when it is full
+func cCharArrayString(chars []C.char) string {
+	b := C.GoBytes(unsafe.Pointer(&chars[0]), C.int(len(chars)))
+	if i := strings.IndexByte(string(b), 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
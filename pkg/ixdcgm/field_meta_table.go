@@ -0,0 +1,440 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by gen_field_meta.go from include/dcgm_fields.h; DO NOT EDIT.
+
+package ixdcgm
+
+// fieldMetaTable lists the fields defined by dcgm_fields.h with their description, and the size of the
+// 32-bit fields
+var fieldMetaTable = []FieldMeta{
+	{Id: 0, Name: "DCGM_FI_UNKNOWN", Description: "NULL field"},
+	{Id: 1, Name: "DCGM_FI_DRIVER_VERSION", Description: "Driver Version"},
+	{Id: 2, Name: "DCGM_FI_NVML_VERSION", Description: "Underlying NVML version"},
+	{Id: 3, Name: "DCGM_FI_PROCESS_NAME", Description: "Process Name"},
+	{Id: 4, Name: "DCGM_FI_DEV_COUNT", Description: "Number of Devices on the node"},
+	{Id: 5, Name: "DCGM_FI_CUDA_DRIVER_VERSION", Description: "Cuda Driver Version Retrieves a number with the major value in the thousands place and the minor value in the hundreds place. CUDA 11.1 = 11100"},
+	{Id: 50, Name: "DCGM_FI_DEV_NAME", Description: "Name of the GPU device"},
+	{Id: 51, Name: "DCGM_FI_DEV_BRAND", Description: "Device Brand"},
+	{Id: 52, Name: "DCGM_FI_DEV_NVML_INDEX", Description: "NVML index of this GPU"},
+	{Id: 53, Name: "DCGM_FI_DEV_SERIAL", Description: "Device Serial Number"},
+	{Id: 54, Name: "DCGM_FI_DEV_UUID", Description: "UUID corresponding to the device"},
+	{Id: 55, Name: "DCGM_FI_DEV_MINOR_NUMBER", Description: "Device node minor number /dev/nvidia#"},
+	{Id: 56, Name: "DCGM_FI_DEV_OEM_INFOROM_VER", Description: "OEM inforom version"},
+	{Id: 57, Name: "DCGM_FI_DEV_PCI_BUSID", Description: "PCI attributes for the device"},
+	{Id: 58, Name: "DCGM_FI_DEV_PCI_COMBINED_ID", Description: "The combined 16-bit device id and 16-bit vendor id"},
+	{Id: 59, Name: "DCGM_FI_DEV_PCI_SUBSYS_ID", Description: "The 32-bit Sub System Device ID"},
+	{Id: 60, Name: "DCGM_FI_GPU_TOPOLOGY_PCI", Description: "Topology of all GPUs on the system via PCI (static)"},
+	{Id: 61, Name: "DCGM_FI_GPU_TOPOLOGY_NVLINK", Description: "Topology of all GPUs on the system via NVLINK (static)"},
+	{Id: 62, Name: "DCGM_FI_GPU_TOPOLOGY_AFFINITY", Description: "Affinity of all GPUs on the system (static)"},
+	{Id: 63, Name: "DCGM_FI_DEV_CUDA_COMPUTE_CAPABILITY", Description: "Cuda compute capability for the device. The major version is the upper 32 bits and the minor version is the lower 32 bits."},
+	{Id: 65, Name: "DCGM_FI_DEV_COMPUTE_MODE", Description: "Compute mode for the device"},
+	{Id: 66, Name: "DCGM_FI_DEV_PERSISTENCE_MODE", Description: "Persistence mode for the device Boolean: 0 is disabled, 1 is enabled"},
+	{Id: 67, Name: "DCGM_FI_DEV_MIG_MODE", Description: "MIG mode for the device Boolean: 0 is disabled, 1 is enabled"},
+	{Id: 68, Name: "DCGM_FI_DEV_CUDA_VISIBLE_DEVICES_STR", Description: "The string that CUDA_VISIBLE_DEVICES should be set to for this entity (including MIG)"},
+	{Id: 69, Name: "DCGM_FI_DEV_MIG_MAX_SLICES", Description: "The maximum number of MIG slices supported by this GPU"},
+	{Id: 70, Name: "DCGM_FI_DEV_CPU_AFFINITY_0", Description: "Device CPU affinity. part 1/8 = cpus 0 - 63"},
+	{Id: 71, Name: "DCGM_FI_DEV_CPU_AFFINITY_1", Description: "Device CPU affinity. part 1/8 = cpus 64 - 127"},
+	{Id: 72, Name: "DCGM_FI_DEV_CPU_AFFINITY_2", Description: "Device CPU affinity. part 2/8 = cpus 128 - 191"},
+	{Id: 73, Name: "DCGM_FI_DEV_CPU_AFFINITY_3", Description: "Device CPU affinity. part 3/8 = cpus 192 - 255"},
+	{Id: 74, Name: "DCGM_FI_DEV_CC_MODE", Description: "ConfidentialCompute/AmpereProtectedMemory status for this system 0 = disabled 1 = enabled"},
+	{Id: 75, Name: "DCGM_FI_DEV_MIG_ATTRIBUTES", Description: "Attributes for the given MIG device handles"},
+	{Id: 76, Name: "DCGM_FI_DEV_MIG_GI_INFO", Description: "GPU instance profile information"},
+	{Id: 77, Name: "DCGM_FI_DEV_MIG_CI_INFO", Description: "Compute instance profile information"},
+	{Id: 80, Name: "DCGM_FI_DEV_ECC_INFOROM_VER", Description: "ECC inforom version"},
+	{Id: 81, Name: "DCGM_FI_DEV_POWER_INFOROM_VER", Description: "Power management object inforom version"},
+	{Id: 82, Name: "DCGM_FI_DEV_INFOROM_IMAGE_VER", Description: "Inforom image version"},
+	{Id: 83, Name: "DCGM_FI_DEV_INFOROM_CONFIG_CHECK", Description: "Inforom configuration checksum"},
+	{Id: 84, Name: "DCGM_FI_DEV_INFOROM_CONFIG_VALID", Description: "Reads the infoROM from the flash and verifies the checksums"},
+	{Id: 85, Name: "DCGM_FI_DEV_VBIOS_VERSION", Description: "VBIOS version of the device"},
+	{Id: 86, Name: "DCGM_FI_DEV_MEM_AFFINITY_0", Description: "Device Memory node affinity, 0-63"},
+	{Id: 87, Name: "DCGM_FI_DEV_MEM_AFFINITY_1", Description: "Device Memory node affinity, 64-127"},
+	{Id: 88, Name: "DCGM_FI_DEV_MEM_AFFINITY_2", Description: "Device Memory node affinity, 128-191"},
+	{Id: 89, Name: "DCGM_FI_DEV_MEM_AFFINITY_3", Description: "Device Memory node affinity, 192-255"},
+	{Id: 90, Name: "DCGM_FI_DEV_BAR1_TOTAL", Description: "Total BAR1 of the GPU in MB"},
+	{Id: 91, Name: "DCGM_FI_SYNC_BOOST", Description: "Deprecated - Sync boost settings on the node"},
+	{Id: 92, Name: "DCGM_FI_DEV_BAR1_USED", Description: "Used BAR1 of the GPU in MB"},
+	{Id: 93, Name: "DCGM_FI_DEV_BAR1_FREE", Description: "Free BAR1 of the GPU in MB"},
+	{Id: 100, Name: "DCGM_FI_DEV_SM_CLOCK", Description: "SM clock for the device", Size: 4},
+	{Id: 101, Name: "DCGM_FI_DEV_MEM_CLOCK", Description: "Memory clock for the device", Size: 4},
+	{Id: 102, Name: "DCGM_FI_DEV_VIDEO_CLOCK", Description: "Video encoder/decoder clock for the device"},
+	{Id: 110, Name: "DCGM_FI_DEV_APP_SM_CLOCK", Description: "SM Application clocks", Size: 4},
+	{Id: 111, Name: "DCGM_FI_DEV_APP_MEM_CLOCK", Description: "Memory Application clocks", Size: 4},
+	{Id: 112, Name: "DCGM_FI_DEV_CLOCK_THROTTLE_REASONS", Description: "Current clock throttle reasons (bitmask of DCGM_CLOCKS_THROTTLE_REASON_*)"},
+	{Id: 113, Name: "DCGM_FI_DEV_MAX_SM_CLOCK", Description: "Maximum supported SM clock for the device", Size: 4},
+	{Id: 114, Name: "DCGM_FI_DEV_MAX_MEM_CLOCK", Description: "Maximum supported Memory clock for the device", Size: 4},
+	{Id: 115, Name: "DCGM_FI_DEV_MAX_VIDEO_CLOCK", Description: "Maximum supported Video encoder/decoder clock for the device"},
+	{Id: 120, Name: "DCGM_FI_DEV_AUTOBOOST", Description: "Auto-boost for the device (1 = enabled. 0 = disabled)"},
+	{Id: 130, Name: "DCGM_FI_DEV_SUPPORTED_CLOCKS", Description: "Supported clocks for the device"},
+	{Id: 140, Name: "DCGM_FI_DEV_MEMORY_TEMP", Description: "Memory temperature for the device", Size: 4},
+	{Id: 150, Name: "DCGM_FI_DEV_GPU_TEMP", Description: "Current temperature readings for the device, in degrees C", Size: 4},
+	{Id: 151, Name: "DCGM_FI_DEV_MEM_MAX_OP_TEMP", Description: "Maximum operating temperature for the memory of this GPU"},
+	{Id: 152, Name: "DCGM_FI_DEV_GPU_MAX_OP_TEMP", Description: "Maximum operating temperature for this GPU"},
+	{Id: 155, Name: "DCGM_FI_DEV_POWER_USAGE", Description: "Power usage for the device in Watts"},
+	{Id: 156, Name: "DCGM_FI_DEV_TOTAL_ENERGY_CONSUMPTION", Description: "Total energy consumption for the GPU in mJ since the driver was last reloaded"},
+	{Id: 157, Name: "DCGM_FI_DEV_POWER_USAGE_INSTANT", Description: "Current instantaneous power usage of the device in Watts"},
+	{Id: 158, Name: "DCGM_FI_DEV_SLOWDOWN_TEMP", Description: "Slowdown temperature for the device", Size: 4},
+	{Id: 159, Name: "DCGM_FI_DEV_SHUTDOWN_TEMP", Description: "Shutdown temperature for the device", Size: 4},
+	{Id: 160, Name: "DCGM_FI_DEV_POWER_MGMT_LIMIT", Description: "Current Power limit for the device"},
+	{Id: 161, Name: "DCGM_FI_DEV_POWER_MGMT_LIMIT_MIN", Description: "Minimum power management limit for the device"},
+	{Id: 162, Name: "DCGM_FI_DEV_POWER_MGMT_LIMIT_MAX", Description: "Maximum power management limit for the device"},
+	{Id: 163, Name: "DCGM_FI_DEV_POWER_MGMT_LIMIT_DEF", Description: "Default power management limit for the device"},
+	{Id: 164, Name: "DCGM_FI_DEV_ENFORCED_POWER_LIMIT", Description: "Effective power limit that the driver enforces after taking into account all limiters"},
+	{Id: 190, Name: "DCGM_FI_DEV_PSTATE", Description: "Performance state (P-State) 0-15. 0=highest", Size: 4},
+	{Id: 191, Name: "DCGM_FI_DEV_FAN_SPEED", Description: "Fan speed for the device in percent 0-100", Size: 4},
+	{Id: 200, Name: "DCGM_FI_DEV_PCIE_TX_THROUGHPUT", Description: "PCIe Tx utilization information Deprecated: Use DCGM_FI_PROF_PCIE_TX_BYTES instead."},
+	{Id: 201, Name: "DCGM_FI_DEV_PCIE_RX_THROUGHPUT", Description: "PCIe Rx utilization information Deprecated: Use DCGM_FI_PROF_PCIE_RX_BYTES instead."},
+	{Id: 202, Name: "DCGM_FI_DEV_PCIE_REPLAY_COUNTER", Description: "PCIe replay counter"},
+	{Id: 203, Name: "DCGM_FI_DEV_GPU_UTIL", Description: "GPU Utilization", Size: 4},
+	{Id: 204, Name: "DCGM_FI_DEV_MEM_COPY_UTIL", Description: "Memory Utilization", Size: 4},
+	{Id: 205, Name: "DCGM_FI_DEV_ACCOUNTING_DATA", Description: "Process accounting stats. This field is only supported when the host engine is running as root unless you enable accounting ahead of time. Accounting mode can be enabled by running \"nvidia-smi -am 1\" as root on the same node the host engine is running on."},
+	{Id: 206, Name: "DCGM_FI_DEV_ENC_UTIL", Description: "Encoder Utilization", Size: 4},
+	{Id: 207, Name: "DCGM_FI_DEV_DEC_UTIL", Description: "Decoder Utilization", Size: 4},
+	{Id: 230, Name: "DCGM_FI_DEV_XID_ERRORS", Description: "XID errors. The value is the specific XID error"},
+	{Id: 235, Name: "DCGM_FI_DEV_PCIE_MAX_LINK_GEN", Description: "PCIe Max Link Generation"},
+	{Id: 236, Name: "DCGM_FI_DEV_PCIE_MAX_LINK_WIDTH", Description: "PCIe Max Link Width"},
+	{Id: 237, Name: "DCGM_FI_DEV_PCIE_LINK_GEN", Description: "PCIe Current Link Generation", Size: 4},
+	{Id: 238, Name: "DCGM_FI_DEV_PCIE_LINK_WIDTH", Description: "PCIe Current Link Width", Size: 4},
+	{Id: 240, Name: "DCGM_FI_DEV_POWER_VIOLATION", Description: "Power Violation time in usec"},
+	{Id: 241, Name: "DCGM_FI_DEV_THERMAL_VIOLATION", Description: "Thermal Violation time in usec"},
+	{Id: 242, Name: "DCGM_FI_DEV_SYNC_BOOST_VIOLATION", Description: "Sync Boost Violation time in usec"},
+	{Id: 243, Name: "DCGM_FI_DEV_BOARD_LIMIT_VIOLATION", Description: "Board violation limit."},
+	{Id: 244, Name: "DCGM_FI_DEV_LOW_UTIL_VIOLATION", Description: "Low utilisation violation limit."},
+	{Id: 245, Name: "DCGM_FI_DEV_RELIABILITY_VIOLATION", Description: "Reliability violation limit."},
+	{Id: 246, Name: "DCGM_FI_DEV_TOTAL_APP_CLOCKS_VIOLATION", Description: "App clock violation limit."},
+	{Id: 247, Name: "DCGM_FI_DEV_TOTAL_BASE_CLOCKS_VIOLATION", Description: "Base clock violation limit."},
+	{Id: 250, Name: "DCGM_FI_DEV_FB_TOTAL", Description: "Total Frame Buffer of the GPU in MB"},
+	{Id: 251, Name: "DCGM_FI_DEV_FB_FREE", Description: "Free Frame Buffer in MB"},
+	{Id: 252, Name: "DCGM_FI_DEV_FB_USED", Description: "Used Frame Buffer in MB"},
+	{Id: 253, Name: "DCGM_FI_DEV_FB_RESERVED", Description: "Reserved Frame Buffer in MB"},
+	{Id: 254, Name: "DCGM_FI_DEV_FB_USED_PERCENT", Description: "Percentage used of Frame Buffer: 'Used/(Total - Reserved)'. Range 0.0-1.0"},
+	{Id: 285, Name: "DCGM_FI_DEV_C2C_LINK_COUNT", Description: "C2C Link Count"},
+	{Id: 286, Name: "DCGM_FI_DEV_C2C_LINK_STATUS", Description: "C2C Link Status The value of 0 the link is INACTIVE. The value of 1 the link is ACTIVE."},
+	{Id: 287, Name: "DCGM_FI_DEV_C2C_MAX_BANDWIDTH", Description: "C2C Max Bandwidth The value indicates the link speed in MB/s."},
+	{Id: 300, Name: "DCGM_FI_DEV_ECC_CURRENT", Description: "Current ECC mode for the device"},
+	{Id: 301, Name: "DCGM_FI_DEV_ECC_PENDING", Description: "Pending ECC mode for the device"},
+	{Id: 310, Name: "DCGM_FI_DEV_ECC_SBE_VOL_TOTAL", Description: "Total single bit volatile ECC errors"},
+	{Id: 311, Name: "DCGM_FI_DEV_ECC_DBE_VOL_TOTAL", Description: "Total double bit volatile ECC errors"},
+	{Id: 312, Name: "DCGM_FI_DEV_ECC_SBE_AGG_TOTAL", Description: "Total single bit aggregate (persistent) ECC errors Note: monotonically increasing"},
+	{Id: 313, Name: "DCGM_FI_DEV_ECC_DBE_AGG_TOTAL", Description: "Total double bit aggregate (persistent) ECC errors Note: monotonically increasing"},
+	{Id: 314, Name: "DCGM_FI_DEV_ECC_SBE_VOL_L1", Description: "L1 cache single bit volatile ECC errors"},
+	{Id: 315, Name: "DCGM_FI_DEV_ECC_DBE_VOL_L1", Description: "L1 cache double bit volatile ECC errors"},
+	{Id: 316, Name: "DCGM_FI_DEV_ECC_SBE_VOL_L2", Description: "L2 cache single bit volatile ECC errors"},
+	{Id: 317, Name: "DCGM_FI_DEV_ECC_DBE_VOL_L2", Description: "L2 cache double bit volatile ECC errors"},
+	{Id: 318, Name: "DCGM_FI_DEV_ECC_SBE_VOL_DEV", Description: "Device memory single bit volatile ECC errors"},
+	{Id: 319, Name: "DCGM_FI_DEV_ECC_DBE_VOL_DEV", Description: "Device memory double bit volatile ECC errors"},
+	{Id: 320, Name: "DCGM_FI_DEV_ECC_SBE_VOL_REG", Description: "Register file single bit volatile ECC errors"},
+	{Id: 321, Name: "DCGM_FI_DEV_ECC_DBE_VOL_REG", Description: "Register file double bit volatile ECC errors"},
+	{Id: 322, Name: "DCGM_FI_DEV_ECC_SBE_VOL_TEX", Description: "Texture memory single bit volatile ECC errors"},
+	{Id: 323, Name: "DCGM_FI_DEV_ECC_DBE_VOL_TEX", Description: "Texture memory double bit volatile ECC errors"},
+	{Id: 324, Name: "DCGM_FI_DEV_ECC_SBE_AGG_L1", Description: "L1 cache single bit aggregate (persistent) ECC errors Note: monotonically increasing"},
+	{Id: 325, Name: "DCGM_FI_DEV_ECC_DBE_AGG_L1", Description: "L1 cache double bit aggregate (persistent) ECC errors Note: monotonically increasing"},
+	{Id: 326, Name: "DCGM_FI_DEV_ECC_SBE_AGG_L2", Description: "L2 cache single bit aggregate (persistent) ECC errors Note: monotonically increasing"},
+	{Id: 327, Name: "DCGM_FI_DEV_ECC_DBE_AGG_L2", Description: "L2 cache double bit aggregate (persistent) ECC errors Note: monotonically increasing"},
+	{Id: 328, Name: "DCGM_FI_DEV_ECC_SBE_AGG_DEV", Description: "Device memory single bit aggregate (persistent) ECC errors Note: monotonically increasing"},
+	{Id: 329, Name: "DCGM_FI_DEV_ECC_DBE_AGG_DEV", Description: "Device memory double bit aggregate (persistent) ECC errors Note: monotonically increasing"},
+	{Id: 330, Name: "DCGM_FI_DEV_ECC_SBE_AGG_REG", Description: "Register File single bit aggregate (persistent) ECC errors Note: monotonically increasing"},
+	{Id: 331, Name: "DCGM_FI_DEV_ECC_DBE_AGG_REG", Description: "Register File double bit aggregate (persistent) ECC errors Note: monotonically increasing"},
+	{Id: 332, Name: "DCGM_FI_DEV_ECC_SBE_AGG_TEX", Description: "Texture memory single bit aggregate (persistent) ECC errors Note: monotonically increasing"},
+	{Id: 333, Name: "DCGM_FI_DEV_ECC_DBE_AGG_TEX", Description: "Texture memory double bit aggregate (persistent) ECC errors Note: monotonically increasing"},
+	{Id: 385, Name: "DCGM_FI_DEV_BANKS_REMAP_ROWS_AVAIL_MAX", Description: "Historical max available spare memory rows per memory bank"},
+	{Id: 386, Name: "DCGM_FI_DEV_BANKS_REMAP_ROWS_AVAIL_HIGH", Description: "Historical high mark of available spare memory rows per memory bank"},
+	{Id: 387, Name: "DCGM_FI_DEV_BANKS_REMAP_ROWS_AVAIL_PARTIAL", Description: "Historical mark of partial available spare memory rows per memory bank"},
+	{Id: 388, Name: "DCGM_FI_DEV_BANKS_REMAP_ROWS_AVAIL_LOW", Description: "Historical low mark of available spare memory rows per memory bank"},
+	{Id: 389, Name: "DCGM_FI_DEV_BANKS_REMAP_ROWS_AVAIL_NONE", Description: "Historical marker of memory banks with no available spare memory rows"},
+	{Id: 390, Name: "DCGM_FI_DEV_RETIRED_SBE", Description: "Number of retired pages because of single bit errors Note: monotonically increasing"},
+	{Id: 391, Name: "DCGM_FI_DEV_RETIRED_DBE", Description: "Number of retired pages because of double bit errors Note: monotonically increasing"},
+	{Id: 392, Name: "DCGM_FI_DEV_RETIRED_PENDING", Description: "Number of pages pending retirement"},
+	{Id: 393, Name: "DCGM_FI_DEV_UNCORRECTABLE_REMAPPED_ROWS", Description: "Number of remapped rows for uncorrectable errors"},
+	{Id: 394, Name: "DCGM_FI_DEV_CORRECTABLE_REMAPPED_ROWS", Description: "Number of remapped rows for correctable errors"},
+	{Id: 395, Name: "DCGM_FI_DEV_ROW_REMAP_FAILURE", Description: "Whether remapping of rows has failed"},
+	{Id: 396, Name: "DCGM_FI_DEV_ROW_REMAP_PENDING", Description: "Whether remapping of rows is pending"},
+	{Id: 400, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L0", Description: "NV Link flow control CRC Error Counter for Lane 0"},
+	{Id: 401, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L1", Description: "NV Link flow control CRC Error Counter for Lane 1"},
+	{Id: 402, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L2", Description: "NV Link flow control CRC Error Counter for Lane 2"},
+	{Id: 403, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L3", Description: "NV Link flow control CRC Error Counter for Lane 3"},
+	{Id: 404, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L4", Description: "NV Link flow control CRC Error Counter for Lane 4"},
+	{Id: 405, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L5", Description: "NV Link flow control CRC Error Counter for Lane 5"},
+	{Id: 409, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_TOTAL", Description: "NV Link flow control CRC Error Counter total for all Lanes"},
+	{Id: 410, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L0", Description: "NV Link data CRC Error Counter for Lane 0"},
+	{Id: 411, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L1", Description: "NV Link data CRC Error Counter for Lane 1"},
+	{Id: 412, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L2", Description: "NV Link data CRC Error Counter for Lane 2"},
+	{Id: 413, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L3", Description: "NV Link data CRC Error Counter for Lane 3"},
+	{Id: 414, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L4", Description: "NV Link data CRC Error Counter for Lane 4"},
+	{Id: 415, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L5", Description: "NV Link data CRC Error Counter for Lane 5"},
+	{Id: 419, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_TOTAL", Description: "NV Link data CRC Error Counter total for all Lanes"},
+	{Id: 420, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L0", Description: "NV Link Replay Error Counter for Lane 0"},
+	{Id: 421, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L1", Description: "NV Link Replay Error Counter for Lane 1"},
+	{Id: 422, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L2", Description: "NV Link Replay Error Counter for Lane 2"},
+	{Id: 423, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L3", Description: "NV Link Replay Error Counter for Lane 3"},
+	{Id: 424, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L4", Description: "NV Link Replay Error Counter for Lane 4"},
+	{Id: 425, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L5", Description: "NV Link Replay Error Counter for Lane 5"},
+	{Id: 429, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_TOTAL", Description: "NV Link Replay Error Counter total for all Lanes"},
+	{Id: 430, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L0", Description: "NV Link Recovery Error Counter for Lane 0"},
+	{Id: 431, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L1", Description: "NV Link Recovery Error Counter for Lane 1"},
+	{Id: 432, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L2", Description: "NV Link Recovery Error Counter for Lane 2"},
+	{Id: 433, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L3", Description: "NV Link Recovery Error Counter for Lane 3"},
+	{Id: 434, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L4", Description: "NV Link Recovery Error Counter for Lane 4"},
+	{Id: 435, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L5", Description: "NV Link Recovery Error Counter for Lane 5"},
+	{Id: 439, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_TOTAL", Description: "NV Link Recovery Error Counter total for all Lanes"},
+	{Id: 440, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L0", Description: "NV Link Bandwidth Counter for Lane 0"},
+	{Id: 441, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L1", Description: "NV Link Bandwidth Counter for Lane 1"},
+	{Id: 442, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L2", Description: "NV Link Bandwidth Counter for Lane 2"},
+	{Id: 443, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L3", Description: "NV Link Bandwidth Counter for Lane 3"},
+	{Id: 444, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L4", Description: "NV Link Bandwidth Counter for Lane 4"},
+	{Id: 445, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L5", Description: "NV Link Bandwidth Counter for Lane 5"},
+	{Id: 449, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_TOTAL", Description: "NV Link Bandwidth Counter total for all Lanes"},
+	{Id: 450, Name: "DCGM_FI_DEV_GPU_NVLINK_ERRORS", Description: "GPU NVLink error information"},
+	{Id: 451, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L6", Description: "NV Link flow control CRC Error Counter for Lane 6"},
+	{Id: 452, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L7", Description: "NV Link flow control CRC Error Counter for Lane 7"},
+	{Id: 453, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L8", Description: "NV Link flow control CRC Error Counter for Lane 8"},
+	{Id: 454, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L9", Description: "NV Link flow control CRC Error Counter for Lane 9"},
+	{Id: 455, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L10", Description: "NV Link flow control CRC Error Counter for Lane 10"},
+	{Id: 456, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L11", Description: "NV Link flow control CRC Error Counter for Lane 11"},
+	{Id: 457, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L6", Description: "NV Link data CRC Error Counter for Lane 6"},
+	{Id: 458, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L7", Description: "NV Link data CRC Error Counter for Lane 7"},
+	{Id: 459, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L8", Description: "NV Link data CRC Error Counter for Lane 8"},
+	{Id: 460, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L9", Description: "NV Link data CRC Error Counter for Lane 9"},
+	{Id: 461, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L10", Description: "NV Link data CRC Error Counter for Lane 10"},
+	{Id: 462, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L11", Description: "NV Link data CRC Error Counter for Lane 11"},
+	{Id: 463, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L6", Description: "NV Link Replay Error Counter for Lane 6"},
+	{Id: 464, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L7", Description: "NV Link Replay Error Counter for Lane 7"},
+	{Id: 465, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L8", Description: "NV Link Replay Error Counter for Lane 8"},
+	{Id: 466, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L9", Description: "NV Link Replay Error Counter for Lane 9"},
+	{Id: 467, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L10", Description: "NV Link Replay Error Counter for Lane 10"},
+	{Id: 468, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L11", Description: "NV Link Replay Error Counter for Lane 11"},
+	{Id: 469, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L6", Description: "NV Link Recovery Error Counter for Lane 6"},
+	{Id: 470, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L7", Description: "NV Link Recovery Error Counter for Lane 7"},
+	{Id: 471, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L8", Description: "NV Link Recovery Error Counter for Lane 8"},
+	{Id: 472, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L9", Description: "NV Link Recovery Error Counter for Lane 9"},
+	{Id: 473, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L10", Description: "NV Link Recovery Error Counter for Lane 10"},
+	{Id: 474, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L11", Description: "NV Link Recovery Error Counter for Lane 11"},
+	{Id: 475, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L6", Description: "NV Link Bandwidth Counter for Lane 6"},
+	{Id: 476, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L7", Description: "NV Link Bandwidth Counter for Lane 7"},
+	{Id: 477, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L8", Description: "NV Link Bandwidth Counter for Lane 8"},
+	{Id: 478, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L9", Description: "NV Link Bandwidth Counter for Lane 9"},
+	{Id: 479, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L10", Description: "NV Link Bandwidth Counter for Lane 10"},
+	{Id: 480, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L11", Description: "NV Link Bandwidth Counter for Lane 11"},
+	{Id: 406, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L12", Description: ""},
+	{Id: 407, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L13", Description: ""},
+	{Id: 408, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L14", Description: ""},
+	{Id: 481, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L15", Description: ""},
+	{Id: 482, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L16", Description: ""},
+	{Id: 483, Name: "DCGM_FI_DEV_NVLINK_CRC_FLIT_ERROR_COUNT_L17", Description: ""},
+	{Id: 416, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L12", Description: ""},
+	{Id: 417, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L13", Description: ""},
+	{Id: 418, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L14", Description: ""},
+	{Id: 484, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L15", Description: ""},
+	{Id: 485, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L16", Description: ""},
+	{Id: 486, Name: "DCGM_FI_DEV_NVLINK_CRC_DATA_ERROR_COUNT_L17", Description: ""},
+	{Id: 426, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L12", Description: ""},
+	{Id: 427, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L13", Description: ""},
+	{Id: 428, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L14", Description: ""},
+	{Id: 487, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L15", Description: ""},
+	{Id: 488, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L16", Description: ""},
+	{Id: 489, Name: "DCGM_FI_DEV_NVLINK_REPLAY_ERROR_COUNT_L17", Description: ""},
+	{Id: 436, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L12", Description: ""},
+	{Id: 437, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L13", Description: ""},
+	{Id: 438, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L14", Description: ""},
+	{Id: 491, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L15", Description: ""},
+	{Id: 492, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L16", Description: ""},
+	{Id: 493, Name: "DCGM_FI_DEV_NVLINK_RECOVERY_ERROR_COUNT_L17", Description: ""},
+	{Id: 446, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L12", Description: ""},
+	{Id: 447, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L13", Description: ""},
+	{Id: 448, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L14", Description: ""},
+	{Id: 494, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L15", Description: ""},
+	{Id: 495, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L16", Description: ""},
+	{Id: 496, Name: "DCGM_FI_DEV_NVLINK_BANDWIDTH_L17", Description: ""},
+	{Id: 500, Name: "DCGM_FI_DEV_VIRTUAL_MODE", Description: "Virtualization Mode corresponding to the GPU. One of DCGM_GPU_VIRTUALIZATION_MODE_* constants."},
+	{Id: 501, Name: "DCGM_FI_DEV_SUPPORTED_TYPE_INFO", Description: "Includes Count and Static info of vGPU types supported on a device"},
+	{Id: 502, Name: "DCGM_FI_DEV_CREATABLE_VGPU_TYPE_IDS", Description: "Includes Count and currently Creatable vGPU types on a device"},
+	{Id: 503, Name: "DCGM_FI_DEV_VGPU_INSTANCE_IDS", Description: "Includes Count and currently Active vGPU Instances on a device"},
+	{Id: 504, Name: "DCGM_FI_DEV_VGPU_UTILIZATIONS", Description: "Utilization values for vGPUs running on the device"},
+	{Id: 505, Name: "DCGM_FI_DEV_VGPU_PER_PROCESS_UTILIZATION", Description: "Utilization values for processes running within vGPU VMs using the device"},
+	{Id: 506, Name: "DCGM_FI_DEV_ENC_STATS", Description: "Current encoder statistics for a given device"},
+	{Id: 507, Name: "DCGM_FI_DEV_FBC_STATS", Description: "Statistics of current active frame buffer capture sessions on a given device"},
+	{Id: 508, Name: "DCGM_FI_DEV_FBC_SESSIONS_INFO", Description: "Information about active frame buffer capture sessions on a target device"},
+	{Id: 509, Name: "DCGM_FI_DEV_SUPPORTED_VGPU_TYPE_IDS", Description: "Includes Count and currently Supported vGPU types on a device"},
+	{Id: 510, Name: "DCGM_FI_DEV_VGPU_TYPE_INFO", Description: "Includes Static info of vGPU types supported on a device"},
+	{Id: 511, Name: "DCGM_FI_DEV_VGPU_TYPE_NAME", Description: "Includes the name of a vGPU type supported on a device"},
+	{Id: 512, Name: "DCGM_FI_DEV_VGPU_TYPE_CLASS", Description: "Includes the class of a vGPU type supported on a device"},
+	{Id: 513, Name: "DCGM_FI_DEV_VGPU_TYPE_LICENSE", Description: "Includes the license info for a vGPU type supported on a device"},
+	{Id: 520, Name: "DCGM_FI_DEV_VGPU_VM_ID", Description: "VM ID of the vGPU instance"},
+	{Id: 521, Name: "DCGM_FI_DEV_VGPU_VM_NAME", Description: "VM name of the vGPU instance"},
+	{Id: 522, Name: "DCGM_FI_DEV_VGPU_TYPE", Description: "vGPU type of the vGPU instance"},
+	{Id: 523, Name: "DCGM_FI_DEV_VGPU_UUID", Description: "UUID of the vGPU instance"},
+	{Id: 524, Name: "DCGM_FI_DEV_VGPU_DRIVER_VERSION", Description: "Driver version of the vGPU instance"},
+	{Id: 525, Name: "DCGM_FI_DEV_VGPU_MEMORY_USAGE", Description: "Memory usage of the vGPU instance"},
+	{Id: 526, Name: "DCGM_FI_DEV_VGPU_LICENSE_STATUS", Description: "License status of the vGPU"},
+	{Id: 527, Name: "DCGM_FI_DEV_VGPU_FRAME_RATE_LIMIT", Description: "Frame rate limit of the vGPU instance"},
+	{Id: 528, Name: "DCGM_FI_DEV_VGPU_ENC_STATS", Description: "Current encoder statistics of the vGPU instance"},
+	{Id: 529, Name: "DCGM_FI_DEV_VGPU_ENC_SESSIONS_INFO", Description: "Information about all active encoder sessions on the vGPU instance"},
+	{Id: 530, Name: "DCGM_FI_DEV_VGPU_FBC_STATS", Description: "Statistics of current active frame buffer capture sessions on the vGPU instance"},
+	{Id: 531, Name: "DCGM_FI_DEV_VGPU_FBC_SESSIONS_INFO", Description: "Information about active frame buffer capture sessions on the vGPU instance"},
+	{Id: 532, Name: "DCGM_FI_DEV_VGPU_INSTANCE_LICENSE_STATE", Description: "License state information of the vGPU instance"},
+	{Id: 533, Name: "DCGM_FI_DEV_VGPU_PCI_ID", Description: "PCI Id of the vGPU instance"},
+	{Id: 534, Name: "DCGM_FI_DEV_VGPU_VM_GPU_INSTANCE_ID", Description: "GPU Instance ID for the given vGPU Instance"},
+	{Id: 701, Name: "DCGM_FI_DEV_NVSWITCH_VOLTAGE_MVOLT", Description: "NvSwitch voltage"},
+	{Id: 702, Name: "DCGM_FI_DEV_NVSWITCH_CURRENT_IDDQ", Description: "NvSwitch Current IDDQ"},
+	{Id: 703, Name: "DCGM_FI_DEV_NVSWITCH_CURRENT_IDDQ_REV", Description: "NvSwitch Current IDDQ Rev"},
+	{Id: 704, Name: "DCGM_FI_DEV_NVSWITCH_CURRENT_IDDQ_DVDD", Description: "NvSwitch Current IDDQ Rev DVDD"},
+	{Id: 705, Name: "DCGM_FI_DEV_NVSWITCH_POWER_VDD", Description: "NvSwitch Power VDD in watts"},
+	{Id: 706, Name: "DCGM_FI_DEV_NVSWITCH_POWER_DVDD", Description: "NvSwitch Power DVDD in watts"},
+	{Id: 707, Name: "DCGM_FI_DEV_NVSWITCH_POWER_HVDD", Description: "NvSwitch Power HVDD in watts"},
+	{Id: 780, Name: "DCGM_FI_DEV_NVSWITCH_LINK_THROUGHPUT_TX", Description: "NVSwitch Tx Throughput Counter for ports 0-17"},
+	{Id: 781, Name: "DCGM_FI_DEV_NVSWITCH_LINK_THROUGHPUT_RX", Description: "NVSwitch Rx Throughput Counter for ports 0-17"},
+	{Id: 782, Name: "DCGM_FI_DEV_NVSWITCH_LINK_FATAL_ERRORS", Description: "NvSwitch fatal_errors for ports 0-17"},
+	{Id: 783, Name: "DCGM_FI_DEV_NVSWITCH_LINK_NON_FATAL_ERRORS", Description: "NvSwitch non_fatal_errors for ports 0-17"},
+	{Id: 784, Name: "DCGM_FI_DEV_NVSWITCH_LINK_REPLAY_ERRORS", Description: "NvSwitch replay_count_errors for ports 0-17"},
+	{Id: 785, Name: "DCGM_FI_DEV_NVSWITCH_LINK_RECOVERY_ERRORS", Description: "NvSwitch recovery_count_errors for ports 0-17"},
+	{Id: 786, Name: "DCGM_FI_DEV_NVSWITCH_LINK_FLIT_ERRORS", Description: "NvSwitch filt_err_count_errors for ports 0-17"},
+	{Id: 787, Name: "DCGM_FI_DEV_NVSWITCH_LINK_CRC_ERRORS", Description: "NvLink lane_crs_err_count_aggregate_errors for ports 0-17"},
+	{Id: 788, Name: "DCGM_FI_DEV_NVSWITCH_LINK_ECC_ERRORS", Description: "NvLink lane ecc_err_count_aggregate_errors for ports 0-17"},
+	{Id: 789, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_LOW_VC0", Description: "Nvlink lane latency low lane0 counter."},
+	{Id: 790, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_LOW_VC1", Description: "Nvlink lane latency low lane1 counter."},
+	{Id: 791, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_LOW_VC2", Description: "Nvlink lane latency low lane2 counter."},
+	{Id: 792, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_LOW_VC3", Description: "Nvlink lane latency low lane3 counter."},
+	{Id: 793, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_MEDIUM_VC0", Description: "Nvlink lane latency medium lane0 counter."},
+	{Id: 794, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_MEDIUM_VC1", Description: "Nvlink lane latency medium lane1 counter."},
+	{Id: 795, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_MEDIUM_VC2", Description: "Nvlink lane latency medium lane2 counter."},
+	{Id: 796, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_MEDIUM_VC3", Description: "Nvlink lane latency medium lane3 counter."},
+	{Id: 797, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_HIGH_VC0", Description: "Nvlink lane latency high lane0 counter."},
+	{Id: 798, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_HIGH_VC1", Description: "Nvlink lane latency high lane1 counter."},
+	{Id: 799, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_HIGH_VC2", Description: "Nvlink lane latency high lane2 counter."},
+	{Id: 800, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_HIGH_VC3", Description: "Nvlink lane latency high lane3 counter."},
+	{Id: 801, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_PANIC_VC0", Description: "Nvlink lane latency panic lane0 counter."},
+	{Id: 802, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_PANIC_VC1", Description: "Nvlink lane latency panic lane1 counter."},
+	{Id: 803, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_PANIC_VC2", Description: "Nvlink lane latency panic lane2 counter."},
+	{Id: 804, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_PANIC_VC3", Description: "Nvlink lane latency panic lane2 counter."},
+	{Id: 805, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_COUNT_VC0", Description: "Nvlink lane latency count lane0 counter."},
+	{Id: 806, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_COUNT_VC1", Description: "Nvlink lane latency count lane1 counter."},
+	{Id: 807, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_COUNT_VC2", Description: "Nvlink lane latency count lane2 counter."},
+	{Id: 808, Name: "DCGM_FI_DEV_NVSWITCH_LINK_LATENCY_COUNT_VC3", Description: "Nvlink lane latency count lane3 counter."},
+	{Id: 809, Name: "DCGM_FI_DEV_NVSWITCH_LINK_CRC_ERRORS_LANE0", Description: "NvLink lane crc_err_count for lane 0 on ports 0-17"},
+	{Id: 810, Name: "DCGM_FI_DEV_NVSWITCH_LINK_CRC_ERRORS_LANE1", Description: "NvLink lane crc_err_count for lane 1 on ports 0-17"},
+	{Id: 811, Name: "DCGM_FI_DEV_NVSWITCH_LINK_CRC_ERRORS_LANE2", Description: "NvLink lane crc_err_count for lane 2 on ports 0-17"},
+	{Id: 812, Name: "DCGM_FI_DEV_NVSWITCH_LINK_CRC_ERRORS_LANE3", Description: "NvLink lane crc_err_count for lane 3 on ports 0-17"},
+	{Id: 813, Name: "DCGM_FI_DEV_NVSWITCH_LINK_ECC_ERRORS_LANE0", Description: "NvLink lane ecc_err_count for lane 0 on ports 0-17"},
+	{Id: 814, Name: "DCGM_FI_DEV_NVSWITCH_LINK_ECC_ERRORS_LANE1", Description: "NvLink lane ecc_err_count for lane 1 on ports 0-17"},
+	{Id: 815, Name: "DCGM_FI_DEV_NVSWITCH_LINK_ECC_ERRORS_LANE2", Description: "NvLink lane ecc_err_count for lane 2 on ports 0-17"},
+	{Id: 816, Name: "DCGM_FI_DEV_NVSWITCH_LINK_ECC_ERRORS_LANE3", Description: "NvLink lane ecc_err_count for lane 3 on ports 0-17"},
+	{Id: 856, Name: "DCGM_FI_DEV_NVSWITCH_FATAL_ERRORS", Description: "NVSwitch fatal error information. Note: value field indicates the specific SXid reported"},
+	{Id: 857, Name: "DCGM_FI_DEV_NVSWITCH_NON_FATAL_ERRORS", Description: "NVSwitch non fatal error information. Note: value field indicates the specific SXid reported"},
+	{Id: 858, Name: "DCGM_FI_DEV_NVSWITCH_TEMPERATURE_CURRENT", Description: "NVSwitch current temperature."},
+	{Id: 859, Name: "DCGM_FI_DEV_NVSWITCH_TEMPERATURE_LIMIT_SLOWDOWN", Description: "NVSwitch limit slowdown temperature."},
+	{Id: 860, Name: "DCGM_FI_DEV_NVSWITCH_TEMPERATURE_LIMIT_SHUTDOWN", Description: "NVSwitch limit shutdown temperature."},
+	{Id: 861, Name: "DCGM_FI_DEV_NVSWITCH_THROUGHPUT_TX", Description: "NVSwitch throughput Tx."},
+	{Id: 862, Name: "DCGM_FI_DEV_NVSWITCH_THROUGHPUT_RX", Description: "NVSwitch throughput Rx."},
+	{Id: 863, Name: "DCGM_FI_DEV_NVSWITCH_PHYS_ID", Description: "NVSwitch Physical ID."},
+	{Id: 864, Name: "DCGM_FI_DEV_NVSWITCH_RESET_REQUIRED", Description: "NVSwitch reset required."},
+	{Id: 865, Name: "DCGM_FI_DEV_NVSWITCH_LINK_ID", Description: "NvSwitch NvLink ID"},
+	{Id: 866, Name: "DCGM_FI_DEV_NVSWITCH_PCIE_DOMAIN", Description: "NvSwitch PCIE domain"},
+	{Id: 867, Name: "DCGM_FI_DEV_NVSWITCH_PCIE_BUS", Description: "NvSwitch PCIE bus"},
+	{Id: 868, Name: "DCGM_FI_DEV_NVSWITCH_PCIE_DEVICE", Description: "NvSwitch PCIE device"},
+	{Id: 869, Name: "DCGM_FI_DEV_NVSWITCH_PCIE_FUNCTION", Description: "NvSwitch PCIE function"},
+	{Id: 870, Name: "DCGM_FI_DEV_NVSWITCH_LINK_STATUS", Description: "NvLink status. UNKNOWN:-1 OFF:0 SAFE:1 ACTIVE:2 ERROR:3"},
+	{Id: 871, Name: "DCGM_FI_DEV_NVSWITCH_LINK_TYPE", Description: "NvLink device type (GPU/Switch)."},
+	{Id: 872, Name: "DCGM_FI_DEV_NVSWITCH_LINK_REMOTE_PCIE_DOMAIN", Description: "NvLink device pcie domain."},
+	{Id: 873, Name: "DCGM_FI_DEV_NVSWITCH_LINK_REMOTE_PCIE_BUS", Description: "NvLink device pcie bus."},
+	{Id: 874, Name: "DCGM_FI_DEV_NVSWITCH_LINK_REMOTE_PCIE_DEVICE", Description: "NvLink device pcie device."},
+	{Id: 875, Name: "DCGM_FI_DEV_NVSWITCH_LINK_REMOTE_PCIE_FUNCTION", Description: "NvLink device pcie function."},
+	{Id: 876, Name: "DCGM_FI_DEV_NVSWITCH_LINK_DEVICE_LINK_ID", Description: "NvLink device link ID"},
+	{Id: 877, Name: "DCGM_FI_DEV_NVSWITCH_LINK_DEVICE_LINK_SID", Description: "NvLink device SID."},
+	{Id: 878, Name: "DCGM_FI_DEV_NVSWITCH_LINK_DEVICE_UUID", Description: "NvLink device link uid."},
+	{Id: 1001, Name: "DCGM_FI_PROF_GR_ENGINE_ACTIVE", Description: "Ratio of time the graphics engine is active. The graphics engine is active if a graphics/compute context is bound and the graphics pipe or compute pipe is busy."},
+	{Id: 1002, Name: "DCGM_FI_PROF_SM_ACTIVE", Description: "The ratio of cycles an SM has at least 1 warp assigned (computed from the number of cycles and elapsed cycles)"},
+	{Id: 1003, Name: "DCGM_FI_PROF_SM_OCCUPANCY", Description: "The ratio of number of warps resident on an SM. (number of resident as a ratio of the theoretical maximum number of warps per elapsed cycle)"},
+	{Id: 1004, Name: "DCGM_FI_PROF_PIPE_TENSOR_ACTIVE", Description: "The ratio of cycles the any tensor pipe is active (off the peak sustained elapsed cycles)"},
+	{Id: 1005, Name: "DCGM_FI_PROF_DRAM_ACTIVE", Description: "The ratio of cycles the device memory interface is active sending or receiving data."},
+	{Id: 1006, Name: "DCGM_FI_PROF_PIPE_FP64_ACTIVE", Description: "Ratio of cycles the fp64 pipe is active."},
+	{Id: 1007, Name: "DCGM_FI_PROF_PIPE_FP32_ACTIVE", Description: "Ratio of cycles the fp32 pipe is active."},
+	{Id: 1008, Name: "DCGM_FI_PROF_PIPE_FP16_ACTIVE", Description: "Ratio of cycles the fp16 pipe is active. This does not include HMMA."},
+	{Id: 1009, Name: "DCGM_FI_PROF_PCIE_TX_BYTES", Description: "The number of bytes of active PCIe tx (transmit) data including both header and payload. Note that this is from the perspective of the GPU, so copying data from device to host (DtoH) would be reflected in this metric."},
+	{Id: 1010, Name: "DCGM_FI_PROF_PCIE_RX_BYTES", Description: "The number of bytes of active PCIe rx (read) data including both header and payload. Note that this is from the perspective of the GPU, so copying data from host to device (HtoD) would be reflected in this metric."},
+	{Id: 1011, Name: "DCGM_FI_PROF_NVLINK_TX_BYTES", Description: "The total number of bytes of active NvLink tx (transmit) data including both header and payload. Per-link fields are available below"},
+	{Id: 1012, Name: "DCGM_FI_PROF_NVLINK_RX_BYTES", Description: "The total number of bytes of active NvLink rx (read) data including both header and payload. Per-link fields are available below"},
+	{Id: 1013, Name: "DCGM_FI_PROF_PIPE_TENSOR_IMMA_ACTIVE", Description: "The ratio of cycles the tensor (IMMA) pipe is active (off the peak sustained elapsed cycles)"},
+	{Id: 1014, Name: "DCGM_FI_PROF_PIPE_TENSOR_HMMA_ACTIVE", Description: "The ratio of cycles the tensor (HMMA) pipe is active (off the peak sustained elapsed cycles)"},
+	{Id: 1015, Name: "DCGM_FI_PROF_PIPE_TENSOR_DFMA_ACTIVE", Description: "The ratio of cycles the tensor (DFMA) pipe is active (off the peak sustained elapsed cycles)"},
+	{Id: 1016, Name: "DCGM_FI_PROF_PIPE_INT_ACTIVE", Description: "Ratio of cycles the integer pipe is active."},
+	{Id: 1017, Name: "DCGM_FI_PROF_NVDEC0_ACTIVE", Description: "Ratio of cycles each of the NVDEC engines are active."},
+	{Id: 1018, Name: "DCGM_FI_PROF_NVDEC1_ACTIVE", Description: ""},
+	{Id: 1019, Name: "DCGM_FI_PROF_NVDEC2_ACTIVE", Description: ""},
+	{Id: 1020, Name: "DCGM_FI_PROF_NVDEC3_ACTIVE", Description: ""},
+	{Id: 1021, Name: "DCGM_FI_PROF_NVDEC4_ACTIVE", Description: ""},
+	{Id: 1022, Name: "DCGM_FI_PROF_NVDEC5_ACTIVE", Description: ""},
+	{Id: 1023, Name: "DCGM_FI_PROF_NVDEC6_ACTIVE", Description: ""},
+	{Id: 1024, Name: "DCGM_FI_PROF_NVDEC7_ACTIVE", Description: ""},
+	{Id: 1025, Name: "DCGM_FI_PROF_NVJPG0_ACTIVE", Description: "Ratio of cycles each of the NVJPG engines are active."},
+	{Id: 1026, Name: "DCGM_FI_PROF_NVJPG1_ACTIVE", Description: ""},
+	{Id: 1027, Name: "DCGM_FI_PROF_NVJPG2_ACTIVE", Description: ""},
+	{Id: 1028, Name: "DCGM_FI_PROF_NVJPG3_ACTIVE", Description: ""},
+	{Id: 1029, Name: "DCGM_FI_PROF_NVJPG4_ACTIVE", Description: ""},
+	{Id: 1030, Name: "DCGM_FI_PROF_NVJPG5_ACTIVE", Description: ""},
+	{Id: 1031, Name: "DCGM_FI_PROF_NVJPG6_ACTIVE", Description: ""},
+	{Id: 1032, Name: "DCGM_FI_PROF_NVJPG7_ACTIVE", Description: ""},
+	{Id: 1033, Name: "DCGM_FI_PROF_NVOFA0_ACTIVE", Description: "Ratio of cycles each of the NVOFA engines are active."},
+	{Id: 1040, Name: "DCGM_FI_PROF_NVLINK_L0_TX_BYTES", Description: "The per-link number of bytes of active NvLink TX (transmit) or RX (transmit) data including both header and payload. For example: DCGM_FI_PROF_NVLINK_L0_TX_BYTES -> L0 TX To get the bandwidth for a link, add the RX and TX value together like total = DCGM_FI_PROF_NVLINK_L0_TX_BYTES + DCGM_FI_PROF_NVLINK_L0_RX_BYTES"},
+	{Id: 1041, Name: "DCGM_FI_PROF_NVLINK_L0_RX_BYTES", Description: ""},
+	{Id: 1042, Name: "DCGM_FI_PROF_NVLINK_L1_TX_BYTES", Description: ""},
+	{Id: 1043, Name: "DCGM_FI_PROF_NVLINK_L1_RX_BYTES", Description: ""},
+	{Id: 1044, Name: "DCGM_FI_PROF_NVLINK_L2_TX_BYTES", Description: ""},
+	{Id: 1045, Name: "DCGM_FI_PROF_NVLINK_L2_RX_BYTES", Description: ""},
+	{Id: 1046, Name: "DCGM_FI_PROF_NVLINK_L3_TX_BYTES", Description: ""},
+	{Id: 1047, Name: "DCGM_FI_PROF_NVLINK_L3_RX_BYTES", Description: ""},
+	{Id: 1048, Name: "DCGM_FI_PROF_NVLINK_L4_TX_BYTES", Description: ""},
+	{Id: 1049, Name: "DCGM_FI_PROF_NVLINK_L4_RX_BYTES", Description: ""},
+	{Id: 1050, Name: "DCGM_FI_PROF_NVLINK_L5_TX_BYTES", Description: ""},
+	{Id: 1051, Name: "DCGM_FI_PROF_NVLINK_L5_RX_BYTES", Description: ""},
+	{Id: 1052, Name: "DCGM_FI_PROF_NVLINK_L6_TX_BYTES", Description: ""},
+	{Id: 1053, Name: "DCGM_FI_PROF_NVLINK_L6_RX_BYTES", Description: ""},
+	{Id: 1054, Name: "DCGM_FI_PROF_NVLINK_L7_TX_BYTES", Description: ""},
+	{Id: 1055, Name: "DCGM_FI_PROF_NVLINK_L7_RX_BYTES", Description: ""},
+	{Id: 1056, Name: "DCGM_FI_PROF_NVLINK_L8_TX_BYTES", Description: ""},
+	{Id: 1057, Name: "DCGM_FI_PROF_NVLINK_L8_RX_BYTES", Description: ""},
+	{Id: 1058, Name: "DCGM_FI_PROF_NVLINK_L9_TX_BYTES", Description: ""},
+	{Id: 1059, Name: "DCGM_FI_PROF_NVLINK_L9_RX_BYTES", Description: ""},
+	{Id: 1060, Name: "DCGM_FI_PROF_NVLINK_L10_TX_BYTES", Description: ""},
+	{Id: 1061, Name: "DCGM_FI_PROF_NVLINK_L10_RX_BYTES", Description: ""},
+	{Id: 1062, Name: "DCGM_FI_PROF_NVLINK_L11_TX_BYTES", Description: ""},
+	{Id: 1063, Name: "DCGM_FI_PROF_NVLINK_L11_RX_BYTES", Description: ""},
+	{Id: 1064, Name: "DCGM_FI_PROF_NVLINK_L12_TX_BYTES", Description: ""},
+	{Id: 1065, Name: "DCGM_FI_PROF_NVLINK_L12_RX_BYTES", Description: ""},
+	{Id: 1066, Name: "DCGM_FI_PROF_NVLINK_L13_TX_BYTES", Description: ""},
+	{Id: 1067, Name: "DCGM_FI_PROF_NVLINK_L13_RX_BYTES", Description: ""},
+	{Id: 1068, Name: "DCGM_FI_PROF_NVLINK_L14_TX_BYTES", Description: ""},
+	{Id: 1069, Name: "DCGM_FI_PROF_NVLINK_L14_RX_BYTES", Description: ""},
+	{Id: 1070, Name: "DCGM_FI_PROF_NVLINK_L15_TX_BYTES", Description: ""},
+	{Id: 1071, Name: "DCGM_FI_PROF_NVLINK_L15_RX_BYTES", Description: ""},
+	{Id: 1072, Name: "DCGM_FI_PROF_NVLINK_L16_TX_BYTES", Description: ""},
+	{Id: 1073, Name: "DCGM_FI_PROF_NVLINK_L16_RX_BYTES", Description: ""},
+	{Id: 1074, Name: "DCGM_FI_PROF_NVLINK_L17_TX_BYTES", Description: ""},
+	{Id: 1075, Name: "DCGM_FI_PROF_NVLINK_L17_RX_BYTES", Description: ""},
+	{Id: 1100, Name: "DCGM_FI_DEV_CPU_UTIL_TOTAL", Description: "CPU Utilization, total"},
+	{Id: 1101, Name: "DCGM_FI_DEV_CPU_UTIL_USER", Description: "CPU Utilization, user"},
+	{Id: 1102, Name: "DCGM_FI_DEV_CPU_UTIL_NICE", Description: "CPU Utilization, nice"},
+	{Id: 1103, Name: "DCGM_FI_DEV_CPU_UTIL_SYS", Description: "CPU Utilization, system time"},
+	{Id: 1104, Name: "DCGM_FI_DEV_CPU_UTIL_IRQ", Description: "CPU Utilization, interrupt servicing"},
+	{Id: 1110, Name: "DCGM_FI_DEV_CPU_TEMP_CURRENT", Description: "CPU temperature"},
+	{Id: 1111, Name: "DCGM_FI_DEV_CPU_TEMP_WARNING", Description: "CPU Warning Temperature"},
+	{Id: 1112, Name: "DCGM_FI_DEV_CPU_TEMP_CRITICAL", Description: "CPU Critical Temperature"},
+	{Id: 1120, Name: "DCGM_FI_DEV_CPU_CLOCK_CURRENT", Description: "CPU instantaneous clock speed"},
+	{Id: 1130, Name: "DCGM_FI_DEV_CPU_POWER_UTIL_CURRENT", Description: "CPU power utilization"},
+	{Id: 1131, Name: "DCGM_FI_DEV_CPU_POWER_LIMIT", Description: "CPU power limit"},
+	{Id: 1140, Name: "DCGM_FI_DEV_CPU_VENDOR", Description: "CPU vendor name"},
+	{Id: 1141, Name: "DCGM_FI_DEV_CPU_MODEL", Description: "CPU model name"},
+}
@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestFieldRegistry(t *testing.T) {
+	meta, found := ixdcgm.FieldByID(ixdcgm.DCGM_FI_DEV_GPU_TEMP)
+	if !found || meta.Name != "DCGM_FI_DEV_GPU_TEMP" || meta.Description == "" {
+		t.Errorf("FieldByID(DCGM_FI_DEV_GPU_TEMP) = %+v, %v", meta, found)
+	}
+	if _, found = ixdcgm.FieldByID(9999); found {
+		t.Error("FieldByID of an unknown field succeeded")
+	}
+
+	for _, name := range []string{"DCGM_FI_DEV_POWER_USAGE", "dev_power_usage", "power_usage", " Power_Usage "} {
+		meta, found = ixdcgm.FieldByName(name)
+		if !found || meta.Id != ixdcgm.DCGM_FI_DEV_POWER_USAGE {
+			t.Errorf("FieldByName(%q) = %+v, %v, want DCGM_FI_DEV_POWER_USAGE", name, meta, found)
+		}
+	}
+	// the fields without DEV in their name win over the shorter names of the device fields
+	if meta, found = ixdcgm.FieldByName("driver_version"); !found || meta.Id != ixdcgm.DCGM_FI_DRIVER_VERSION {
+		t.Errorf("FieldByName(driver_version) = %+v, %v, want DCGM_FI_DRIVER_VERSION", meta, found)
+	}
+
+	// every field of the name to id map is registered with the same id
+	for name, id := range ixdcgm.DCGM_FI {
+		if meta, found = ixdcgm.FieldByName(name); found && meta.Id != id {
+			t.Errorf("FieldByName(%s) = %d, want %d", name, meta.Id, id)
+		}
+	}
+}
+
+func TestParseFieldList(t *testing.T) {
+	fields, err := ixdcgm.ParseFieldList("DCGM_FI_DEV_GPU_TEMP, power_usage,203")
+	want := []ixdcgm.Short{ixdcgm.DCGM_FI_DEV_GPU_TEMP, ixdcgm.DCGM_FI_DEV_POWER_USAGE, ixdcgm.DCGM_FI_DEV_GPU_UTIL}
+	if err != nil || !reflect.DeepEqual(fields, want) {
+		t.Errorf("ParseFieldList = %v, %v, want %v", fields, err, want)
+	}
+
+	for _, list := range []string{"gpu_temp,no_such_field", "9999", ""} {
+		if _, err = ixdcgm.ParseFieldList(list); !errors.Is(err, ixdcgm.ErrUnknownField) {
+			t.Errorf("ParseFieldList(%q) returned %v, want ErrUnknownField", list, err)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"bytes"
+	"math"
+)
+
+// Int64 returns the value of an int64 or timestamp field, without checking for blank values
+func (fv FieldValue_v1) Int64() int64 {
+	return int64(fv.num)
+}
+
+// Float64 returns the value of a double field, without checking for blank values
+func (fv FieldValue_v1) Float64() float64 {
+	return math.Float64frombits(fv.num)
+}
+
+// Str returns the value of a string field, it is copied on each call
+func (fv FieldValue_v1) Str() string {
+	return string(fv.data)
+}
+
+// Blob returns the value of a binary field, it must not be modified. dcgmFieldValue_v1 does not
+// tell the length of binary values, so it is the whole blob written by the library, trailing zeros included.
+func (fv FieldValue_v1) Blob() []byte {
+	return fv.data
+}
+
+func (fv *FieldValue_v1) setInt64(v int64) {
+	fv.num = uint64(v)
+}
+
+func (fv *FieldValue_v1) setFloat64(v float64) {
+	fv.num = math.Float64bits(v)
+}
+
+// setBytes sets the value of a string or binary field, which is not copied: a string up to its NUL, a whole blob
+func (fv *FieldValue_v1) setBytes(raw []byte) {
+	if fv.FieldType == DCGM_FT_STRING {
+		if end := bytes.IndexByte(raw, 0); end >= 0 {
+			raw = raw[:end]
+		}
+	}
+	fv.data = raw
+}
+
+// holdsBytes tells whether the value references the bytes of a string or binary value
+func (fv FieldValue_v1) holdsBytes() bool {
+	return fv.FieldType == DCGM_FT_STRING || fv.FieldType == DCGM_FT_BINARY
+}
+
+// ValueKind is the type of a field value, given by its DCGM_FT_* field type
+type ValueKind int
+
+const (
+	KindUnknown ValueKind = iota
+	KindInt64
+	KindFloat64
+	KindString
+	KindTimestamp // int64 number of microseconds since 1970
+	KindBlob
+)
+
+func (k ValueKind) String() string {
+	switch k {
+	case KindInt64:
+		return "int64"
+	case KindFloat64:
+		return "float64"
+	case KindString:
+		return "string"
+	case KindTimestamp:
+		return "timestamp"
+	case KindBlob:
+		return "blob"
+	}
+	return "unknown"
+}
+
+func valueKindOf(fieldType uint) ValueKind {
+	switch fieldType {
+	case DCGM_FT_INT64:
+		return KindInt64
+	case DCGM_FT_DOUBLE:
+		return KindFloat64
+	case DCGM_FT_STRING:
+		return KindString
+	case DCGM_FT_TIMESTAMP:
+		return KindTimestamp
+	case DCGM_FT_BINARY:
+		return KindBlob
+	}
+	return KindUnknown
+}
+
+// BlankReason tells why a field holds one of the DCGM_FT_*_BLANK sentinels instead of a value
+type BlankReason int
+
+const (
+	NotBlank             BlankReason = iota
+	BlankNoData                      // DCGM_FT_*_BLANK: no value was read yet
+	BlankNotFound                    // DCGM_FT_*_NOT_FOUND
+	BlankNotSupported                // DCGM_FT_*_NOT_SUPPORTED
+	BlankNotPermissioned             // DCGM_FT_*_NOT_PERMISSIONED
+)
+
+func (r BlankReason) String() string {
+	switch r {
+	case NotBlank:
+		return "not blank"
+	case BlankNoData:
+		return "no data"
+	case BlankNotFound:
+		return "not found"
+	case BlankNotSupported:
+		return "not supported"
+	case BlankNotPermissioned:
+		return "not permissioned"
+	}
+	return "unknown"
+}
+
+// int64Blank recognises the int64 sentinels, and the int32 ones which the values of 32-bit fields
+// keep once reported as int64. Whether the field is 32-bit is only looked up for these values.
+func int64Blank(field Short, v int64) BlankReason {
+	switch v {
+	case DCGM_FT_INT64_NOT_FOUND:
+		return BlankNotFound
+	case DCGM_FT_INT64_NOT_SUPPORTED:
+		return BlankNotSupported
+	case DCGM_FT_INT64_NOT_PERMISSIONED:
+		return BlankNotPermissioned
+	}
+	if v >= DCGM_FT_INT64_BLANK {
+		return BlankNoData
+	}
+	if v >= DCGM_FT_INT32_BLANK && v <= DCGM_FT_INT32_NOT_PERMISSIONED && fieldValueSize(field) == 4 {
+		return int32Blank(v)
+	}
+	return NotBlank
+}
+
+func int32Blank(v int64) BlankReason {
+	switch v {
+	case DCGM_FT_INT32_NOT_FOUND:
+		return BlankNotFound
+	case DCGM_FT_INT32_NOT_SUPPORTED:
+		return BlankNotSupported
+	case DCGM_FT_INT32_NOT_PERMISSIONED:
+		return BlankNotPermissioned
+	}
+	return BlankNoData
+}
+
+func float64Blank(v float64) BlankReason {
+	switch v {
+	case DCGM_FT_FP64_NOT_FOUND:
+		return BlankNotFound
+	case DCGM_FT_FP64_NOT_SUPPORTED:
+		return BlankNotSupported
+	case DCGM_FT_FP64_NOT_PERMISSIONED:
+		return BlankNotPermissioned
+	}
+	if v >= DCGM_FT_FP64_BLANK {
+		return BlankNoData
+	}
+	return NotBlank
+}
+
+func stringBlank(v []byte) BlankReason {
+	switch string(v) {
+	case DCGM_FT_STR_BLANK:
+		return BlankNoData
+	case DCGM_FT_STR_NOT_FOUND:
+		return BlankNotFound
+	case DCGM_FT_STR_NOT_SUPPORTED:
+		return BlankNotSupported
+	case DCGM_FT_STR_NOT_PERMISSIONED:
+		return BlankNotPermissioned
+	}
+	return NotBlank
+}
+
+// Value is the decoded value of a FieldValue_v1. Its accessors return false
+// if the value has another kind, is blank or could not be read.
+type Value struct {
+	kind   ValueKind
+	status ReturnCode
+	blank  BlankReason
+	num    uint64
+	data   []byte
+}
+
+// TypedValue decodes the value according to its field type and recognises the blank sentinels
+func (fv FieldValue_v1) TypedValue() Value {
+	v := Value{kind: valueKindOf(fv.FieldType), status: fv.Status}
+	if v.status != DCGM_ST_OK {
+		return v
+	}
+
+	v.num, v.data = fv.num, fv.data
+	switch v.kind {
+	case KindInt64, KindTimestamp:
+		v.blank = int64Blank(Short(fv.FieldId), fv.Int64())
+	case KindFloat64:
+		v.blank = float64Blank(fv.Float64())
+	case KindString:
+		v.blank = stringBlank(fv.data)
+	}
+	return v
+}
+
+// Kind returns the type of the value
+func (v Value) Kind() ValueKind { return v.kind }
+
+// Blank returns why the field holds a blank sentinel, NotBlank if it holds a value
+func (v Value) Blank() BlankReason { return v.blank }
+
+// Err returns the error the field was read with, nil if it was read
+func (v Value) Err() error {
+	if v.status == DCGM_ST_OK {
+		return nil
+	}
+	return newDcgmError(v.status)
+}
+
+// Valid tells whether the field was read and holds a value
+func (v Value) Valid() bool {
+	return v.status == DCGM_ST_OK && v.blank == NotBlank && v.kind != KindUnknown
+}
+
+// Int64 returns the value of an int64 or timestamp field
+func (v Value) Int64() (int64, bool) {
+	if !v.Valid() || (v.kind != KindInt64 && v.kind != KindTimestamp) {
+		return 0, false
+	}
+	return int64(v.num), true
+}
+
+// Float64 returns the value of a double field
+func (v Value) Float64() (float64, bool) {
+	if !v.Valid() || v.kind != KindFloat64 {
+		return 0, false
+	}
+	return math.Float64frombits(v.num), true
+}
+
+// Str returns the value of a string field, it is copied on each call
+func (v Value) Str() (string, bool) {
+	if !v.Valid() || v.kind != KindString {
+		return "", false
+	}
+	return string(v.data), true
+}
+
+// Blob returns the value of a binary field, it must not be modified
+func (v Value) Blob() ([]byte, bool) {
+	if !v.Valid() || v.kind != KindBlob {
+		return nil, false
+	}
+	return v.data, true
+}
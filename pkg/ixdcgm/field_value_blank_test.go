@@ -0,0 +1,40 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import "testing"
+
+func TestInt32Blank(t *testing.T) {
+	for v, want := range map[int64]BlankReason{
+		DCGM_FT_INT32_BLANK:            BlankNoData,
+		DCGM_FT_INT32_NOT_FOUND:        BlankNotFound,
+		DCGM_FT_INT32_NOT_SUPPORTED:    BlankNotSupported,
+		DCGM_FT_INT32_NOT_PERMISSIONED: BlankNotPermissioned,
+	} {
+		if got := int32Blank(v); got != want {
+			t.Errorf("int32Blank(%d) = %q, want %q", v, got, want)
+		}
+		// without the library, the size of the field is read from the registry
+		if got := int64Blank(DCGM_FI_DEV_GPU_TEMP, v); got != want {
+			t.Errorf("int64Blank(%d) of a 32-bit field = %q, want %q", v, got, want)
+		}
+		if got := int64Blank(DCGM_FI_DEV_POWER_USAGE, v); got != NotBlank {
+			t.Errorf("int64Blank(%d) of a field of unknown size = %q, want %q", v, got, NotBlank)
+		}
+	}
+}
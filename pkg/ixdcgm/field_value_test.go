@@ -0,0 +1,196 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+// latestValue watches the field of GPU 0 and returns its latest value
+func latestValue(t *testing.T, fake *ixdcgm.FakeBackend, field ixdcgm.Short) ixdcgm.Value {
+	t.Helper()
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	fields := []ixdcgm.Short{field}
+	fieldGroup, err := client.FieldGroupCreate("value", fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.WatchFields([]uint{0}, fieldGroup, "value"); err != nil {
+		t.Fatal(err)
+	}
+	values, err := client.GetLatestValuesForFields(0, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return values[0].TypedValue()
+}
+
+func TestTypedValueBlanks(t *testing.T) {
+	int64Blanks := map[int64]ixdcgm.BlankReason{
+		42:                                    ixdcgm.NotBlank,
+		ixdcgm.DCGM_FT_INT64_BLANK:            ixdcgm.BlankNoData,
+		ixdcgm.DCGM_FT_INT64_NOT_FOUND:        ixdcgm.BlankNotFound,
+		ixdcgm.DCGM_FT_INT64_NOT_SUPPORTED:    ixdcgm.BlankNotSupported,
+		ixdcgm.DCGM_FT_INT64_NOT_PERMISSIONED: ixdcgm.BlankNotPermissioned,
+		// the registry knows the field is 32-bit without the library
+		ixdcgm.DCGM_FT_INT32_BLANK:         ixdcgm.BlankNoData,
+		ixdcgm.DCGM_FT_INT32_NOT_SUPPORTED: ixdcgm.BlankNotSupported,
+	}
+	for raw, want := range int64Blanks {
+		fake := ixdcgm.NewFakeBackend(1)
+		fake.SetFieldInt64(0, ixdcgm.DCGM_FI_DEV_GPU_TEMP, raw)
+		v := latestValue(t, fake, ixdcgm.DCGM_FI_DEV_GPU_TEMP)
+		if v.Kind() != ixdcgm.KindInt64 || v.Blank() != want {
+			t.Errorf("int64 %d: kind %s, blank %q, want int64, %q", raw, v.Kind(), v.Blank(), want)
+		}
+		if got, ok := v.Int64(); ok != (want == ixdcgm.NotBlank) || (ok && got != raw) {
+			t.Errorf("Int64 of %d = %d, %t", raw, got, ok)
+		}
+	}
+
+	float64Blanks := map[float64]ixdcgm.BlankReason{
+		1.5:                                  ixdcgm.NotBlank,
+		ixdcgm.DCGM_FT_FP64_BLANK:            ixdcgm.BlankNoData,
+		ixdcgm.DCGM_FT_FP64_NOT_SUPPORTED:    ixdcgm.BlankNotSupported,
+		ixdcgm.DCGM_FT_FP64_NOT_PERMISSIONED: ixdcgm.BlankNotPermissioned,
+	}
+	for raw, want := range float64Blanks {
+		fake := ixdcgm.NewFakeBackend(1)
+		fake.SetFieldFloat64(0, ixdcgm.DCGM_FI_DEV_POWER_USAGE, raw)
+		v := latestValue(t, fake, ixdcgm.DCGM_FI_DEV_POWER_USAGE)
+		if v.Blank() != want {
+			t.Errorf("float64 %g: blank %q, want %q", raw, v.Blank(), want)
+		}
+		if _, ok := v.Int64(); ok {
+			t.Errorf("Int64 of the float64 %g succeeded", raw)
+		}
+	}
+
+	stringBlanks := map[string]ixdcgm.BlankReason{
+		"Iluvatar BI-V150":                  ixdcgm.NotBlank,
+		ixdcgm.DCGM_FT_STR_BLANK:            ixdcgm.BlankNoData,
+		ixdcgm.DCGM_FT_STR_NOT_FOUND:        ixdcgm.BlankNotFound,
+		ixdcgm.DCGM_FT_STR_NOT_PERMISSIONED: ixdcgm.BlankNotPermissioned,
+	}
+	for raw, want := range stringBlanks {
+		fake := ixdcgm.NewFakeBackend(1)
+		fake.SetFieldString(0, ixdcgm.DCGM_FI_DEV_NAME, raw)
+		v := latestValue(t, fake, ixdcgm.DCGM_FI_DEV_NAME)
+		if v.Blank() != want {
+			t.Errorf("string %q: blank %q, want %q", raw, v.Blank(), want)
+		}
+		if got, ok := v.Str(); ok != (want == ixdcgm.NotBlank) || (ok && got != raw) {
+			t.Errorf("String of %q = %q, %t", raw, got, ok)
+		}
+	}
+}
+
+func TestTypedValueNotRead(t *testing.T) {
+	client := ixdcgm.NewFakeClient(ixdcgm.NewFakeBackend(1))
+	defer client.Close()
+
+	// the fields which are not watched are returned with an error status
+	values, err := client.GetLatestValuesForFields(0, []ixdcgm.Short{ixdcgm.DCGM_FI_DEV_GPU_TEMP})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := values[0].TypedValue()
+	if v.Valid() || !errors.Is(v.Err(), ixdcgm.ErrNotWatched) {
+		t.Errorf("value of an unwatched field: valid %t, error %v, want ErrNotWatched", v.Valid(), v.Err())
+	}
+	if _, ok := v.Int64(); ok {
+		t.Error("Int64 of an unwatched field succeeded")
+	}
+	if s := ixdcgm.GetFieldValueStr(values[0], "unknown"); s != "N/A" {
+		t.Errorf("GetFieldValueStr with an unknown type = %q, want N/A", s)
+	}
+}
+
+func TestFieldValueIsNotStringer(t *testing.T) {
+	// a Stringer would print every numeric value as an empty string
+	for _, v := range []any{ixdcgm.FieldValue_v1{}, ixdcgm.Value{}} {
+		if _, ok := v.(fmt.Stringer); ok {
+			t.Errorf("%T implements fmt.Stringer", v)
+		}
+	}
+}
+
+// benchmarkClient returns a client reading 60 watched fields from each of 8 GPUs, as an exporter does
+func benchmarkClient(b *testing.B) (*ixdcgm.Client, []ixdcgm.Short) {
+	const gpus = 8
+	var fields []ixdcgm.Short
+	for _, meta := range ixdcgm.Fields()[:60] {
+		fields = append(fields, meta.Id)
+	}
+
+	fake := ixdcgm.NewFakeBackend(gpus)
+	for gpu := uint(0); gpu < gpus; gpu++ {
+		for i, field := range fields {
+			if i%2 == 0 {
+				fake.SetFieldInt64(gpu, field, int64(i))
+			} else {
+				fake.SetFieldString(gpu, field, "value")
+			}
+		}
+	}
+	client := ixdcgm.NewFakeClient(fake)
+	b.Cleanup(func() { client.Close() })
+
+	fieldGroup, err := client.FieldGroupCreate("bench", fields)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if _, err = client.WatchFields([]uint{0, 1, 2, 3, 4, 5, 6, 7}, fieldGroup, "bench"); err != nil {
+		b.Fatal(err)
+	}
+	return client, fields
+}
+
+func BenchmarkGetLatestValuesForFields(b *testing.B) {
+	client, fields := benchmarkClient(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for gpu := uint(0); gpu < 8; gpu++ {
+			if _, err := client.GetLatestValuesForFields(gpu, fields); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkTypedValue(b *testing.B) {
+	client, fields := benchmarkClient(b)
+	values, err := client.GetLatestValuesForFields(0, fields)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, fv := range values {
+			fv.TypedValue()
+		}
+	}
+}
@@ -23,8 +23,10 @@ package ixdcgm
 */
 import "C"
 import (
+	"context"
 	"fmt"
-	"os"
+	"math/rand"
+	"sync"
 	"unsafe"
 )
 
@@ -34,126 +36,313 @@ const (
 	defaultMaxKeepSamples = 1       // Keep one sample by default since we only ask for latest
 )
 
-type FieldGrpHandle struct{ handle C.dcgmFieldGrp_t }
+// FieldGrpHandle identifies a field group, the handle of a field group created through a client keeps
+// identifying it across reconnects, while the field group is created again with a new id
+type FieldGrpHandle struct {
+	handle     C.dcgmFieldGrp_t
+	fieldGroup *trackedFieldGroup // the field group created through a client, nil for the other field groups
+}
+
+func FieldGroupCreate(groupName string, fields []Short) (FieldGrpHandle, error) {
+	return defaultClient.FieldGroupCreate(groupName, fields)
+}
+
+func (c *Client) FieldGroupCreate(groupName string, fields []Short) (fgId FieldGrpHandle, err error) {
+	return c.fieldGroupCreate(groupName, fields, false)
+}
+
+func (c *Client) fieldGroupCreate(groupName string, fields []Short, temporary bool) (fgId FieldGrpHandle, err error) {
+	err = c.withBackend(func(b backend) error {
+		fieldsGroup, err := b.fieldGroupCreate(groupName, fields)
+		if err != nil {
+			return err
+		}
+		fgId = c.res.addFieldGroup(fieldsGroup, groupName, fields, temporary)
+		return nil
+	})
+	return
+}
 
-func FieldGroupCreate(groupName string, fields []Short) (fgId FieldGrpHandle, err error) {
+func (b cgoBackend) fieldGroupCreate(groupName string, fields []Short) (C.dcgmFieldGrp_t, error) {
 	var fieldsGroup C.dcgmFieldGrp_t
 	cfields := *(*[]C.ushort)(unsafe.Pointer(&fields))
 
 	gn := string2Char(groupName)
 	defer freeCString(gn)
 
-	res := C.dcgmFieldGroupCreate(handle.handle, C.int(len(fields)), &cfields[0], gn, &fieldsGroup)
-	if err = errorString(res); err != nil {
-		return fgId, fmt.Errorf("error creating DCGM fields group: %s", err)
+	res := C.dcgmFieldGroupCreate(b.handle, C.int(len(fields)), &cfields[0], gn, &fieldsGroup)
+	if err := errorString(res); err != nil {
+		return fieldsGroup, fmt.Errorf("error creating DCGM fields group: %w", err)
 	}
+	return fieldsGroup, nil
+}
 
-	fgId = FieldGrpHandle{
-		handle: fieldsGroup,
-	}
-	return
+func FieldGroupDestroy(fieldGroup FieldGrpHandle) error {
+	return defaultClient.FieldGroupDestroy(fieldGroup)
 }
 
-func FieldGroupDestroy(fieldGroup FieldGrpHandle) (err error) {
-	res := C.dcgmFieldGroupDestroy(handle.handle, fieldGroup.handle)
-	if err = errorString(res); err != nil {
-		return fmt.Errorf("error destroying DCGM fields group: %s", err)
+func (c *Client) FieldGroupDestroy(fieldGroup FieldGrpHandle) (err error) {
+	return c.withBackend(func(b backend) error {
+		if err := b.fieldGroupDestroy(c.res.fieldGroupId(fieldGroup)); err != nil {
+			return err
+		}
+		c.res.removeFieldGroup(fieldGroup)
+		return nil
+	})
+}
+
+func (b cgoBackend) fieldGroupDestroy(fieldGroupId C.dcgmFieldGrp_t) error {
+	res := C.dcgmFieldGroupDestroy(b.handle, fieldGroupId)
+	if err := errorString(res); err != nil {
+		return fmt.Errorf("error destroying DCGM fields group: %w", err)
 	}
 	return nil
 }
 
 func WatchFields(gpuIds []uint, fieldGrp FieldGrpHandle, groupName string) (GroupHandle, error) {
-	group, err := CreateGroup(groupName)
+	return defaultClient.WatchFields(gpuIds, fieldGrp, groupName)
+}
+
+func (c *Client) WatchFields(gpuIds []uint, fieldGrp FieldGrpHandle, groupName string) (GroupHandle, error) {
+	return c.WatchFieldsCtx(context.Background(), gpuIds, fieldGrp, groupName)
+}
+
+// WatchFieldsCtx is WatchFields returning ctx.Err() when ctx is done before the first update of the fields
+func WatchFieldsCtx(ctx context.Context, gpuIds []uint, fieldGrp FieldGrpHandle, groupName string) (GroupHandle, error) {
+	return defaultClient.WatchFieldsCtx(ctx, gpuIds, fieldGrp, groupName)
+}
+
+// WatchFieldsCtx is WatchFields returning ctx.Err() when ctx is done before the first update of the fields,
+// the group it created is destroyed when it fails
+func (c *Client) WatchFieldsCtx(ctx context.Context, gpuIds []uint, fieldGrp FieldGrpHandle, groupName string) (GroupHandle, error) {
+	if err := ctx.Err(); err != nil {
+		return GroupHandle{}, err
+	}
+	group, err := c.CreateGroup(groupName)
 	if err != nil {
 		return GroupHandle{}, err
 	}
 	for _, gpuId := range gpuIds {
-		err = AddToGroup(group, gpuId)
+		if err = ctx.Err(); err == nil {
+			err = c.AddToGroup(group, gpuId)
+		}
 		if err != nil {
+			_ = c.DestroyGroup(group)
 			return GroupHandle{}, err
 		}
 	}
 
-	res := C.dcgmWatchFields(handle.handle, group.handle, fieldGrp.handle,
-		C.longlong(defaultUpdateFreq),
-		C.double(defaultMaxKeepAge),
-		C.int(defaultMaxKeepSamples))
-	if err = errorString(res); err != nil {
-		return GroupHandle{}, fmt.Errorf("error watching DCGM fields: %s", err)
+	if err = c.WatchFieldsWithGroupCtx(ctx, fieldGrp, group); err != nil {
+		_ = c.DestroyGroup(group)
+		return GroupHandle{}, err
 	}
+	return group, nil
+}
 
-	cWaitForUpdate := C.int(1)
-	res = C.dcgmUpdateAllFields(handle.handle, cWaitForUpdate)
-	if err = errorString(res); err != nil {
-		return GroupHandle{}, fmt.Errorf("error updating all fields: %s", err)
+// watchTemporary watches fields on gpuIds through a temporary field group and group named after name,
+// for a single call. release destroys both, nothing is left when watchTemporary fails.
+func (c *Client) watchTemporary(ctx context.Context, name string, gpuIds []uint, fields []Short) (release func(), err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	fieldGrp, err := c.fieldGroupCreate(fmt.Sprintf("%sFields%d", name, rand.Uint64()), fields, true)
+	if err != nil {
+		return nil, err
+	}
+	group, err := c.temporaryGroup(ctx, fmt.Sprintf("%sGrp%d", name, rand.Uint64()), gpuIds)
+	if err != nil {
+		c.destroyTemporaryFieldGroup(fieldGrp)
+		return nil, err
+	}
+
+	release = func() {
+		c.destroyTemporaryGroup(group)
+		c.destroyTemporaryFieldGroup(fieldGrp)
+	}
+	if err = c.WatchFieldsWithGroupCtx(ctx, fieldGrp, group); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// destroyTemporaryFieldGroup destroys a field group created for a single call, a field group
+// which cannot be destroyed is kept by the client and destroyed when it is closed
+func (c *Client) destroyTemporaryFieldGroup(fieldGrp FieldGrpHandle) {
+	if err := c.FieldGroupDestroy(fieldGrp); err != nil {
+		getLogger().Warn("Failed to destroy temporary field group", "error", err)
 	}
-	return group, nil
 }
 
 func WatchFieldsWithGroupEx(
 	fieldsGroup FieldGrpHandle, group GroupHandle, updateFreq int64, maxKeepAge float64, maxKeepSamples int32,
 ) error {
-	result := C.dcgmWatchFields(handle.handle, group.handle, fieldsGroup.handle,
+	return defaultClient.WatchFieldsWithGroupEx(fieldsGroup, group, updateFreq, maxKeepAge, maxKeepSamples)
+}
+
+func (c *Client) WatchFieldsWithGroupEx(
+	fieldsGroup FieldGrpHandle, group GroupHandle, updateFreq int64, maxKeepAge float64, maxKeepSamples int32,
+) error {
+	return c.WatchFieldsWithGroupExCtx(context.Background(), fieldsGroup, group, updateFreq, maxKeepAge, maxKeepSamples)
+}
+
+// WatchFieldsWithGroupExCtx is WatchFieldsWithGroupEx returning ctx.Err() when ctx is done before the first update of the fields
+func WatchFieldsWithGroupExCtx(ctx context.Context,
+	fieldsGroup FieldGrpHandle, group GroupHandle, updateFreq int64, maxKeepAge float64, maxKeepSamples int32,
+) error {
+	return defaultClient.WatchFieldsWithGroupExCtx(ctx, fieldsGroup, group, updateFreq, maxKeepAge, maxKeepSamples)
+}
+
+// WatchFieldsWithGroupExCtx is WatchFieldsWithGroupEx returning ctx.Err() when ctx is done before the first update
+// of the fields, the fields are watched from then on
+func (c *Client) WatchFieldsWithGroupExCtx(ctx context.Context,
+	fieldsGroup FieldGrpHandle, group GroupHandle, updateFreq int64, maxKeepAge float64, maxKeepSamples int32,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := c.withBackend(func(b backend) error {
+		err := b.watchFields(c.res.groupId(group), c.res.fieldGroupId(fieldsGroup),
+			updateFreq, maxKeepAge, maxKeepSamples)
+		if err != nil {
+			return fmt.Errorf("Error watching fields: %w", err)
+		}
+		c.res.addWatch(trackedWatch{group, fieldsGroup, updateFreq, maxKeepAge, maxKeepSamples})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = callCtx(ctx, func() (struct{}, error) {
+		return struct{}{}, c.backend().updateAllFields(true)
+	})
+	return err
+}
+
+func (b cgoBackend) watchFields(
+	group C.dcgmGpuGrp_t, fieldsGroup C.dcgmFieldGrp_t, updateFreq int64, maxKeepAge float64, maxKeepSamples int32,
+) error {
+	result := C.dcgmWatchFields(b.handle, group, fieldsGroup,
 		C.longlong(updateFreq), C.double(maxKeepAge), C.int(maxKeepSamples))
+	if err := errorString(result); err != nil {
+		return fmt.Errorf("Error watching fields: %w", err)
+	}
+	return nil
+}
 
+// UnwatchFields stops watching the fields of the field group on the group
+func UnwatchFields(fieldsGroup FieldGrpHandle, group GroupHandle) error {
+	return defaultClient.UnwatchFields(fieldsGroup, group)
+}
+
+// UnwatchFields stops watching the fields of the field group on the group
+func (c *Client) UnwatchFields(fieldsGroup FieldGrpHandle, group GroupHandle) error {
+	return c.withBackend(func(b backend) error {
+		if err := b.unwatchFields(c.res.groupId(group), c.res.fieldGroupId(fieldsGroup)); err != nil {
+			return err
+		}
+		c.res.removeWatch(group, fieldsGroup)
+		return nil
+	})
+}
+
+func (b cgoBackend) unwatchFields(group C.dcgmGpuGrp_t, fieldsGroup C.dcgmFieldGrp_t) error {
+	result := C.dcgmUnwatchFields(b.handle, group, fieldsGroup)
 	if err := errorString(result); err != nil {
-		return fmt.Errorf("Error watching fields: %s", err)
+		return fmt.Errorf("error unwatching fields: %w", err)
 	}
+	return nil
+}
 
-	cWaitForUpdate := C.int(1)
-	res := C.dcgmUpdateAllFields(handle.handle, cWaitForUpdate)
+func (b cgoBackend) updateAllFields(waitForUpdate bool) error {
+	cWaitForUpdate := C.int(0)
+	if waitForUpdate {
+		cWaitForUpdate = 1
+	}
+	res := C.dcgmUpdateAllFields(b.handle, cWaitForUpdate)
 	if err := errorString(res); err != nil {
-		return fmt.Errorf("error updating all fields: %s", err)
+		return fmt.Errorf("error updating all fields: %w", err)
 	}
-
 	return nil
 }
 
 func WatchFieldsWithGroup(fieldsGroup FieldGrpHandle, group GroupHandle) error {
-	return WatchFieldsWithGroupEx(fieldsGroup, group, defaultUpdateFreq, defaultMaxKeepAge, defaultMaxKeepSamples)
+	return defaultClient.WatchFieldsWithGroup(fieldsGroup, group)
+}
+
+func (c *Client) WatchFieldsWithGroup(fieldsGroup FieldGrpHandle, group GroupHandle) error {
+	return c.WatchFieldsWithGroupEx(fieldsGroup, group, defaultUpdateFreq, defaultMaxKeepAge, defaultMaxKeepSamples)
+}
+
+// WatchFieldsWithGroupCtx is WatchFieldsWithGroup returning ctx.Err() when ctx is done before the first update of the fields
+func WatchFieldsWithGroupCtx(ctx context.Context, fieldsGroup FieldGrpHandle, group GroupHandle) error {
+	return defaultClient.WatchFieldsWithGroupCtx(ctx, fieldsGroup, group)
+}
+
+// WatchFieldsWithGroupCtx is WatchFieldsWithGroup returning ctx.Err() when ctx is done before the first update of the fields
+func (c *Client) WatchFieldsWithGroupCtx(ctx context.Context, fieldsGroup FieldGrpHandle, group GroupHandle) error {
+	return c.WatchFieldsWithGroupExCtx(ctx, fieldsGroup, group, defaultUpdateFreq, defaultMaxKeepAge, defaultMaxKeepSamples)
 }
 
 func GetLatestValuesForFields(gpu uint, fields []Short) ([]FieldValue_v1, error) {
-	values := make([]C.dcgmFieldValue_v1, len(fields))
+	return defaultClient.GetLatestValuesForFields(gpu, fields)
+}
+
+func (c *Client) GetLatestValuesForFields(gpu uint, fields []Short) ([]FieldValue_v1, error) {
+	return c.backend().getLatestValuesForFields(gpu, fields)
+}
+
+// GetLatestValuesForFieldsCtx is GetLatestValuesForFields returning ctx.Err() as soon as ctx is done
+func GetLatestValuesForFieldsCtx(ctx context.Context, gpu uint, fields []Short) ([]FieldValue_v1, error) {
+	return defaultClient.GetLatestValuesForFieldsCtx(ctx, gpu, fields)
+}
+
+// GetLatestValuesForFieldsCtx is GetLatestValuesForFields returning ctx.Err() as soon as ctx is done
+func (c *Client) GetLatestValuesForFieldsCtx(ctx context.Context, gpu uint, fields []Short) ([]FieldValue_v1, error) {
+	b := c.backend()
+	return callCtx(ctx, func() ([]FieldValue_v1, error) {
+		return b.getLatestValuesForFields(gpu, fields)
+	})
+}
+
+func (b cgoBackend) getLatestValuesForFields(gpu uint, fields []Short) ([]FieldValue_v1, error) {
+	buf := getFieldValueBuf(len(fields))
+	values := *buf
 	cFields := *(*[]C.ushort)(unsafe.Pointer(&fields))
-	res := C.dcgmGetLatestValuesForFields(handle.handle, C.int(gpu), &cFields[0], C.uint(len(fields)), &values[0])
+	res := C.dcgmGetLatestValuesForFields(b.handle, C.int(gpu), &cFields[0], C.uint(len(fields)), &values[0])
 	if err := errorString(res); err != nil {
-		return nil, fmt.Errorf("error getting latest DCGM fields values: %s", err)
+		fieldValueBufs.Put(buf)
+		return nil, fmt.Errorf("error getting latest DCGM fields values: %w", err)
 	}
-	return toFieldValue(values), nil
+	return decodeFieldValueBuf(buf), nil
 }
 
+// GetFieldValueStr formats the value of a field, "N/A" if it is blank, could not be read or is not of type typ
 func GetFieldValueStr(fv FieldValue_v1, typ string) string {
-	st := fv.Status
-	if st != C.DCGM_ST_OK {
-		return "N/A"
-	}
-
+	v := fv.TypedValue()
 	switch typ {
 	case "int64":
-		value := *(*int64)(unsafe.Pointer(&fv.Value[0]))
-		if value >= DCGM_FT_INT64_BLANK {
-			return "N/A" // indicate the field is not supported
+		if value, ok := v.Int64(); ok {
+			return fmt.Sprintf("%d", value)
 		}
-		return fmt.Sprintf("%d", value)
 
 	case "float64":
-		value := *(*float64)(unsafe.Pointer(&fv.Value[0]))
-		if value >= DCGM_FT_FP64_BLANK {
-			return "N/A" // indicate the field is not supported
+		if value, ok := v.Float64(); ok {
+			// sync the precision with the display of ixdcgmi
+			return fmt.Sprintf("%.3f", value)
 		}
-		// sync the precision with the display of ixdcgmi
-		return fmt.Sprintf("%.3f", value)
 
 	case "string":
-		// remove redundant spaces of string converted from C bytes
-		return removeBytesSpaces(fv.Value[:])
+		if value, ok := v.Str(); ok {
+			return value
+		}
 
 	default:
-		fmt.Printf("Not Supported Type: %s\n", typ)
-		os.Exit(1)
-		return "N/A"
+		getLogger().Error("Not supported field value type", "type", typ)
 	}
+	// indicate the field is not supported or has no value
+	return "N/A"
 }
 
 type Field_Entity_Group uint
@@ -198,34 +387,58 @@ func (e Field_Entity_Group) String() string {
 	return "unknown"
 }
 
-func toFieldValue(cfields []C.dcgmFieldValue_v1) []FieldValue_v1 {
+// fieldValueBufs holds the buffers the library writes the field values to, which are reused across reads
+var fieldValueBufs sync.Pool
+
+// getFieldValueBuf returns a zeroed buffer of n field values, which is decoded by decodeFieldValueBuf
+func getFieldValueBuf(n int) *[]C.dcgmFieldValue_v1 {
+	if buf, ok := fieldValueBufs.Get().(*[]C.dcgmFieldValue_v1); ok && cap(*buf) >= n {
+		*buf = (*buf)[:n]
+		clear(*buf)
+		return buf
+	}
+	buf := make([]C.dcgmFieldValue_v1, n)
+	return &buf
+}
+
+// decodeFieldValueBuf decodes the values the library wrote to buf, which is put back into fieldValueBufs
+// unless string or binary values reference it: these are only copied when they are read.
+func decodeFieldValueBuf(buf *[]C.dcgmFieldValue_v1) []FieldValue_v1 {
+	values := toFieldValue(*buf, false)
+	for _, fv := range values {
+		if fv.holdsBytes() {
+			return values
+		}
+	}
+	fieldValueBufs.Put(buf)
+	return values
+}
+
+// toFieldValue decodes the numeric values into scalars. The string and binary values reference cfields,
+// unless copyBytes is set for the memory which is only valid until toFieldValue returns.
+func toFieldValue(cfields []C.dcgmFieldValue_v1, copyBytes bool) []FieldValue_v1 {
 	fields := make([]FieldValue_v1, len(cfields))
-	for i, f := range cfields {
-		fields[i] = FieldValue_v1{
+	for i := range cfields {
+		// each value holds a 4 KiB union, it is not copied
+		f := &cfields[i]
+		fv := &fields[i]
+		*fv = FieldValue_v1{
 			Version:   uint(f.version),
 			FieldId:   uint(f.fieldId),
 			FieldType: uint(f.fieldType),
-			Status:    int(f.status),
+			Status:    ReturnCode(f.status),
 			Ts:        int64(f.ts),
-			Value:     f.value,
+		}
+		switch {
+		case fv.holdsBytes():
+			fv.setBytes(f.value[:])
+			if copyBytes {
+				fv.data = append([]byte(nil), fv.data...)
+			}
+		default:
+			fv.num = *(*uint64)(unsafe.Pointer(&f.value[0]))
 		}
 	}
 
 	return fields
 }
-
-func (fv FieldValue_v1) Int64() int64 {
-	return *(*int64)(unsafe.Pointer(&fv.Value[0]))
-}
-
-func (fv FieldValue_v1) Float64() float64 {
-	return *(*float64)(unsafe.Pointer(&fv.Value[0]))
-}
-
-func (fv FieldValue_v1) String() string {
-	return C.GoString((*C.char)(unsafe.Pointer(&fv.Value[0])))
-}
-
-func (fv FieldValue_v1) Blob() [4096]byte {
-	return fv.Value
-}
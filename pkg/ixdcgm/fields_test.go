@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+const benchmarkFields = 60
+
+// readLatestValues decodes numFields values as dcgmGetLatestValuesForFields writes them to the buffers of
+// getLatestValuesForFields, each value of which is one of the strings, a double or the index of the field
+func readLatestValues(numFields int, strings ...string) []FieldValue_v1 {
+	buf := getFieldValueBuf(numFields)
+	cvalues := *buf
+	for i := range cvalues {
+		cv := &cvalues[i]
+		cv.version = 1
+		cv.fieldId = DCGM_FI_DEV_GPU_TEMP
+		switch {
+		case i < len(strings):
+			cv.fieldType = 's' // DCGM_FT_STRING
+			copy(cv.value[:], strings[i])
+		case i == len(strings):
+			cv.fieldType = 'd' // DCGM_FT_DOUBLE
+			binary.LittleEndian.PutUint64(cv.value[:], math.Float64bits(42.5))
+		default:
+			cv.fieldType = 'i' // DCGM_FT_INT64
+			binary.LittleEndian.PutUint64(cv.value[:], uint64(i))
+		}
+	}
+	return decodeFieldValueBuf(buf)
+}
+
+func TestDecodeFieldValueBuf(t *testing.T) {
+	values := readLatestValues(3, "535.104.05")
+	if s, ok := values[0].TypedValue().Str(); !ok || s != "535.104.05" {
+		t.Errorf("string value = %q, %t", s, ok)
+	}
+	if f, ok := values[1].TypedValue().Float64(); !ok || f != 42.5 {
+		t.Errorf("double value = %g, %t", f, ok)
+	}
+	if i, ok := values[2].TypedValue().Int64(); !ok || i != 2 {
+		t.Errorf("int64 value = %d, %t", i, ok)
+	}
+
+	// the buffer of the string is not reused by the next reads
+	for i := 0; i < 10; i++ {
+		readLatestValues(3, "overwritten")
+	}
+	if s := values[0].Str(); s != "535.104.05" {
+		t.Errorf("string value changed to %q by the next reads", s)
+	}
+}
+
+func TestDecodeBlob(t *testing.T) {
+	buf := getFieldValueBuf(1)
+	cv := &(*buf)[0]
+	cv.fieldType = 'b' // DCGM_FT_BINARY
+	blob := []byte{1, 0, 2, 0, 0}
+	copy(cv.value[:], blob)
+
+	values := decodeFieldValueBuf(buf)
+	got := values[0].Blob()
+	if len(got) != len(cv.value) || !bytes.Equal(got[:len(blob)], blob) || bytes.IndexByte(got[len(blob):], 1) >= 0 {
+		t.Errorf("blob of %d bytes starting with %v, want the %d bytes of the value starting with %v",
+			len(got), got[:min(len(got), len(blob))], len(cv.value), blob)
+	}
+}
+
+func benchmarkDecodeFieldValues(b *testing.B, strings ...string) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, fv := range readLatestValues(benchmarkFields, strings...) {
+			_ = fv.TypedValue()
+		}
+	}
+}
+
+func BenchmarkDecodeNumericFieldValues(b *testing.B) {
+	benchmarkDecodeFieldValues(b)
+}
+
+func BenchmarkDecodeFieldValuesWithStrings(b *testing.B) {
+	benchmarkDecodeFieldValues(b, "535.104.05", "Iluvatar BI-V100")
+}
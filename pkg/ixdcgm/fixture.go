@@ -0,0 +1,743 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+*/
+import "C"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const fixtureVersion = 1
+
+// fixture is the JSON document the calls of a recording are saved to
+type fixture struct {
+	Version  int           `json:"version"`
+	Recorded time.Time     `json:"recorded"`
+	Calls    []fixtureCall `json:"calls"`
+}
+
+// fixtureCall is a call of the backend with its arguments and its decoded result or its error
+type fixtureCall struct {
+	Call   string          `json:"call"`
+	Args   json.RawMessage `json:"args,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *fixtureError   `json:"error,omitempty"`
+}
+
+type fixtureError struct {
+	Code    ReturnCode `json:"code"`
+	Message string     `json:"message"`
+}
+
+// fixtureGroup identifies the group of a call by its GPUs, which unlike its id are the same from a run to another.
+// Id is only set for the groups which are not created through the client.
+type fixtureGroup struct {
+	AllGpus bool    `json:"allGpus,omitempty"`
+	Gpus    []uint  `json:"gpus,omitempty"`
+	Id      uintptr `json:"id,omitempty"`
+}
+
+// fixtureCreatedGroup is a group created while recording with the GPUs it was created with
+type fixtureCreatedGroup struct {
+	Id   uintptr `json:"id"`
+	Gpus []uint  `json:"gpus,omitempty"`
+}
+
+// fixtureFieldValue is a FieldValue_v1 holding only the used part of its value
+type fixtureFieldValue struct {
+	FieldId   uint       `json:"fieldId"`
+	FieldType uint       `json:"fieldType"`
+	Status    ReturnCode `json:"status"`
+	Ts        int64      `json:"ts"`
+	Int64     *int64     `json:"int64,omitempty"`
+	Float64   *float64   `json:"float64,omitempty"`
+	String    *string    `json:"string,omitempty"`
+	Blob      []byte     `json:"blob,omitempty"`
+}
+
+func toFixtureFieldValues(values []FieldValue_v1) []fixtureFieldValue {
+	fixtureValues := make([]fixtureFieldValue, len(values))
+	for i, fv := range values {
+		v := fixtureFieldValue{FieldId: fv.FieldId, FieldType: fv.FieldType, Status: fv.Status, Ts: fv.Ts}
+		switch fv.FieldType {
+		case DCGM_FT_INT64, DCGM_FT_TIMESTAMP:
+			i64 := fv.Int64()
+			v.Int64 = &i64
+		case DCGM_FT_DOUBLE:
+			f64 := fv.Float64()
+			v.Float64 = &f64
+		case DCGM_FT_STRING:
+			str := fv.Str()
+			v.String = &str
+		default:
+			v.Blob = fv.Blob()
+		}
+		fixtureValues[i] = v
+	}
+	return fixtureValues
+}
+
+func fromFixtureFieldValues(fixtureValues []fixtureFieldValue) []FieldValue_v1 {
+	values := make([]FieldValue_v1, len(fixtureValues))
+	for i, v := range fixtureValues {
+		fv := FieldValue_v1{Version: 1, FieldId: v.FieldId, FieldType: v.FieldType, Status: v.Status, Ts: v.Ts}
+		switch {
+		case v.Int64 != nil:
+			fv.setInt64(*v.Int64)
+		case v.Float64 != nil:
+			fv.setFloat64(*v.Float64)
+		case v.String != nil:
+			fv.setBytes([]byte(*v.String))
+		default:
+			fv.setBytes(v.Blob)
+		}
+		values[i] = fv
+	}
+	return values
+}
+
+// fixtureValuesSince is the result of getValuesSince
+type fixtureValuesSince struct {
+	Values []fixtureEntityValue `json:"values,omitempty"`
+	Next   int64                `json:"next"`
+}
+
+type fixtureEntityValue struct {
+	Entity GroupEntityPair   `json:"entity"`
+	Value  fixtureFieldValue `json:"value"`
+}
+
+func toFixtureEntityValues(values []entityValue) []fixtureEntityValue {
+	fixtureValues := make([]fixtureEntityValue, len(values))
+	for i, v := range values {
+		fixtureValues[i] = fixtureEntityValue{Entity: v.entity, Value: toFixtureFieldValues([]FieldValue_v1{v.value})[0]}
+	}
+	return fixtureValues
+}
+
+func fromFixtureEntityValues(fixtureValues []fixtureEntityValue) []entityValue {
+	values := make([]entityValue, len(fixtureValues))
+	for i, v := range fixtureValues {
+		values[i] = entityValue{entity: v.Entity, value: fromFixtureFieldValues([]fixtureFieldValue{v.Value})[0]}
+	}
+	return values
+}
+
+func fixtureArgs(args ...interface{}) json.RawMessage {
+	data, err := json.Marshal(args)
+	if err != nil {
+		getLogger().Warn("Failed to encode the arguments of a call", "error", err)
+	}
+	return data
+}
+
+// StartRecording records the calls of the default client, see Client.StartRecording
+func StartRecording() error {
+	return defaultClient.StartRecording()
+}
+
+// StopRecording writes the calls recorded by the default client to a fixture file, see Client.StopRecording
+func StopRecording(path string) error {
+	return defaultClient.StopRecording(path)
+}
+
+// StartRecording records every call the client makes to IXDCGM, with its arguments and its decoded result:
+// device attributes, field values, topology, health responses, diag responses, process lists, groups, etc.
+// The recording is written by StopRecording to a JSON fixture file the API can be served from by NewReplayClient,
+// e.g. to reproduce on any machine what a client returned on a node.
+func (c *Client) StartRecording() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rec != nil {
+		return fmt.Errorf("ixdcgm client is already recording")
+	}
+	c.rec = &recorder{
+		started:     time.Now(),
+		groups:      make(map[C.dcgmGpuGrp_t][]uint),
+		fieldGroups: make(map[C.dcgmFieldGrp_t][]Short),
+	}
+	return nil
+}
+
+// StopRecording stops the recording started by StartRecording and writes it to the fixture file at path
+func (c *Client) StopRecording(path string) error {
+	c.mu.Lock()
+	rec := c.rec
+	c.rec = nil
+	c.mu.Unlock()
+	if rec == nil {
+		return fmt.Errorf("ixdcgm client is not recording")
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	data, err := json.MarshalIndent(fixture{Version: fixtureVersion, Recorded: rec.started, Calls: rec.calls}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the recording: %w", err)
+	}
+	if err = os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write the recording: %w", err)
+	}
+	return nil
+}
+
+// NewReplayClient returns a client serving the API from the fixture file written by StopRecording,
+// without the IXDCGM library. The calls return the results recorded for the same arguments in the
+// recorded order, the last result of a call is returned again once all have been returned.
+// Groups, field groups, watches and policies are simulated, so that their ids may differ from the recorded ones.
+func NewReplayClient(path string) (*Client, error) {
+	b, err := newReplayBackend(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{be: b}, nil
+}
+
+// InitReplay makes the package level functions serve the API from the fixture file, see NewReplayClient
+func InitReplay(path string) (cleanup func(), err error) {
+	b, err := newReplayBackend(path)
+	if err != nil {
+		return nil, err
+	}
+	return initWithBackend(b)
+}
+
+type recorder struct {
+	mu      sync.Mutex
+	started time.Time
+	calls   []fixtureCall
+	groups  map[C.dcgmGpuGrp_t][]uint // GPUs of the groups created while recording
+
+	fieldGroups map[C.dcgmFieldGrp_t][]Short // fields of the field groups created while recording
+}
+
+func (r *recorder) add(call string, args json.RawMessage, result interface{}, err error) {
+	fc := fixtureCall{Call: call, Args: args}
+	if err != nil {
+		fc.Error = &fixtureError{Code: ReturnCodeOf(err), Message: err.Error()}
+	} else if result != nil {
+		data, jerr := json.Marshal(result)
+		if jerr != nil {
+			getLogger().Warn("Failed to record the result of a call", "call", call, "error", jerr)
+			return
+		}
+		fc.Result = data
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, fc)
+}
+
+func (r *recorder) group(groupId C.dcgmGpuGrp_t) fixtureGroup {
+	if groupId == C.DCGM_GROUP_ALL_GPUS {
+		return fixtureGroup{AllGpus: true}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if gpus, exists := r.groups[groupId]; exists {
+		return fixtureGroup{Gpus: append([]uint(nil), gpus...)}
+	}
+	return fixtureGroup{Id: uintptr(groupId)}
+}
+
+// fieldGroup identifies a field group by its fields, which unlike its id are the same from a run to another
+func (r *recorder) fieldGroup(fieldGroupId C.dcgmFieldGrp_t) []Short {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.fieldGroups[fieldGroupId]
+}
+
+func (r *recorder) addGroupGpu(groupId C.dcgmGpuGrp_t, gpuId uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if gpus, exists := r.groups[groupId]; exists {
+		r.groups[groupId] = append(gpus, gpuId)
+	}
+}
+
+func (r *recorder) removeGroupGpu(groupId C.dcgmGpuGrp_t, gpuId uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	gpus := r.groups[groupId]
+	for i, id := range gpus {
+		if id == gpuId {
+			r.groups[groupId] = append(gpus[:i:i], gpus[i+1:]...)
+			return
+		}
+	}
+}
+
+// recordingBackend records the calls made to backend
+type recordingBackend struct {
+	backend
+	rec *recorder
+}
+
+func (r recordingBackend) getAllDevices() ([]uint, error) {
+	gpus, err := r.backend.getAllDevices()
+	r.rec.add("getAllDevices", nil, gpus, err)
+	return gpus, err
+}
+
+func (r recordingBackend) getSupportedDevices() ([]uint, error) {
+	gpus, err := r.backend.getSupportedDevices()
+	r.rec.add("getSupportedDevices", nil, gpus, err)
+	return gpus, err
+}
+
+func (r recordingBackend) getDeviceAttributes(gpuId uint) (deviceAttributes, error) {
+	attr, err := r.backend.getDeviceAttributes(gpuId)
+	r.rec.add("getDeviceAttributes", fixtureArgs(gpuId), attr, err)
+	return attr, err
+}
+
+func (r recordingBackend) getDeviceTopology(gpuId uint) ([]P2PLink, error) {
+	links, err := r.backend.getDeviceTopology(gpuId)
+	r.rec.add("getDeviceTopology", fixtureArgs(gpuId), links, err)
+	return links, err
+}
+
+func (r recordingBackend) getDeviceOnSameBoard(gpuId1, gpuId2 uint) (bool, error) {
+	onSameBoard, err := r.backend.getDeviceOnSameBoard(gpuId1, gpuId2)
+	r.rec.add("getDeviceOnSameBoard", fixtureArgs(gpuId1, gpuId2), onSameBoard, err)
+	return onSameBoard, err
+}
+
+func (r recordingBackend) getGroupTopology(groupId C.dcgmGpuGrp_t) (groupTopology, error) {
+	topology, err := r.backend.getGroupTopology(groupId)
+	r.rec.add("getGroupTopology", fixtureArgs(r.rec.group(groupId)), topology, err)
+	return topology, err
+}
+
+func (r recordingBackend) selectGpusByTopology(inputMask uint64, numGpus uint32, hintFlags uint64) (uint64, error) {
+	outputMask, err := r.backend.selectGpusByTopology(inputMask, numGpus, hintFlags)
+	r.rec.add("selectGpusByTopology", fixtureArgs(inputMask, numGpus, hintFlags), outputMask, err)
+	return outputMask, err
+}
+
+func (r recordingBackend) getDeviceRunningProcesses(gpuId uint) ([]processMemory, error) {
+	processes, err := r.backend.getDeviceRunningProcesses(gpuId)
+	r.rec.add("getDeviceRunningProcesses", fixtureArgs(gpuId), processes, err)
+	return processes, err
+}
+
+func (r recordingBackend) getEntities(group Field_Entity_Group, onlySupported bool) ([]uint, error) {
+	ids, err := r.backend.getEntities(group, onlySupported)
+	r.rec.add("getEntities", fixtureArgs(group, onlySupported), ids, err)
+	return ids, err
+}
+
+// groupCreate records the GPUs the group is created with, which are replayed for the groups which are not empty
+func (r recordingBackend) groupCreate(groupType GroupType, groupName string) (C.dcgmGpuGrp_t, error) {
+	groupId, err := r.backend.groupCreate(groupType, groupName)
+	created := fixtureCreatedGroup{Id: uintptr(groupId)}
+	if err == nil && groupType != GroupEmpty {
+		info, infoErr := r.backend.groupGetInfo(groupId)
+		if infoErr != nil {
+			getLogger().Warn("Failed to record the GPUs of a group", "group", groupName, "error", infoErr)
+		} else {
+			for _, e := range info.EntityList {
+				if e.EntityGroupId == FE_GPU {
+					created.Gpus = append(created.Gpus, e.EntityId)
+				}
+			}
+		}
+	}
+	r.rec.add("groupCreate", fixtureArgs(groupName, groupType), created, err)
+	if err == nil {
+		r.rec.mu.Lock()
+		r.rec.groups[groupId] = created.Gpus
+		r.rec.mu.Unlock()
+	}
+	return groupId, err
+}
+
+func (r recordingBackend) groupAddDevice(groupId C.dcgmGpuGrp_t, gpuId uint) error {
+	err := r.backend.groupAddDevice(groupId, gpuId)
+	r.rec.add("groupAddDevice", fixtureArgs(uintptr(groupId), gpuId), nil, err)
+	if err == nil {
+		r.rec.addGroupGpu(groupId, gpuId)
+	}
+	return err
+}
+
+func (r recordingBackend) groupAddEntity(groupId C.dcgmGpuGrp_t, entityGroupId Field_Entity_Group, entityId uint) error {
+	err := r.backend.groupAddEntity(groupId, entityGroupId, entityId)
+	r.rec.add("groupAddEntity", fixtureArgs(uintptr(groupId), entityGroupId, entityId), nil, err)
+	if err == nil && entityGroupId == FE_GPU {
+		r.rec.addGroupGpu(groupId, entityId)
+	}
+	return err
+}
+
+func (r recordingBackend) groupRemoveDevice(groupId C.dcgmGpuGrp_t, gpuId uint) error {
+	err := r.backend.groupRemoveDevice(groupId, gpuId)
+	r.rec.add("groupRemoveDevice", fixtureArgs(uintptr(groupId), gpuId), nil, err)
+	if err == nil {
+		r.rec.removeGroupGpu(groupId, gpuId)
+	}
+	return err
+}
+
+func (r recordingBackend) groupRemoveEntity(groupId C.dcgmGpuGrp_t, entityGroupId Field_Entity_Group, entityId uint) error {
+	err := r.backend.groupRemoveEntity(groupId, entityGroupId, entityId)
+	r.rec.add("groupRemoveEntity", fixtureArgs(uintptr(groupId), entityGroupId, entityId), nil, err)
+	if err == nil && entityGroupId == FE_GPU {
+		r.rec.removeGroupGpu(groupId, entityId)
+	}
+	return err
+}
+
+func (r recordingBackend) groupDestroy(groupId C.dcgmGpuGrp_t) error {
+	err := r.backend.groupDestroy(groupId)
+	r.rec.add("groupDestroy", fixtureArgs(uintptr(groupId)), nil, err)
+	if err == nil {
+		r.rec.mu.Lock()
+		delete(r.rec.groups, groupId)
+		r.rec.mu.Unlock()
+	}
+	return err
+}
+
+func (r recordingBackend) groupGetInfo(groupId C.dcgmGpuGrp_t) (*GroupInfo, error) {
+	info, err := r.backend.groupGetInfo(groupId)
+	r.rec.add("groupGetInfo", fixtureArgs(r.rec.group(groupId)), info, err)
+	return info, err
+}
+
+func (r recordingBackend) groupGetAllIds() ([]C.dcgmGpuGrp_t, error) {
+	ids, err := r.backend.groupGetAllIds()
+	recorded := make([]uintptr, len(ids))
+	for i, id := range ids {
+		recorded[i] = uintptr(id)
+	}
+	r.rec.add("groupGetAllIds", nil, recorded, err)
+	return ids, err
+}
+
+func (r recordingBackend) fieldGroupCreate(groupName string, fields []Short) (C.dcgmFieldGrp_t, error) {
+	fieldGroupId, err := r.backend.fieldGroupCreate(groupName, fields)
+	r.rec.add("fieldGroupCreate", fixtureArgs(groupName, fields), uintptr(fieldGroupId), err)
+	if err == nil {
+		r.rec.mu.Lock()
+		r.rec.fieldGroups[fieldGroupId] = append([]Short(nil), fields...)
+		r.rec.mu.Unlock()
+	}
+	return fieldGroupId, err
+}
+
+func (r recordingBackend) fieldGroupDestroy(fieldGroupId C.dcgmFieldGrp_t) error {
+	err := r.backend.fieldGroupDestroy(fieldGroupId)
+	r.rec.add("fieldGroupDestroy", fixtureArgs(uintptr(fieldGroupId)), nil, err)
+	if err == nil {
+		r.rec.mu.Lock()
+		delete(r.rec.fieldGroups, fieldGroupId)
+		r.rec.mu.Unlock()
+	}
+	return err
+}
+
+func (r recordingBackend) watchFields(
+	groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t, updateFreq int64, maxKeepAge float64, maxKeepSamples int32,
+) error {
+	err := r.backend.watchFields(groupId, fieldGroupId, updateFreq, maxKeepAge, maxKeepSamples)
+	r.rec.add("watchFields",
+		fixtureArgs(uintptr(groupId), uintptr(fieldGroupId), updateFreq, maxKeepAge, maxKeepSamples), nil, err)
+	return err
+}
+
+func (r recordingBackend) unwatchFields(groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t) error {
+	err := r.backend.unwatchFields(groupId, fieldGroupId)
+	r.rec.add("unwatchFields", fixtureArgs(uintptr(groupId), uintptr(fieldGroupId)), nil, err)
+	return err
+}
+
+func (r recordingBackend) getValuesSince(
+	groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t, since int64,
+) ([]entityValue, int64, error) {
+	values, next, err := r.backend.getValuesSince(groupId, fieldGroupId, since)
+	result := fixtureValuesSince{Values: toFixtureEntityValues(values), Next: next}
+	r.rec.add("getValuesSince", fixtureArgs(r.rec.group(groupId), r.rec.fieldGroup(fieldGroupId), since), result, err)
+	return values, next, err
+}
+
+func (r recordingBackend) updateAllFields(waitForUpdate bool) error {
+	err := r.backend.updateAllFields(waitForUpdate)
+	r.rec.add("updateAllFields", fixtureArgs(waitForUpdate), nil, err)
+	return err
+}
+
+func (r recordingBackend) getLatestValuesForFields(gpuId uint, fields []Short) ([]FieldValue_v1, error) {
+	values, err := r.backend.getLatestValuesForFields(gpuId, fields)
+	r.rec.add("getLatestValuesForFields", fixtureArgs(gpuId, fields), toFixtureFieldValues(values), err)
+	return values, err
+}
+
+func (r recordingBackend) entityGetLatestValues(entity GroupEntityPair, fields []Short) ([]FieldValue_v1, error) {
+	values, err := r.backend.entityGetLatestValues(entity, fields)
+	r.rec.add("entityGetLatestValues", fixtureArgs(entity, fields), toFixtureFieldValues(values), err)
+	return values, err
+}
+
+func (r recordingBackend) healthSet(groupId C.dcgmGpuGrp_t, systems HealthSystem) error {
+	err := r.backend.healthSet(groupId, systems)
+	r.rec.add("healthSet", fixtureArgs(uintptr(groupId), systems), nil, err)
+	return err
+}
+
+func (r recordingBackend) healthGet(groupId C.dcgmGpuGrp_t) (HealthSystem, error) {
+	systems, err := r.backend.healthGet(groupId)
+	r.rec.add("healthGet", fixtureArgs(uintptr(groupId)), systems, err)
+	return systems, err
+}
+
+func (r recordingBackend) healthCheck(groupId C.dcgmGpuGrp_t) (HealthResponse, error) {
+	response, err := r.backend.healthCheck(groupId)
+	r.rec.add("healthCheck", fixtureArgs(r.rec.group(groupId)), response, err)
+	return response, err
+}
+
+func (r recordingBackend) runDiag(groupId C.dcgmGpuGrp_t, diagType DiagType) (DiagResults, error) {
+	results, err := r.backend.runDiag(groupId, diagType)
+	r.rec.add("runDiag", fixtureArgs(r.rec.group(groupId), diagType), results, err)
+	return results, err
+}
+
+func (r recordingBackend) policySet(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t, paramList []policyConditionParam) error {
+	err := r.backend.policySet(groupId, condition, paramList)
+	r.rec.add("policySet", fixtureArgs(uintptr(groupId), uint(condition), paramList), nil, err)
+	return err
+}
+
+func (r recordingBackend) policyRegister(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t, slot int) error {
+	err := r.backend.policyRegister(groupId, condition, slot)
+	r.rec.add("policyRegister", fixtureArgs(uintptr(groupId), uint(condition)), nil, err)
+	return err
+}
+
+func (r recordingBackend) policyUnregister(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t) error {
+	err := r.backend.policyUnregister(groupId, condition)
+	r.rec.add("policyUnregister", fixtureArgs(uintptr(groupId), uint(condition)), nil, err)
+	return err
+}
+
+// replayBackend serves the calls recorded in a fixture. Groups, field groups, watches and policies,
+// ids of which change from a run to another, are simulated by a FakeBackend.
+type replayBackend struct {
+	*FakeBackend
+
+	callsMu sync.Mutex
+	calls   map[string][]fixtureCall // recorded calls by name and arguments, in the recorded order
+}
+
+func newReplayBackend(path string) (*replayBackend, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var f fixture
+	if err = json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %s: %w", path, err)
+	}
+	if f.Version != fixtureVersion {
+		return nil, fmt.Errorf("unsupported version %d of fixture %s, expected %d", f.Version, path, fixtureVersion)
+	}
+
+	r := &replayBackend{
+		FakeBackend: NewFakeBackend(C.DCGM_MAX_NUM_DEVICES),
+		calls:       make(map[string][]fixtureCall),
+	}
+	for _, call := range f.Calls {
+		// the arguments are indented in the file
+		var args bytes.Buffer
+		if len(call.Args) > 0 {
+			if err = json.Compact(&args, call.Args); err != nil {
+				return nil, fmt.Errorf("failed to decode the arguments of %s in fixture %s: %w", call.Call, path, err)
+			}
+		}
+		key := call.Call + args.String()
+		r.calls[key] = append(r.calls[key], call)
+	}
+	return r, nil
+}
+
+// replay decodes the next result recorded for the call into result
+func (r *replayBackend) replay(call string, args json.RawMessage, result interface{}) error {
+	key := call + string(args)
+	r.callsMu.Lock()
+	recorded := r.calls[key]
+	if len(recorded) == 0 {
+		r.callsMu.Unlock()
+		return fmt.Errorf("no %s call recorded with arguments %s: %w", call, args, newDcgmError(DCGM_ST_NO_DATA))
+	}
+	if len(recorded) > 1 {
+		r.calls[key] = recorded[1:]
+	}
+	r.callsMu.Unlock()
+
+	fc := recorded[0]
+	if fc.Error != nil {
+		return &DcgmError{msg: fc.Error.Message, Code: fc.Error.Code}
+	}
+	if len(fc.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(fc.Result, result); err != nil {
+		return fmt.Errorf("failed to decode the recorded result of %s: %w", call, err)
+	}
+	return nil
+}
+
+func (r *replayBackend) group(groupId C.dcgmGpuGrp_t) fixtureGroup {
+	if groupId == C.DCGM_GROUP_ALL_GPUS {
+		return fixtureGroup{AllGpus: true}
+	}
+	r.FakeBackend.mu.Lock()
+	defer r.FakeBackend.mu.Unlock()
+	if grp, exists := r.groups[groupId]; exists {
+		return fixtureGroup{Gpus: append([]uint(nil), grp.gpuIds...)}
+	}
+	return fixtureGroup{Id: uintptr(groupId)}
+}
+
+func (r *replayBackend) getAllDevices() (gpus []uint, err error) {
+	err = r.replay("getAllDevices", nil, &gpus)
+	return
+}
+
+func (r *replayBackend) getSupportedDevices() (gpus []uint, err error) {
+	err = r.replay("getSupportedDevices", nil, &gpus)
+	return
+}
+
+func (r *replayBackend) getDeviceAttributes(gpuId uint) (attr deviceAttributes, err error) {
+	err = r.replay("getDeviceAttributes", fixtureArgs(gpuId), &attr)
+	return
+}
+
+func (r *replayBackend) getDeviceTopology(gpuId uint) (links []P2PLink, err error) {
+	err = r.replay("getDeviceTopology", fixtureArgs(gpuId), &links)
+	return
+}
+
+func (r *replayBackend) getDeviceOnSameBoard(gpuId1, gpuId2 uint) (onSameBoard bool, err error) {
+	err = r.replay("getDeviceOnSameBoard", fixtureArgs(gpuId1, gpuId2), &onSameBoard)
+	return
+}
+
+func (r *replayBackend) getGroupTopology(groupId C.dcgmGpuGrp_t) (topology groupTopology, err error) {
+	err = r.replay("getGroupTopology", fixtureArgs(r.group(groupId)), &topology)
+	return
+}
+
+func (r *replayBackend) selectGpusByTopology(inputMask uint64, numGpus uint32, hintFlags uint64) (outputMask uint64, err error) {
+	err = r.replay("selectGpusByTopology", fixtureArgs(inputMask, numGpus, hintFlags), &outputMask)
+	return
+}
+
+func (r *replayBackend) getDeviceRunningProcesses(gpuId uint) (processes []processMemory, err error) {
+	err = r.replay("getDeviceRunningProcesses", fixtureArgs(gpuId), &processes)
+	return
+}
+
+// groupCreate creates the group with the recorded GPUs, which may differ from the simulated ones, unless it is empty
+func (r *replayBackend) groupCreate(groupType GroupType, groupName string) (C.dcgmGpuGrp_t, error) {
+	if groupType == GroupEmpty {
+		return r.FakeBackend.groupCreate(groupType, groupName)
+	}
+	var created fixtureCreatedGroup
+	if err := r.replay("groupCreate", fixtureArgs(groupName, groupType), &created); err != nil {
+		return 0, err
+	}
+	groupId, err := r.FakeBackend.groupCreate(GroupEmpty, groupName)
+	if err != nil {
+		return 0, err
+	}
+	for _, gpuId := range created.Gpus {
+		if err = r.FakeBackend.groupAddDevice(groupId, gpuId); err != nil {
+			return 0, err
+		}
+	}
+	return groupId, nil
+}
+
+func (r *replayBackend) groupGetInfo(groupId C.dcgmGpuGrp_t) (*GroupInfo, error) {
+	if groupId != C.DCGM_GROUP_ALL_GPUS {
+		return r.FakeBackend.groupGetInfo(groupId)
+	}
+	var info *GroupInfo
+	err := r.replay("groupGetInfo", fixtureArgs(r.group(groupId)), &info)
+	return info, err
+}
+
+func (r *replayBackend) getLatestValuesForFields(gpuId uint, fields []Short) ([]FieldValue_v1, error) {
+	var values []fixtureFieldValue
+	if err := r.replay("getLatestValuesForFields", fixtureArgs(gpuId, fields), &values); err != nil {
+		return nil, err
+	}
+	return fromFixtureFieldValues(values), nil
+}
+
+// getValuesSince returns the recorded values, the timestamp it returns is the recorded one
+// so that the next call is made with the recorded arguments
+func (r *replayBackend) getValuesSince(
+	groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t, since int64,
+) ([]entityValue, int64, error) {
+	r.FakeBackend.mu.Lock()
+	fields := r.FakeBackend.fieldGroups[fieldGroupId]
+	r.FakeBackend.mu.Unlock()
+
+	var result fixtureValuesSince
+	if err := r.replay("getValuesSince", fixtureArgs(r.group(groupId), fields, since), &result); err != nil {
+		return nil, since, err
+	}
+	return fromFixtureEntityValues(result.Values), result.Next, nil
+}
+
+func (r *replayBackend) getEntities(group Field_Entity_Group, onlySupported bool) (ids []uint, err error) {
+	err = r.replay("getEntities", fixtureArgs(group, onlySupported), &ids)
+	return
+}
+
+func (r *replayBackend) entityGetLatestValues(entity GroupEntityPair, fields []Short) ([]FieldValue_v1, error) {
+	var values []fixtureFieldValue
+	if err := r.replay("entityGetLatestValues", fixtureArgs(entity, fields), &values); err != nil {
+		return nil, err
+	}
+	return fromFixtureFieldValues(values), nil
+}
+
+func (r *replayBackend) healthCheck(groupId C.dcgmGpuGrp_t) (response HealthResponse, err error) {
+	err = r.replay("healthCheck", fixtureArgs(r.group(groupId)), &response)
+	return
+}
+
+func (r *replayBackend) runDiag(groupId C.dcgmGpuGrp_t, diagType DiagType) (results DiagResults, err error) {
+	err = r.replay("runDiag", fixtureArgs(r.group(groupId), diagType), &results)
+	return
+}
@@ -0,0 +1,150 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	if err := fake.SetGPU(1, ixdcgm.FakeGPU{
+		Uuid: "GPU-1", BusId: "00000000:1B:00.0", Name: "Iluvatar MR-V100", Serial: "S1",
+		DriverVersion: "4.2.0", PowerLimit: 150, MemoryTotal: 32768, MemoryUsed: 1024,
+	}); err != nil {
+		t.Fatalf("SetGPU failed: %v", err)
+	}
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_GPU_TEMP, 45, 46)
+	fake.SetFieldFloat64(1, ixdcgm.DCGM_FI_DEV_POWER_USAGE, 123.4567)
+	fake.InjectHealthIncident(ixdcgm.Incident{
+		System:     ixdcgm.DCGM_HEALTH_WATCH_MEM,
+		Health:     ixdcgm.DCGM_HEALTH_RESULT_FAIL,
+		Error:      ixdcgm.DiagErrorDetail{Message: "double-bit ECC error", Code: 1},
+		EntityInfo: ixdcgm.GroupEntityPair{EntityGroupId: ixdcgm.FE_GPU, EntityId: 1},
+	})
+	recorded := ixdcgm.NewFakeClient(fake)
+	defer recorded.Close()
+
+	if err := recorded.StartRecording(); err != nil {
+		t.Fatalf("StartRecording failed: %v", err)
+	}
+	if err := recorded.StartRecording(); err == nil {
+		t.Errorf("StartRecording of a recording client succeeded")
+	}
+	info, err := recorded.GetDeviceInfo(1)
+	if err != nil {
+		t.Fatalf("GetDeviceInfo failed: %v", err)
+	}
+	status1, err := recorded.GetDeviceStatus(1)
+	if err != nil {
+		t.Fatalf("GetDeviceStatus failed: %v", err)
+	}
+	status2, err := recorded.GetDeviceStatus(1)
+	if err != nil {
+		t.Fatalf("GetDeviceStatus failed: %v", err)
+	}
+	health, err := recorded.HealthCheckByGpuId(1)
+	if err != nil {
+		t.Fatalf("HealthCheckByGpuId failed: %v", err)
+	}
+	diag, err := recorded.RunDiag(ixdcgm.DiagQuick, ixdcgm.GroupAllGPUs())
+	if err != nil {
+		t.Fatalf("RunDiag failed: %v", err)
+	}
+	_, unknownErr := recorded.GetDeviceInfo(7)
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	if err = recorded.StopRecording(path); err != nil {
+		t.Fatalf("StopRecording failed: %v", err)
+	}
+	if err = recorded.StopRecording(path); err == nil {
+		t.Errorf("StopRecording of a client which is not recording succeeded")
+	}
+
+	replayed, err := ixdcgm.NewReplayClient(path)
+	if err != nil {
+		t.Fatalf("NewReplayClient failed: %v", err)
+	}
+	defer replayed.Close()
+
+	if got, err := replayed.GetDeviceInfo(1); err != nil || !reflect.DeepEqual(got, info) {
+		t.Errorf("replayed GetDeviceInfo = %+v, %v, want %+v", got, err, info)
+	}
+	// the values are replayed in the recorded order
+	for _, want := range []ixdcgm.DeviceStatus{status1, status2} {
+		if got, err := replayed.GetDeviceStatus(1); err != nil || got != want {
+			t.Errorf("replayed GetDeviceStatus = %+v, %v, want %+v", got, err, want)
+		}
+	}
+	if got, err := replayed.HealthCheckByGpuId(1); err != nil || !reflect.DeepEqual(got, health) {
+		t.Errorf("replayed HealthCheckByGpuId = %+v, %v, want %+v", got, err, health)
+	}
+	if got, err := replayed.RunDiag(ixdcgm.DiagQuick, ixdcgm.GroupAllGPUs()); err != nil || !reflect.DeepEqual(got, diag) {
+		t.Errorf("replayed RunDiag = %+v, %v, want %+v", got, err, diag)
+	}
+	if _, err = replayed.GetDeviceInfo(7); ixdcgm.ReturnCodeOf(err) != ixdcgm.ReturnCodeOf(unknownErr) {
+		t.Errorf("replayed GetDeviceInfo of an unknown GPU = %v, want %v", err, unknownErr)
+	}
+	if _, err = replayed.GetDeviceInfo(0); !errors.Is(err, ixdcgm.ErrNoData) {
+		t.Errorf("GetDeviceInfo of a GPU which was not recorded = %v, want ErrNoData", err)
+	}
+}
+
+func TestRecordAndReplayWatcher(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_GPU_TEMP, 45)
+	recorded := ixdcgm.NewFakeClient(fake)
+	defer recorded.Close()
+
+	// the values are read once when the watcher starts, then every hour
+	gpus := []ixdcgm.GroupEntityPair{{EntityGroupId: ixdcgm.FE_GPU, EntityId: 1}}
+	fields := []ixdcgm.Short{ixdcgm.DCGM_FI_DEV_GPU_TEMP}
+	firstSample := func(client *ixdcgm.Client) ixdcgm.Sample {
+		t.Helper()
+		w, err := client.NewWatcher(gpus, fields, time.Hour, 0, 0)
+		if err != nil {
+			t.Fatalf("NewWatcher failed: %v", err)
+		}
+		defer w.Close()
+		return <-w.Samples()
+	}
+
+	if err := recorded.StartRecording(); err != nil {
+		t.Fatalf("StartRecording failed: %v", err)
+	}
+	want := firstSample(recorded)
+	path := filepath.Join(t.TempDir(), "watcher.json")
+	if err := recorded.StopRecording(path); err != nil {
+		t.Fatalf("StopRecording failed: %v", err)
+	}
+
+	replayed, err := ixdcgm.NewReplayClient(path)
+	if err != nil {
+		t.Fatalf("NewReplayClient failed: %v", err)
+	}
+	defer replayed.Close()
+	if got := firstSample(replayed); !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed sample = %+v, want %+v", got, want)
+	}
+}
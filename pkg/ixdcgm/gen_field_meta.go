@@ -0,0 +1,130 @@
+//go:build ignore
+
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// gen_field_meta generates field_meta_table.go from the field definitions of include/dcgm_fields.h
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var (
+	defineRe  = regexp.MustCompile(`^#define (DCGM_FI_\w+) (\d+)\s*$`)
+	markerRe  = regexp.MustCompile(`(FIRST|LAST)_\w*FIELD_ID$|_FIELDS_\d+_(START|END)$|^DCGM_FI_MAX_FIELDS$`)
+	htmlRe    = regexp.MustCompile(`<[^>]+>|&nbsp;`)
+	spacesRe  = regexp.MustCompile(`\s+`)
+	header    = "include/dcgm_fields.h"
+	generated = "field_meta_table.go"
+)
+
+// int32Fields lists the fields whose raw values are 32-bit integers. dcgm_fields.h does not give the size of
+// the fields, the registry needs it to recognise their blank values when the library is not loaded.
+var int32Fields = map[string]bool{
+	"DCGM_FI_DEV_SM_CLOCK":        true,
+	"DCGM_FI_DEV_MEM_CLOCK":       true,
+	"DCGM_FI_DEV_APP_SM_CLOCK":    true,
+	"DCGM_FI_DEV_APP_MEM_CLOCK":   true,
+	"DCGM_FI_DEV_MAX_SM_CLOCK":    true,
+	"DCGM_FI_DEV_MAX_MEM_CLOCK":   true,
+	"DCGM_FI_DEV_MEMORY_TEMP":     true,
+	"DCGM_FI_DEV_GPU_TEMP":        true,
+	"DCGM_FI_DEV_SLOWDOWN_TEMP":   true,
+	"DCGM_FI_DEV_SHUTDOWN_TEMP":   true,
+	"DCGM_FI_DEV_FAN_SPEED":       true,
+	"DCGM_FI_DEV_PSTATE":          true,
+	"DCGM_FI_DEV_PCIE_LINK_GEN":   true,
+	"DCGM_FI_DEV_PCIE_LINK_WIDTH": true,
+	"DCGM_FI_DEV_GPU_UTIL":        true,
+	"DCGM_FI_DEV_MEM_COPY_UTIL":   true,
+	"DCGM_FI_DEV_ENC_UTIL":        true,
+	"DCGM_FI_DEV_DEC_UTIL":        true,
+}
+
+func main() {
+	f, err := os.Open(header)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	var out bytes.Buffer
+	license, err := os.ReadFile("backend.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+	out.Write(license[:bytes.Index(license, []byte("*/"))+3])
+	fmt.Fprintf(&out, "\n// Code generated by gen_field_meta.go from %s; DO NOT EDIT.\n\n", header)
+	out.WriteString("package ixdcgm\n\n")
+	out.WriteString("// fieldMetaTable lists the fields defined by dcgm_fields.h with their description, and the size of the\n")
+	out.WriteString("// 32-bit fields\n")
+	out.WriteString("var fieldMetaTable = []FieldMeta{\n")
+
+	var comment []string
+	inComment := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "/*"):
+			inComment, comment = true, nil
+			line = strings.TrimLeft(line, "/*")
+			fallthrough
+		case inComment:
+			if i := strings.Index(line, "*/"); i >= 0 {
+				line, inComment = line[:i], false
+			}
+			line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+			if line = strings.TrimSpace(htmlRe.ReplaceAllString(line, " ")); line != "" {
+				comment = append(comment, line)
+			}
+		case defineRe.MatchString(line):
+			m := defineRe.FindStringSubmatch(line)
+			if !markerRe.MatchString(m[1]) {
+				description := spacesRe.ReplaceAllString(strings.Join(comment, " "), " ")
+				size := ""
+				if int32Fields[m[1]] {
+					size = ", Size: 4"
+				}
+				fmt.Fprintf(&out, "\t{Id: %s, Name: %q, Description: %q%s},\n", m[2], m[1], description, size)
+			}
+			comment = nil
+		case line != "":
+			comment = nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	out.WriteString("}\n")
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile(generated, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -22,48 +22,233 @@ package ixdcgm
 #include "include/dcgm_structs.h"
 */
 import "C"
+import (
+	"context"
+	"errors"
+	"fmt"
+)
 
+// GroupType is what a group holds when it is created
+type GroupType int
+
+const (
+	GroupDefault                 GroupType = C.DCGM_GROUP_DEFAULT                   // all the GPUs of the node
+	GroupEmpty                   GroupType = C.DCGM_GROUP_EMPTY                     // nothing, entities are added afterwards
+	GroupDefaultSwitches         GroupType = C.DCGM_GROUP_DEFAULT_NVSWITCHES        // all the switches of the node
+	GroupDefaultInstances        GroupType = C.DCGM_GROUP_DEFAULT_INSTANCES         // all the GPU instances of the node
+	GroupDefaultComputeInstances GroupType = C.DCGM_GROUP_DEFAULT_COMPUTE_INSTANCES // all the compute instances of the node
+	GroupDefaultEverything       GroupType = C.DCGM_GROUP_DEFAULT_EVERYTHING        // all the entities of the node
+)
+
+// GroupHandle identifies a group. The handle of a group created through a client keeps identifying it
+// across reconnects, while the group is created again with a new id, the other groups are identified
+// by their id on the ix-hostengine.
 type GroupHandle struct {
 	handle C.dcgmGpuGrp_t
+	group  *trackedGroup // the group created through a client, nil for the other groups
 }
 
+// SetHandle makes g the handle of the group of the given id on the ix-hostengine
 func (g *GroupHandle) SetHandle(val uintptr) {
-	g.handle = C.dcgmGpuGrp_t(val)
+	*g = GroupHandle{handle: C.dcgmGpuGrp_t(val)}
 }
 
+// GetHandle returns the id of the group on the ix-hostengine, e.g. as listed by `ixdcgmi group -l`,
+// which is the id on the current connection for a group created through a client
 func (g *GroupHandle) GetHandle() uintptr {
+	if g.group != nil {
+		return uintptr(g.group.id())
+	}
 	return uintptr(g.handle)
 }
 
 func GroupAllGPUs() GroupHandle {
-	return GroupHandle{C.DCGM_GROUP_ALL_GPUS}
+	return GroupHandle{handle: C.DCGM_GROUP_ALL_GPUS}
 }
 
 func CreateGroup(groupName string) (GroupHandle, error) {
+	return defaultClient.CreateGroup(groupName)
+}
+
+func (c *Client) CreateGroup(groupName string) (GroupHandle, error) {
+	return c.createGroup(groupName, GroupEmpty, false)
+}
+
+// CreateGroupWithType creates a group holding the entities of groupType, e.g. all the GPUs for GroupDefault
+func CreateGroupWithType(groupName string, groupType GroupType) (GroupHandle, error) {
+	return defaultClient.CreateGroupWithType(groupName, groupType)
+}
+
+// CreateGroupWithType creates a group holding the entities of groupType, e.g. all the GPUs for GroupDefault
+func (c *Client) CreateGroupWithType(groupName string, groupType GroupType) (GroupHandle, error) {
+	return c.createGroup(groupName, groupType, false)
+}
+
+func (c *Client) createGroup(groupName string, groupType GroupType, temporary bool) (group GroupHandle, err error) {
+	err = c.withBackend(func(b backend) error {
+		cGroupId, err := b.groupCreate(groupType, groupName)
+		if err != nil {
+			return err
+		}
+		group = c.res.addGroup(cGroupId, groupName, groupType, temporary)
+		return nil
+	})
+	return group, err
+}
+
+// temporaryGroup creates a group of gpuIds for a single call, nothing is left when it fails
+func (c *Client) temporaryGroup(ctx context.Context, groupName string, gpuIds []uint) (GroupHandle, error) {
+	if err := ctx.Err(); err != nil {
+		return GroupHandle{}, err
+	}
+	group, err := c.createGroup(groupName, GroupEmpty, true)
+	if err != nil {
+		return GroupHandle{}, err
+	}
+	for _, gpuId := range gpuIds {
+		if err = ctx.Err(); err == nil {
+			err = c.AddToGroup(group, gpuId)
+		}
+		if err != nil {
+			c.destroyTemporaryGroup(group)
+			return GroupHandle{}, err
+		}
+	}
+	return group, nil
+}
+
+// destroyTemporaryGroup destroys a group created by temporaryGroup, a group which cannot be destroyed
+// is kept by the client and destroyed when it is closed
+func (c *Client) destroyTemporaryGroup(group GroupHandle) {
+	if err := c.DestroyGroup(group); err != nil {
+		getLogger().Warn("Failed to destroy temporary group", "error", err)
+	}
+}
+
+func (b cgoBackend) groupCreate(groupType GroupType, groupName string) (C.dcgmGpuGrp_t, error) {
 	var cGroupId C.dcgmGpuGrp_t
 	cgn := string2Char(groupName)
 	defer freeCString(cgn)
 
-	res := C.dcgmGroupCreate(handle.handle, C.DCGM_GROUP_EMPTY, cgn, &cGroupId)
+	res := C.dcgmGroupCreate(b.handle, C.dcgmGroupType_t(groupType), cgn, &cGroupId)
 	if err := errorString(res); err != nil {
-		return GroupHandle{}, err
+		return cGroupId, fmt.Errorf("Error creating group %s: %w", groupName, err)
 	}
-
-	return GroupHandle{cGroupId}, nil
+	return cGroupId, nil
 }
 
 func AddToGroup(groupId GroupHandle, gpuId uint) error {
-	res := C.dcgmGroupAddDevice(handle.handle, groupId.handle, C.uint(gpuId))
+	return defaultClient.AddToGroup(groupId, gpuId)
+}
+
+func (c *Client) AddToGroup(groupId GroupHandle, gpuId uint) error {
+	return c.withBackend(func(b backend) error {
+		if err := b.groupAddDevice(c.res.groupId(groupId), gpuId); err != nil {
+			return err
+		}
+		c.res.addGroupEntity(groupId, GroupEntityPair{EntityGroupId: FE_GPU, EntityId: gpuId})
+		return nil
+	})
+}
+
+func (b cgoBackend) groupAddDevice(groupId C.dcgmGpuGrp_t, gpuId uint) error {
+	res := C.dcgmGroupAddDevice(b.handle, groupId, C.uint(gpuId))
 	if err := errorString(res); err != nil {
-		return err
+		return fmt.Errorf("Error adding gpu %d to group: %w", gpuId, err)
+	}
+	return nil
+}
+
+// AddEntityToGroup adds an entity of any entity group, e.g. a GPU instance, to the group
+func AddEntityToGroup(groupId GroupHandle, entityGroupId Field_Entity_Group, entityId uint) error {
+	return defaultClient.AddEntityToGroup(groupId, entityGroupId, entityId)
+}
+
+// AddEntityToGroup adds an entity of any entity group, e.g. a GPU instance, to the group
+func (c *Client) AddEntityToGroup(groupId GroupHandle, entityGroupId Field_Entity_Group, entityId uint) error {
+	return c.withBackend(func(b backend) error {
+		if err := b.groupAddEntity(c.res.groupId(groupId), entityGroupId, entityId); err != nil {
+			return err
+		}
+		c.res.addGroupEntity(groupId, GroupEntityPair{EntityGroupId: entityGroupId, EntityId: entityId})
+		return nil
+	})
+}
+
+func (b cgoBackend) groupAddEntity(groupId C.dcgmGpuGrp_t, entityGroupId Field_Entity_Group, entityId uint) error {
+	res := C.dcgmGroupAddEntity(b.handle, groupId, C.dcgm_field_entity_group_t(entityGroupId), C.dcgm_field_eid_t(entityId))
+	if err := errorString(res); err != nil {
+		return fmt.Errorf("Error adding %s %d to group: %w", entityGroupId, entityId, err)
+	}
+	return nil
+}
+
+// RemoveFromGroup removes a GPU from the group
+func RemoveFromGroup(groupId GroupHandle, gpuId uint) error {
+	return defaultClient.RemoveFromGroup(groupId, gpuId)
+}
+
+// RemoveFromGroup removes a GPU from the group
+func (c *Client) RemoveFromGroup(groupId GroupHandle, gpuId uint) error {
+	return c.withBackend(func(b backend) error {
+		if err := b.groupRemoveDevice(c.res.groupId(groupId), gpuId); err != nil {
+			return err
+		}
+		c.res.removeGroupEntity(groupId, GroupEntityPair{EntityGroupId: FE_GPU, EntityId: gpuId})
+		return nil
+	})
+}
+
+func (b cgoBackend) groupRemoveDevice(groupId C.dcgmGpuGrp_t, gpuId uint) error {
+	res := C.dcgmGroupRemoveDevice(b.handle, groupId, C.uint(gpuId))
+	if err := errorString(res); err != nil {
+		return fmt.Errorf("Error removing gpu %d from group: %w", gpuId, err)
+	}
+	return nil
+}
+
+// RemoveEntityFromGroup removes an entity of any entity group from the group
+func RemoveEntityFromGroup(groupId GroupHandle, entityGroupId Field_Entity_Group, entityId uint) error {
+	return defaultClient.RemoveEntityFromGroup(groupId, entityGroupId, entityId)
+}
+
+// RemoveEntityFromGroup removes an entity of any entity group from the group
+func (c *Client) RemoveEntityFromGroup(groupId GroupHandle, entityGroupId Field_Entity_Group, entityId uint) error {
+	return c.withBackend(func(b backend) error {
+		if err := b.groupRemoveEntity(c.res.groupId(groupId), entityGroupId, entityId); err != nil {
+			return err
+		}
+		c.res.removeGroupEntity(groupId, GroupEntityPair{EntityGroupId: entityGroupId, EntityId: entityId})
+		return nil
+	})
+}
+
+func (b cgoBackend) groupRemoveEntity(groupId C.dcgmGpuGrp_t, entityGroupId Field_Entity_Group, entityId uint) error {
+	res := C.dcgmGroupRemoveEntity(b.handle, groupId, C.dcgm_field_entity_group_t(entityGroupId), C.dcgm_field_eid_t(entityId))
+	if err := errorString(res); err != nil {
+		return fmt.Errorf("Error removing %s %d from group: %w", entityGroupId, entityId, err)
 	}
 	return nil
 }
 
 func DestroyGroup(groupId GroupHandle) error {
-	res := C.dcgmGroupDestroy(handle.handle, groupId.handle)
+	return defaultClient.DestroyGroup(groupId)
+}
+
+func (c *Client) DestroyGroup(groupId GroupHandle) error {
+	return c.withBackend(func(b backend) error {
+		if err := b.groupDestroy(c.res.groupId(groupId)); err != nil {
+			return err
+		}
+		c.res.removeGroup(groupId)
+		return nil
+	})
+}
+
+func (b cgoBackend) groupDestroy(groupId C.dcgmGpuGrp_t) error {
+	res := C.dcgmGroupDestroy(b.handle, groupId)
 	if err := errorString(res); err != nil {
-		return err
+		return fmt.Errorf("Error destroying group: %w", err)
 	}
 	return nil
 }
@@ -75,13 +260,25 @@ type GroupInfo struct {
 }
 
 func GetGroupInfo(groupId GroupHandle) (*GroupInfo, error) {
+	return defaultClient.GetGroupInfo(groupId)
+}
+
+func (c *Client) GetGroupInfo(groupId GroupHandle) (info *GroupInfo, err error) {
+	err = c.withBackend(func(b backend) (err error) {
+		info, err = b.groupGetInfo(c.res.groupId(groupId))
+		return err
+	})
+	return info, err
+}
+
+func (b cgoBackend) groupGetInfo(groupId C.dcgmGpuGrp_t) (*GroupInfo, error) {
 	response := C.dcgmGroupInfo_v2{
 		version: C.dcgmGroupInfo_version2,
 	}
 
-	result := C.dcgmGroupGetInfo(handle.handle, groupId.handle, &response)
+	result := C.dcgmGroupGetInfo(b.handle, groupId, &response)
 	if err := errorString(result); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("Error getting group info: %w", err)
 	}
 
 	ret := &GroupInfo{
@@ -98,3 +295,95 @@ func GetGroupInfo(groupId GroupHandle) (*GroupInfo, error) {
 
 	return ret, nil
 }
+
+// GetAllGroupIds returns the handles of all the groups of the ix-hostengine, see Client.GetAllGroupIds
+func GetAllGroupIds() ([]GroupHandle, error) {
+	return defaultClient.GetAllGroupIds()
+}
+
+// GetAllGroupIds returns the handles of all the groups of the ix-hostengine, including the groups created
+// by other clients, e.g. by ixdcgmi group -c. The groups created through the client keep the handle they
+// were created with.
+func (c *Client) GetAllGroupIds() (groups []GroupHandle, err error) {
+	err = c.withBackend(func(b backend) error {
+		ids, err := b.groupGetAllIds()
+		if err != nil {
+			return err
+		}
+
+		groups = make([]GroupHandle, len(ids))
+		for i, id := range ids {
+			groups[i] = c.res.groupHandle(id)
+		}
+		return nil
+	})
+	return groups, err
+}
+
+func (b cgoBackend) groupGetAllIds() ([]C.dcgmGpuGrp_t, error) {
+	var groupIdList [C.DCGM_MAX_NUM_GROUPS]C.dcgmGpuGrp_t
+	var count C.uint
+
+	result := C.dcgmGroupGetAllIds(b.handle, &groupIdList[0], &count)
+	if err := errorString(result); err != nil {
+		return nil, fmt.Errorf("Error getting group ids: %w", err)
+	}
+
+	ids := make([]C.dcgmGpuGrp_t, int(count))
+	copy(ids, groupIdList[:count])
+	return ids, nil
+}
+
+// Group is a group of the ix-hostengine as listed by ListGroups
+type Group struct {
+	Handle GroupHandle
+	GroupInfo
+}
+
+// ListGroups returns all the groups of the ix-hostengine with their name and entities, see Client.ListGroups
+func ListGroups() ([]Group, error) {
+	return defaultClient.ListGroups()
+}
+
+// ListGroups returns all the groups of the ix-hostengine with their name and entities.
+// The groups created by other clients are not destroyed when the client is closed.
+func (c *Client) ListGroups() ([]Group, error) {
+	handles, err := c.GetAllGroupIds()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]Group, 0, len(handles))
+	for _, handle := range handles {
+		info, err := c.GetGroupInfo(handle)
+		if errors.Is(err, ErrNotConfigured) {
+			// destroyed since it was listed
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, Group{Handle: handle, GroupInfo: *info})
+	}
+	return groups, nil
+}
+
+// FindGroupByName returns the first group named name, see Client.FindGroupByName
+func FindGroupByName(name string) (GroupHandle, error) {
+	return defaultClient.FindGroupByName(name)
+}
+
+// FindGroupByName returns the first listed group named name, e.g. to use a group created by ixdcgmi group -c
+// instead of creating another one. It returns an error wrapping ErrNotConfigured if there is none.
+func (c *Client) FindGroupByName(name string) (GroupHandle, error) {
+	groups, err := c.ListGroups()
+	if err != nil {
+		return GroupHandle{}, err
+	}
+	for _, grp := range groups {
+		if grp.GroupName == name {
+			return grp.Handle, nil
+		}
+	}
+	return GroupHandle{}, fmt.Errorf("Error finding group %s: %w", name, ErrNotConfigured)
+}
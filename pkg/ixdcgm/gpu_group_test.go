@@ -0,0 +1,120 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"errors"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func groupGpus(t *testing.T, client *ixdcgm.Client, group ixdcgm.GroupHandle) []uint {
+	t.Helper()
+	info, err := client.GetGroupInfo(group)
+	if err != nil {
+		t.Fatalf("GetGroupInfo failed: %v", err)
+	}
+	var gpus []uint
+	for _, e := range info.EntityList {
+		gpus = append(gpus, e.EntityId)
+	}
+	return gpus
+}
+
+func TestGroupEntities(t *testing.T) {
+	client := ixdcgm.NewFakeClient(ixdcgm.NewFakeBackend(3))
+	defer client.Close()
+
+	group, err := client.CreateGroupWithType("allGpus", ixdcgm.GroupDefault)
+	if err != nil {
+		t.Fatalf("CreateGroupWithType failed: %v", err)
+	}
+	if gpus := groupGpus(t, client, group); len(gpus) != 3 {
+		t.Fatalf("default group holds GPUs %v, want 3 GPUs", gpus)
+	}
+
+	if err = client.RemoveFromGroup(group, 1); err != nil {
+		t.Fatalf("RemoveFromGroup failed: %v", err)
+	}
+	if err = client.RemoveEntityFromGroup(group, ixdcgm.FE_GPU, 2); err != nil {
+		t.Fatalf("RemoveEntityFromGroup failed: %v", err)
+	}
+	if err = client.RemoveFromGroup(group, 1); !errors.Is(err, ixdcgm.ErrBadParam) {
+		t.Errorf("removing a GPU which is not in the group returned %v, want ErrBadParam", err)
+	}
+	if err = client.AddEntityToGroup(group, ixdcgm.FE_GPU, 2); err != nil {
+		t.Fatalf("AddEntityToGroup failed: %v", err)
+	}
+	gpus := groupGpus(t, client, group)
+	if len(gpus) != 2 || gpus[0] != 0 || gpus[1] != 2 {
+		t.Errorf("group holds GPUs %v, want [0 2]", gpus)
+	}
+}
+
+func TestFindGroupByName(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+
+	// a group created by another client, e.g. ixdcgmi group -c
+	other := ixdcgm.NewFakeClient(fake)
+	defer other.Close()
+	created, err := other.CreateGroup("shared")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %v", err)
+	}
+	if err = other.AddToGroup(created, 1); err != nil {
+		t.Fatalf("AddToGroup failed: %v", err)
+	}
+
+	client := ixdcgm.NewFakeClient(fake)
+	own, err := client.CreateGroup("own")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %v", err)
+	}
+
+	groups, err := client.ListGroups()
+	if err != nil {
+		t.Fatalf("ListGroups failed: %v", err)
+	}
+	names := map[string]ixdcgm.GroupHandle{}
+	for _, grp := range groups {
+		names[grp.GroupName] = grp.Handle
+	}
+	if len(groups) != 2 || names["own"] != own {
+		t.Errorf("ListGroups = %+v, want the groups shared and own", groups)
+	}
+
+	shared, err := client.FindGroupByName("shared")
+	if err != nil {
+		t.Fatalf("FindGroupByName failed: %v", err)
+	}
+	if gpus := groupGpus(t, client, shared); len(gpus) != 1 || gpus[0] != 1 {
+		t.Errorf("found group holds GPUs %v, want [1]", gpus)
+	}
+	if _, err = client.FindGroupByName("missing"); !errors.Is(err, ixdcgm.ErrNotConfigured) {
+		t.Errorf("FindGroupByName of a missing group returned %v, want ErrNotConfigured", err)
+	}
+
+	// the groups of the other client are left as they are
+	if err = client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if n := fake.GroupCount(); n != 1 {
+		t.Errorf("%d groups left after Close, want 1", n)
+	}
+}
@@ -28,6 +28,7 @@ package ixdcgm
 import "C"
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"unsafe"
@@ -46,16 +47,31 @@ type DeviceHealth struct {
 }
 
 // HealthSet enable the DCGM health check system for the given systems
-func HealthSet(groupId GroupHandle, systems HealthSystem) (err error) {
+func HealthSet(groupId GroupHandle, systems HealthSystem) error {
+	return defaultClient.HealthSet(groupId, systems)
+}
+
+// HealthSet enable the DCGM health check system for the given systems
+func (c *Client) HealthSet(groupId GroupHandle, systems HealthSystem) (err error) {
+	return c.withBackend(func(b backend) error {
+		if err := b.healthSet(c.res.groupId(groupId), systems); err != nil {
+			return err
+		}
+		c.res.setHealth(groupId, systems)
+		return nil
+	})
+}
+
+func (b cgoBackend) healthSet(groupId C.dcgmGpuGrp_t, systems HealthSystem) (err error) {
 	params_v2 := C.dcgmHealthSetParams_v2{
 		version:        C.dcgmHealthSetParams_version2,
-		groupId:        groupId.handle,
+		groupId:        groupId,
 		systems:        C.dcgmHealthSystems_t(systems),
 		updateInterval: C.longlong(int64(30 * 1000000)), // How often to query the underlying health information from the driver in usecs.
 		maxKeepAge:     C.double(float64(600)),          // How long to keep data cached for this field in seconds.
 	}
 
-	result := C.dcgmHealthSet_v2(handle.handle, &params_v2)
+	result := C.dcgmHealthSet_v2(b.handle, &params_v2)
 	if err = errorString(result); err != nil {
 		return fmt.Errorf("error setting health watches: %w", err)
 	}
@@ -64,9 +80,22 @@ func HealthSet(groupId GroupHandle, systems HealthSystem) (err error) {
 
 // HealthGet retrieve the current state of the DCGM health check system
 func HealthGet(groupId GroupHandle) (HealthSystem, error) {
+	return defaultClient.HealthGet(groupId)
+}
+
+// HealthGet retrieve the current state of the DCGM health check system
+func (c *Client) HealthGet(groupId GroupHandle) (systems HealthSystem, err error) {
+	err = c.withBackend(func(b backend) (err error) {
+		systems, err = b.healthGet(c.res.groupId(groupId))
+		return err
+	})
+	return systems, err
+}
+
+func (b cgoBackend) healthGet(groupId C.dcgmGpuGrp_t) (HealthSystem, error) {
 	var systems C.dcgmHealthSystems_t
 
-	result := C.dcgmHealthGet(handle.handle, groupId.handle, (*C.dcgmHealthSystems_t)(unsafe.Pointer(&systems)))
+	result := C.dcgmHealthGet(b.handle, groupId, (*C.dcgmHealthSystems_t)(unsafe.Pointer(&systems)))
 	if err := errorString(result); err != nil {
 		return HealthSystem(0), err
 	}
@@ -95,13 +124,41 @@ type HealthResponse struct {
 // about all of the enabled watches within a group is created but no error results are
 // provided. On subsequent calls, any error information will be returned.
 func HealthCheck(groupId GroupHandle) (HealthResponse, error) {
+	return defaultClient.HealthCheck(groupId)
+}
+
+// HealthCheck check the configured watches for any errors/failures/warnings that have occurred
+// since the last time this check was invoked.  On the first call, stateful information
+// about all of the enabled watches within a group is created but no error results are
+// provided. On subsequent calls, any error information will be returned.
+func (c *Client) HealthCheck(groupId GroupHandle) (HealthResponse, error) {
+	return c.HealthCheckCtx(context.Background(), groupId)
+}
+
+// HealthCheckCtx is HealthCheck returning ctx.Err() as soon as ctx is done
+func HealthCheckCtx(ctx context.Context, groupId GroupHandle) (HealthResponse, error) {
+	return defaultClient.HealthCheckCtx(ctx, groupId)
+}
+
+// HealthCheckCtx is HealthCheck returning ctx.Err() as soon as ctx is done
+func (c *Client) HealthCheckCtx(ctx context.Context, groupId GroupHandle) (HealthResponse, error) {
+	return callCtx(ctx, func() (response HealthResponse, err error) {
+		err = c.withBackend(func(b backend) (err error) {
+			response, err = b.healthCheck(c.res.groupId(groupId))
+			return err
+		})
+		return response, err
+	})
+}
+
+func (b cgoBackend) healthCheck(groupId C.dcgmGpuGrp_t) (HealthResponse, error) {
 	var healthResults C.dcgmHealthResponse_v4
 	healthResults.version = makeVersion4(unsafe.Sizeof(healthResults))
 
-	result := C.dcgmHealthCheck(handle.handle, groupId.handle, (*C.dcgmHealthResponse_t)(unsafe.Pointer(&healthResults)))
+	result := C.dcgmHealthCheck(b.handle, groupId, (*C.dcgmHealthResponse_t)(unsafe.Pointer(&healthResults)))
 
 	if err := errorString(result); err != nil {
-		return HealthResponse{}, &DcgmError{msg: C.GoString(C.errorString(result)), Code: result}
+		return HealthResponse{}, fmt.Errorf("Error checking health: %w", err)
 	}
 
 	response := HealthResponse{
@@ -131,24 +188,23 @@ func HealthCheck(groupId GroupHandle) (HealthResponse, error) {
 	return response, nil
 }
 
-func healthCheckByGpuId(gpuId uint) (deviceHealth DeviceHealth, err error) {
+func (c *Client) healthCheckByGpuId(ctx context.Context, gpuId uint) (deviceHealth DeviceHealth, err error) {
 	name := fmt.Sprintf("health%d", rand.Uint64())
-	groupId, err := CreateGroup(name)
+	groupId, err := c.temporaryGroup(ctx, name, []uint{gpuId})
 	if err != nil {
 		return
 	}
+	defer c.destroyTemporaryGroup(groupId)
 
-	err = AddToGroup(groupId, gpuId)
-	if err != nil {
+	if err = ctx.Err(); err != nil {
 		return
 	}
-
-	err = HealthSet(groupId, DCGM_HEALTH_WATCH_ALL)
+	err = c.HealthSet(groupId, DCGM_HEALTH_WATCH_ALL)
 	if err != nil {
 		return
 	}
 
-	result, err := HealthCheck(groupId)
+	result, err := c.HealthCheckCtx(ctx, groupId)
 	if err != nil {
 		return
 	}
@@ -174,7 +230,6 @@ func healthCheckByGpuId(gpuId uint) (deviceHealth DeviceHealth, err error) {
 		Status:  status,
 		Watches: watches,
 	}
-	_ = DestroyGroup(groupId)
 	return
 }
 
@@ -0,0 +1,116 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"reflect"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestHealthCheckByGpuId(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	fake.InjectHealthIncident(ixdcgm.Incident{
+		System:     ixdcgm.DCGM_HEALTH_WATCH_MEM,
+		Health:     ixdcgm.DCGM_HEALTH_RESULT_FAIL,
+		Error:      ixdcgm.DiagErrorDetail{Message: "double-bit ECC error", Code: 1},
+		EntityInfo: ixdcgm.GroupEntityPair{EntityGroupId: ixdcgm.FE_GPU, EntityId: 1},
+	})
+	fake.InjectHealthIncident(ixdcgm.Incident{
+		System:     ixdcgm.DCGM_HEALTH_WATCH_THERMAL,
+		Health:     ixdcgm.DCGM_HEALTH_RESULT_WARN,
+		Error:      ixdcgm.DiagErrorDetail{Message: "clocks throttled"},
+		EntityInfo: ixdcgm.GroupEntityPair{EntityGroupId: ixdcgm.FE_GPU, EntityId: 1},
+	})
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	health, err := client.HealthCheckByGpuId(1)
+	if err != nil {
+		t.Fatalf("HealthCheckByGpuId failed: %v", err)
+	}
+	want := ixdcgm.DeviceHealth{
+		GPU:    1,
+		Status: "Failure",
+		Watches: []ixdcgm.SystemWatch{
+			{Type: "Memory watches", Status: "Failure", Error: "double-bit ECC error"},
+			{Type: "Temperature watches", Status: "Warning", Error: "clocks throttled"},
+		},
+	}
+	if !reflect.DeepEqual(health, want) {
+		t.Errorf("HealthCheckByGpuId = %+v, want %+v", health, want)
+	}
+
+	health, err = client.HealthCheckByGpuId(0)
+	if err != nil {
+		t.Fatalf("HealthCheckByGpuId failed: %v", err)
+	}
+	if health.Status != "Healthy" || len(health.Watches) != 0 {
+		t.Errorf("HealthCheckByGpuId of a healthy GPU = %+v", health)
+	}
+
+	fake.ClearHealthIncidents()
+	health, err = client.HealthCheckByGpuId(1)
+	if err != nil {
+		t.Fatalf("HealthCheckByGpuId failed: %v", err)
+	}
+	if health.Status != "Healthy" {
+		t.Errorf("HealthCheckByGpuId after the incidents are cleared = %+v", health)
+	}
+
+	if n := fake.GroupCount(); n != 0 {
+		t.Errorf("%d groups left after HealthCheckByGpuId", n)
+	}
+}
+
+func TestHealthCheckOnlyReportsWatchedSystems(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(1)
+	fake.InjectHealthIncident(ixdcgm.Incident{
+		System:     ixdcgm.DCGM_HEALTH_WATCH_PCIE,
+		Health:     ixdcgm.DCGM_HEALTH_RESULT_WARN,
+		EntityInfo: ixdcgm.GroupEntityPair{EntityGroupId: ixdcgm.FE_GPU, EntityId: 0},
+	})
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	group, err := client.CreateGroup("health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.DestroyGroup(group)
+	if err = client.AddToGroup(group, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err = client.HealthSet(group, ixdcgm.DCGM_HEALTH_WATCH_MEM); err != nil {
+		t.Fatal(err)
+	}
+
+	systems, err := client.HealthGet(group)
+	if err != nil || systems != ixdcgm.DCGM_HEALTH_WATCH_MEM {
+		t.Errorf("HealthGet = %v, %v, want %v", systems, err, ixdcgm.DCGM_HEALTH_WATCH_MEM)
+	}
+
+	response, err := client.HealthCheck(group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.OverallHealth != ixdcgm.DCGM_HEALTH_RESULT_PASS || len(response.Incidents) != 0 {
+		t.Errorf("HealthCheck reported an incident of an unwatched system: %+v", response)
+	}
+}
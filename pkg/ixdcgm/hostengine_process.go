@@ -0,0 +1,319 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	hostengineBin = "ix-hostengine"
+
+	defaultHostengineStartTimeout = 10 * time.Second
+	defaultHostengineStopTimeout  = 5 * time.Second
+
+	hostengineReadyPollInterval = 50 * time.Millisecond
+	hostengineMinRestartBackoff = 1 * time.Second
+	hostengineMaxRestartBackoff = 1 * time.Minute
+)
+
+var errHostengineStopping = errors.New("ix-hostengine is being stopped")
+
+// hostengineProcess runs ix-hostengine as a child process listening on a unix socket.
+// The child is restarted if it dies unexpectedly until stop is called.
+type hostengineProcess struct {
+	bin          string
+	args         []string
+	socketPath   string
+	startTimeout time.Duration
+	stopTimeout  time.Duration
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	exited   chan struct{} // closed once the current child is reaped
+	stopping bool
+	stopCh   chan struct{} // closed when stop is called
+	done     chan struct{} // closed once the restart loop returns
+}
+
+// findHostengine looks for ix-hostengine in PATH first and then in the IXDCGM bin directory
+func findHostengine() (string, error) {
+	if bin, err := exec.LookPath(hostengineBin); err == nil {
+		return bin, nil
+	}
+
+	uptDirMu.Lock()
+	bin := filepath.Join(ixdcgmBinDir, hostengineBin)
+	uptDirMu.Unlock()
+	if _, err := exec.LookPath(bin); err != nil {
+		return "", fmt.Errorf("%s is neither in PATH nor in %s", hostengineBin, filepath.Dir(bin))
+	}
+	return bin, nil
+}
+
+func newHostengineProcess(socketPath string, cfg Config) (*hostengineProcess, error) {
+	bin, err := findHostengine()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &hostengineProcess{
+		bin:          bin,
+		args:         append([]string{"--no-daemon", "--domain-socket", socketPath}, cfg.HostengineArgs...),
+		socketPath:   socketPath,
+		startTimeout: cfg.StartTimeout,
+		stopTimeout:  cfg.StopTimeout,
+		stopCh:       make(chan struct{}),
+	}
+	if p.startTimeout == 0 {
+		p.startTimeout = defaultHostengineStartTimeout
+	}
+	if p.stopTimeout == 0 {
+		p.stopTimeout = defaultHostengineStopTimeout
+	}
+	return p, nil
+}
+
+// start runs the child and waits until its socket accepts connections
+func (p *hostengineProcess) start() error {
+	exited, err := p.spawn()
+	if err != nil {
+		return err
+	}
+	if err = p.waitReady(exited); err != nil {
+		p.kill()
+		<-exited
+		return err
+	}
+
+	p.done = make(chan struct{})
+	go p.superviseChild()
+	return nil
+}
+
+func (p *hostengineProcess) spawn() (chan struct{}, error) {
+	// a stale socket left by a dead child would prevent the new one from listening
+	_ = os.Remove(p.socketPath)
+
+	uptDirMu.Lock()
+	libDir := ixdcgmLibDir
+	uptDirMu.Unlock()
+
+	cmd := exec.Command(p.bin, p.args...)
+	cmd.Env = append(os.Environ(), "LD_LIBRARY_PATH="+os.Getenv("LD_LIBRARY_PATH")+":"+libDir)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	stdout := &lineLogger{stream: "stdout"}
+	stderr := &lineLogger{stream: "stderr"}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// do not wait forever for the output of grandchildren which inherited the pipes
+	cmd.WaitDelay = time.Second
+
+	// spawning under the lock makes sure stop sees every child it has to terminate
+	p.mu.Lock()
+	if p.stopping {
+		p.mu.Unlock()
+		return nil, errHostengineStopping
+	}
+	if err := cmd.Start(); err != nil {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("Error starting %s: %w", hostengineBin, err)
+	}
+	exited := make(chan struct{})
+	p.cmd = cmd
+	p.exited = exited
+	p.mu.Unlock()
+
+	go func() {
+		err := cmd.Wait()
+		stdout.flush()
+		stderr.flush()
+
+		p.mu.Lock()
+		stopping := p.stopping
+		p.mu.Unlock()
+		if !stopping {
+			getLogger().Error("ix-hostengine exited unexpectedly", "pid", cmd.Process.Pid, "error", err)
+		}
+		close(exited)
+	}()
+	return exited, nil
+}
+
+// waitReady waits until the socket of the child accepts connections
+func (p *hostengineProcess) waitReady(exited <-chan struct{}) error {
+	deadline := time.Now().Add(p.startTimeout)
+	for {
+		conn, err := net.DialTimeout("unix", p.socketPath, hostengineReadyPollInterval)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s did not listen on %s within %v: %w", hostengineBin, p.socketPath, p.startTimeout, err)
+		}
+
+		select {
+		case <-exited:
+			return fmt.Errorf("%s exited before listening on %s", hostengineBin, p.socketPath)
+		case <-time.After(hostengineReadyPollInterval):
+		}
+	}
+}
+
+// superviseChild restarts the child each time it dies unexpectedly
+func (p *hostengineProcess) superviseChild() {
+	defer close(p.done)
+
+	backoff := hostengineMinRestartBackoff
+	for {
+		p.mu.Lock()
+		exited := p.exited
+		p.mu.Unlock()
+		<-exited
+
+		for {
+			p.mu.Lock()
+			stopping := p.stopping
+			p.mu.Unlock()
+			if stopping {
+				return
+			}
+
+			getLogger().Info("Restarting ix-hostengine", "backoff", backoff)
+			select {
+			case <-p.stopCh:
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > hostengineMaxRestartBackoff {
+				backoff = hostengineMaxRestartBackoff
+			}
+
+			exited, err := p.spawn()
+			if errors.Is(err, errHostengineStopping) {
+				return
+			}
+			if err != nil {
+				getLogger().Error("Error restarting ix-hostengine", "error", err)
+				continue
+			}
+			if err = p.waitReady(exited); err != nil {
+				getLogger().Error("Error restarting ix-hostengine", "error", err)
+				p.kill()
+				<-exited
+				continue
+			}
+			backoff = hostengineMinRestartBackoff
+			break
+		}
+	}
+}
+
+// signal sends sig to the process group of the child
+func (p *hostengineProcess) signal(sig syscall.Signal) {
+	p.mu.Lock()
+	cmd := p.cmd
+	p.mu.Unlock()
+	if cmd != nil && cmd.Process != nil {
+		_ = syscall.Kill(-cmd.Process.Pid, sig)
+	}
+}
+
+func (p *hostengineProcess) kill() {
+	p.signal(syscall.SIGKILL)
+}
+
+// stop terminates the child with SIGTERM, kills it with SIGKILL if it is still running
+// after the grace period and reaps it
+func (p *hostengineProcess) stop() error {
+	p.mu.Lock()
+	if p.stopping {
+		p.mu.Unlock()
+		return nil
+	}
+	p.stopping = true
+	close(p.stopCh)
+	exited := p.exited
+	p.mu.Unlock()
+
+	var err error
+	p.signal(syscall.SIGTERM)
+	select {
+	case <-exited:
+	case <-time.After(p.stopTimeout):
+		err = fmt.Errorf("%s did not exit within %v after SIGTERM, killed", hostengineBin, p.stopTimeout)
+		p.kill()
+		<-exited
+	}
+
+	if p.done != nil {
+		<-p.done
+	}
+
+	if rerr := os.Remove(p.socketPath); rerr != nil && !errors.Is(rerr, os.ErrNotExist) && err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// lineLogger logs each line the ix-hostengine child process writes to stream
+type lineLogger struct {
+	mu     sync.Mutex
+	stream string
+	buf    []byte
+}
+
+func (l *lineLogger) Write(b []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buf = append(l.buf, b...)
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			break
+		}
+		l.log(l.buf[:i])
+		l.buf = l.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+func (l *lineLogger) flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.buf) > 0 {
+		l.log(l.buf)
+		l.buf = nil
+	}
+}
+
+func (l *lineLogger) log(line []byte) {
+	getLogger().Info("ix-hostengine output", "stream", l.stream, "line", string(line))
+}
@@ -0,0 +1,240 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// The ix-hostengine stub is a script on PATH running the test binary in stub mode,
+// which listens on the socket after a short delay and behaves according to stubModeEnv.
+const (
+	stubEnv     = "IXDCGM_STUB_HOSTENGINE"
+	stubModeEnv = "IXDCGM_STUB_MODE"
+)
+
+func TestStubHostengine(t *testing.T) {
+	if os.Getenv(stubEnv) != "1" {
+		t.Skip("only run as the ix-hostengine stub")
+	}
+
+	var socketPath string
+	for i, arg := range os.Args {
+		if arg == "--domain-socket" && i+1 < len(os.Args) {
+			socketPath = os.Args[i+1]
+		}
+	}
+	os.Stdout.WriteString("stub started\n")
+	os.Stderr.WriteString("stub warning\n")
+
+	switch os.Getenv(stubModeEnv) {
+	case "ignore-term":
+		signal.Ignore(syscall.SIGTERM)
+	case "exit":
+		os.Exit(1)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		os.Exit(2)
+	}
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			os.Exit(3)
+		}
+		conn.Close()
+	}
+}
+
+// lockedBuffer collects the logs of the package
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// newStubProcess puts the stub on PATH and returns the child process running it in mode
+func newStubProcess(t *testing.T, mode string, cfg Config) (*hostengineProcess, *lockedBuffer) {
+	t.Helper()
+	testBin, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\nexec \"" + testBin + "\" -test.run='^TestStubHostengine$' -- \"$@\"\n"
+	if err = os.WriteFile(filepath.Join(dir, hostengineBin), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(stubEnv, "1")
+	t.Setenv(stubModeEnv, mode)
+
+	logs := &lockedBuffer{}
+	previous := getLogger()
+	SetLogger(slog.New(slog.NewTextHandler(logs, nil)))
+	t.Cleanup(func() { SetLogger(previous) })
+
+	p, err := newHostengineProcess(filepath.Join(dir, hostengineSocketName), cfg)
+	if err != nil {
+		t.Fatalf("newHostengineProcess failed: %v", err)
+	}
+	return p, logs
+}
+
+func (p *hostengineProcess) pid() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cmd.Process.Pid
+}
+
+// reaped tells whether the process is gone, a child which is not reaped yet still exists as a zombie
+func reaped(pid int) bool {
+	return errors.Is(syscall.Kill(pid, 0), syscall.ESRCH)
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestHostengineProcess(t *testing.T) {
+	p, logs := newStubProcess(t, "", Config{})
+	if err := p.start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	// start returns once the socket accepts connections
+	conn, err := net.Dial("unix", p.socketPath)
+	if err != nil {
+		t.Fatalf("socket not ready once started: %v", err)
+	}
+	conn.Close()
+
+	waitFor(t, "the output of the child", func() bool {
+		out := logs.String()
+		return strings.Contains(out, "stream=stdout line=\"stub started\"") &&
+			strings.Contains(out, "stream=stderr line=\"stub warning\"")
+	})
+
+	pid := p.pid()
+	if err = p.stop(); err != nil {
+		t.Errorf("stop of a child exiting on SIGTERM failed: %v", err)
+	}
+	if !reaped(pid) {
+		t.Errorf("child %d not reaped once stopped", pid)
+	}
+	if _, err = os.Stat(p.socketPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("socket left once stopped: %v", err)
+	}
+}
+
+func TestHostengineProcessKill(t *testing.T) {
+	const stopTimeout = 300 * time.Millisecond
+	p, _ := newStubProcess(t, "ignore-term", Config{StopTimeout: stopTimeout})
+	if err := p.start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	pid := p.pid()
+	begin := time.Now()
+	if err := p.stop(); err == nil || !strings.Contains(err.Error(), "killed") {
+		t.Errorf("stop of a child ignoring SIGTERM = %v, want it killed", err)
+	}
+	if elapsed := time.Since(begin); elapsed < stopTimeout {
+		t.Errorf("child killed after %v, before the grace period of %v", elapsed, stopTimeout)
+	}
+	if !reaped(pid) {
+		t.Errorf("child %d not reaped once killed", pid)
+	}
+}
+
+func TestHostengineProcessExitBeforeReady(t *testing.T) {
+	p, _ := newStubProcess(t, "exit", Config{})
+	if err := p.start(); err == nil || !strings.Contains(err.Error(), "exited before listening") {
+		t.Errorf("start of a child exiting before listening = %v", err)
+	}
+}
+
+func TestHostengineProcessRestart(t *testing.T) {
+	p, logs := newStubProcess(t, "", Config{})
+	if err := p.start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	defer p.stop()
+	pid := p.pid()
+	p.kill()
+	waitFor(t, "the child to be restarted", func() bool {
+		if p.pid() == pid {
+			return false
+		}
+		conn, err := net.Dial("unix", p.socketPath)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	})
+	if !reaped(pid) {
+		t.Errorf("dead child %d not reaped", pid)
+	}
+	if !strings.Contains(logs.String(), "ix-hostengine exited unexpectedly") {
+		t.Errorf("unexpected exit not logged:\n%s", logs)
+	}
+}
+
+func TestHostengineArgs(t *testing.T) {
+	for _, arg := range []string{"--no-daemon", "--domain-socket", "--domain-socket=/tmp/other.socket"} {
+		cfg := Config{Mode: StartHostengine, HostengineArgs: []string{"--log-level", "debug", arg}}
+		if err := cfg.validate(); err == nil {
+			t.Errorf("HostengineArgs with %s are valid", arg)
+		}
+	}
+	cfg := Config{Mode: StartHostengine, HostengineArgs: []string{"--log-level", "debug"}}
+	if err := cfg.validate(); err != nil {
+		t.Errorf("HostengineArgs without the flags of the library are invalid: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+const hostengineStatsChanCap = 16
+
+// HostengineCpuUtilization is the CPU utilization of the ix-hostengine process.
+// The values are fractions of the CPU resources of the node, multiply them by 100 to get them in %.
+type HostengineCpuUtilization struct {
+	Total  float64
+	Kernel float64
+	User   float64
+}
+
+// HostengineStats is a sample of the resource usage of the ix-hostengine process
+type HostengineStats struct {
+	Time        time.Time
+	MemoryBytes int64 // memory used by the ix-hostengine process, including swapped memory
+	Cpu         HostengineCpuUtilization
+	// Err is the error which occurred while sampling, the other fields are not valid if it is set
+	Err error
+}
+
+// GetHostengineMemoryUsage returns the memory used by the ix-hostengine of the default client in bytes
+func GetHostengineMemoryUsage() (int64, error) {
+	return defaultClient.GetHostengineMemoryUsage()
+}
+
+// GetHostengineMemoryUsage returns the memory used by the ix-hostengine process in bytes,
+// including its swapped memory
+func (c *Client) GetHostengineMemoryUsage() (int64, error) {
+	h, release, err := c.libHandle()
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	var memory C.dcgmIntrospectMemory_t
+	memory.version = makeVersion1(unsafe.Sizeof(memory))
+
+	// wait for the first metadata to be gathered instead of failing with DCGM_ST_NO_DATA
+	result := C.dcgmIntrospectGetHostengineMemoryUsage(h, &memory, C.int(1))
+	if err := errorString(result); err != nil {
+		return 0, fmt.Errorf("Error getting ix-hostengine memory usage: %w", err)
+	}
+	return int64(memory.bytesUsed), nil
+}
+
+// GetHostengineCpuUtilization returns the CPU utilization of the ix-hostengine of the default client
+func GetHostengineCpuUtilization() (HostengineCpuUtilization, error) {
+	return defaultClient.GetHostengineCpuUtilization()
+}
+
+// GetHostengineCpuUtilization returns the CPU utilization of the ix-hostengine process
+func (c *Client) GetHostengineCpuUtilization() (HostengineCpuUtilization, error) {
+	h, release, err := c.libHandle()
+	if err != nil {
+		return HostengineCpuUtilization{}, err
+	}
+	defer release()
+
+	var cpuUtil C.dcgmIntrospectCpuUtil_t
+	cpuUtil.version = makeVersion1(unsafe.Sizeof(cpuUtil))
+
+	result := C.dcgmIntrospectGetHostengineCpuUtilization(h, &cpuUtil, C.int(1))
+	if err := errorString(result); err != nil {
+		return HostengineCpuUtilization{}, fmt.Errorf("Error getting ix-hostengine CPU utilization: %w", err)
+	}
+	return HostengineCpuUtilization{
+		Total:  float64(cpuUtil.total),
+		Kernel: float64(cpuUtil.kernel),
+		User:   float64(cpuUtil.user),
+	}, nil
+}
+
+// SampleHostengineStats samples the ix-hostengine of the default client, see Client.SampleHostengineStats
+func SampleHostengineStats(ctx context.Context, interval time.Duration) (<-chan HostengineStats, error) {
+	return defaultClient.SampleHostengineStats(ctx, interval)
+}
+
+// SampleHostengineStats samples the memory usage and CPU utilization of the ix-hostengine process
+// every interval and sends them on the returned channel, which is closed when ctx is done.
+// Samples are discarded while the channel is full.
+func (c *Client) SampleHostengineStats(ctx context.Context, interval time.Duration) (<-chan HostengineStats, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid sampling interval %v: must be positive", interval)
+	}
+
+	stats := make(chan HostengineStats, hostengineStatsChanCap)
+	go func() {
+		defer close(stats)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			sample := HostengineStats{Time: time.Now()}
+			sample.MemoryBytes, sample.Err = c.GetHostengineMemoryUsage()
+			if sample.Err == nil {
+				sample.Cpu, sample.Err = c.GetHostengineCpuUtilization()
+			}
+
+			select {
+			case stats <- sample:
+			default:
+				getLogger().Warn("The hostengine stats channel is already full, new samples will be discarded")
+			}
+		}
+	}()
+
+	return stats, nil
+}
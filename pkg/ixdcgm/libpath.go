@@ -0,0 +1,217 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#cgo linux LDFLAGS: -ldl
+#define _GNU_SOURCE
+#include <dlfcn.h>
+#include <stdlib.h>
+
+// libraryPath returns the path of the shared object providing symbol, NULL if it is unknown
+static const char* libraryPath(void* handle, const char* symbol)
+{
+	Dl_info info;
+	void* addr = dlsym(handle, symbol);
+	if (addr == NULL || dladdr(addr, &info) == 0) {
+		return NULL;
+	}
+	return info.dli_fname;
+}
+*/
+import "C"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+const (
+	// ixdcgmLibPathEnv lists, separated by colons, the directories or library files
+	// searched for libixdcgm.so before any other location
+	ixdcgmLibPathEnv = "IXDCGM_LIB_PATH"
+
+	// ixdcgmLibSoname is the versioned soname, which is the only name installed in some containers
+	ixdcgmLibSoname = ixdcgmLib + ".4"
+
+	defaultIxDcgmLibDir = "/usr/local/ixdcgm/lib64"
+)
+
+// ixdcgmLibDirSet tells whether the library directory was set by SetIxDcgmLibDir
+var ixdcgmLibDirSet bool
+
+// LibraryInfo describes the loaded IXDCGM library
+type LibraryInfo struct {
+	// Path is the path of the library as loaded
+	Path string
+	// ResolvedPath is Path with all the symbolic links resolved, e.g. /usr/local/ixdcgm/lib64/libixdcgm.so.4.2.0
+	ResolvedPath string
+	// Version is the version in the file name of ResolvedPath, e.g. "4.2.0", empty if the name has none
+	Version string
+}
+
+var loadedLib *LibraryInfo
+
+// LibraryLoadAttempt is a path libixdcgm.so was looked for at and the reason it could not be loaded
+type LibraryLoadAttempt struct {
+	Path string
+	Err  string
+}
+
+// LibraryLoadError lists every path libixdcgm.so was looked for at, in the search order
+type LibraryLoadError struct {
+	Attempts []LibraryLoadAttempt
+}
+
+func (e *LibraryLoadError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to load %s, tried:", ixdcgmLib)
+	for _, a := range e.Attempts {
+		fmt.Fprintf(&b, "\n  %s: %s", a.Path, a.Err)
+	}
+	return b.String()
+}
+
+// Is makes the error match ErrLibraryNotFound
+func (e *LibraryLoadError) Is(target error) bool {
+	return target == ErrLibraryNotFound
+}
+
+// GetLoadedLibrary describes the IXDCGM library which is loaded
+func GetLoadedLibrary() (LibraryInfo, error) {
+	libMu.Lock()
+	defer libMu.Unlock()
+	if loadedLib == nil {
+		return LibraryInfo{}, errLibNotLoaded()
+	}
+	return *loadedLib, nil
+}
+
+func errLibNotLoaded() error {
+	return fmt.Errorf("%s is not loaded: %w", ixdcgmLib, ErrUninitialized)
+}
+
+// ixdcgmLibCandidates returns the paths to load libixdcgm.so from, in order:
+// the entries of IXDCGM_LIB_PATH, the directory set by SetIxDcgmLibDir,
+// the library and its versioned soname in the system library path, and the default directory.
+func ixdcgmLibCandidates() []string {
+	var candidates []string
+	for _, entry := range filepath.SplitList(os.Getenv(ixdcgmLibPathEnv)) {
+		if entry == "" {
+			continue
+		}
+		if fi, err := os.Stat(entry); err == nil && !fi.IsDir() {
+			candidates = append(candidates, entry)
+			continue
+		}
+		candidates = append(candidates, libCandidatesInDir(entry)...)
+	}
+
+	uptDirMu.Lock()
+	libDir, libDirSet := ixdcgmLibDir, ixdcgmLibDirSet
+	uptDirMu.Unlock()
+	if libDirSet {
+		candidates = append(candidates, libCandidatesInDir(libDir)...)
+	}
+
+	// names without a slash are looked up by the dynamic loader
+	candidates = append(candidates, ixdcgmLib, ixdcgmLibSoname)
+
+	if !libDirSet || libDir != defaultIxDcgmLibDir {
+		candidates = append(candidates, libCandidatesInDir(defaultIxDcgmLibDir)...)
+	}
+	return candidates
+}
+
+// libCandidatesInDir returns libixdcgm.so in dir followed by its versioned sonames, highest version first
+func libCandidatesInDir(dir string) []string {
+	candidates := []string{filepath.Join(dir, ixdcgmLib)}
+	versioned, _ := filepath.Glob(filepath.Join(dir, ixdcgmLib+".*"))
+	sort.SliceStable(versioned, func(i, j int) bool {
+		return compareLibVersions(libVersion(versioned[i]), libVersion(versioned[j])) > 0
+	})
+	return append(candidates, versioned...)
+}
+
+// libVersion returns the version in the file name of the library, e.g. "4.10" for libixdcgm.so.4.10
+func libVersion(path string) string {
+	_, version, _ := strings.Cut(filepath.Base(path), ".so.")
+	return version
+}
+
+// compareLibVersions compares the dotted versions component by component, numerically when both
+// components are numbers, so that 4.10 is above 4.9. A version is below the longer ones it prefixes.
+func compareLibVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.ParseUint(as[i], 10, 64)
+		bn, bErr := strconv.ParseUint(bs[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil && an != bn:
+			if an < bn {
+				return -1
+			}
+			return 1
+		case (aErr != nil || bErr != nil) && as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// openIxDcgmLib dlopens the first loadable candidate
+func openIxDcgmLib() (unsafe.Pointer, error) {
+	loadErr := &LibraryLoadError{}
+	for _, path := range ixdcgmLibCandidates() {
+		cPath := string2Char(path)
+		handle := C.dlopen(cPath, C.RTLD_LAZY|C.RTLD_GLOBAL)
+		freeCString(cPath)
+		if handle != nil {
+			loadedLib = describeLib(handle, path)
+			getLogger().Debug("Loaded IXDCGM library", "path", loadedLib.ResolvedPath, "version", loadedLib.Version)
+			return handle, nil
+		}
+
+		// dlerror starts with the path already reported by the attempt
+		errMsg := strings.TrimPrefix(C.GoString(C.dlerror()), path+": ")
+		getLogger().Debug("Failed to load IXDCGM library", "path", path, "error", errMsg)
+		loadErr.Attempts = append(loadErr.Attempts, LibraryLoadAttempt{Path: path, Err: errMsg})
+	}
+	return nil, loadErr
+}
+
+func describeLib(handle unsafe.Pointer, path string) *LibraryInfo {
+	info := &LibraryInfo{Path: path, ResolvedPath: path}
+
+	symbol := string2Char("dcgmInit")
+	defer freeCString(symbol)
+	if loaded := C.libraryPath(handle, symbol); loaded != nil {
+		info.Path = C.GoString(loaded)
+		info.ResolvedPath = info.Path
+	}
+	if resolved, err := filepath.EvalSymlinks(info.Path); err == nil {
+		info.ResolvedPath = resolved
+	}
+
+	info.Version = libVersion(info.ResolvedPath)
+	return info
+}
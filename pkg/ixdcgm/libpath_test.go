@@ -0,0 +1,170 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCompareLibVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"4.10", "4.9", 1},
+		{"4.9", "4.10", -1},
+		{"4.2.0", "4.2.0", 0},
+		{"4.2.0", "4", 1},
+		{"4", "4.2.0", -1},
+		{"10", "9.9.9", 1},
+		{"4.2.rc1", "4.2.rc2", -1},
+	}
+	for _, tt := range tests {
+		got := compareLibVersions(tt.a, tt.b)
+		if (got > 0) != (tt.want > 0) || (got < 0) != (tt.want < 0) {
+			t.Errorf("compareLibVersions(%q, %q) = %d, want the sign of %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// createLibs creates empty files of the given names in a temporary directory
+func createLibs(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLibCandidatesInDir(t *testing.T) {
+	dir := createLibs(t, "libixdcgm.so.4", "libixdcgm.so.4.9", "libixdcgm.so.4.10", "libixdcgm.so.4.2.0", "libother.so.5")
+	var got []string
+	for _, path := range libCandidatesInDir(dir) {
+		got = append(got, filepath.Base(path))
+	}
+	want := []string{"libixdcgm.so", "libixdcgm.so.4.10", "libixdcgm.so.4.9", "libixdcgm.so.4.2.0", "libixdcgm.so.4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("libCandidatesInDir = %v, want %v", got, want)
+	}
+}
+
+// withLibDir sets the directory of SetIxDcgmLibDir for the duration of the test, "" unsets it
+func withLibDir(t *testing.T, dir string) {
+	t.Helper()
+	uptDirMu.Lock()
+	oldDir, oldSet := ixdcgmLibDir, ixdcgmLibDirSet
+	ixdcgmLibDir, ixdcgmLibDirSet = dir, dir != ""
+	if dir == "" {
+		ixdcgmLibDir = defaultIxDcgmLibDir
+	}
+	uptDirMu.Unlock()
+	t.Cleanup(func() {
+		uptDirMu.Lock()
+		ixdcgmLibDir, ixdcgmLibDirSet = oldDir, oldSet
+		uptDirMu.Unlock()
+	})
+}
+
+func TestIxdcgmLibCandidates(t *testing.T) {
+	envDir := createLibs(t, "libixdcgm.so.4.1")
+	envFile := filepath.Join(createLibs(t, "custom.so"), "custom.so")
+	libDir := createLibs(t)
+	defaults := append([]string{ixdcgmLib, ixdcgmLibSoname}, libCandidatesInDir(defaultIxDcgmLibDir)...)
+
+	tests := []struct {
+		name   string
+		env    string
+		libDir string
+		want   []string
+	}{
+		{name: "defaults", want: defaults},
+		{
+			name: "env dir",
+			env:  envDir,
+			want: append([]string{filepath.Join(envDir, ixdcgmLib), filepath.Join(envDir, "libixdcgm.so.4.1")}, defaults...),
+		},
+		{
+			name: "env file and dir",
+			env:  envFile + string(os.PathListSeparator) + string(os.PathListSeparator) + envDir,
+			want: append([]string{envFile, filepath.Join(envDir, ixdcgmLib), filepath.Join(envDir, "libixdcgm.so.4.1")}, defaults...),
+		},
+		{
+			name:   "env before lib dir",
+			env:    envFile,
+			libDir: libDir,
+			want:   append([]string{envFile, filepath.Join(libDir, ixdcgmLib)}, defaults...),
+		},
+		{
+			name:   "lib dir is the default dir",
+			libDir: defaultIxDcgmLibDir,
+			want:   append(libCandidatesInDir(defaultIxDcgmLibDir), ixdcgmLib, ixdcgmLibSoname),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ixdcgmLibPathEnv, tt.env)
+			withLibDir(t, tt.libDir)
+			if got := ixdcgmLibCandidates(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ixdcgmLibCandidates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenIxDcgmLibAttempts(t *testing.T) {
+	// the files are not shared objects, so that none of them can be loaded
+	dir := createLibs(t, ixdcgmLib, "libixdcgm.so.4.1")
+	t.Setenv(ixdcgmLibPathEnv, dir)
+	withLibDir(t, "")
+
+	libMu.Lock()
+	defer libMu.Unlock()
+	if loadedLib != nil {
+		t.Skip("IXDCGM library already loaded")
+	}
+	_, err := openIxDcgmLib()
+	if err == nil {
+		// an installed library is found after the files of the temporary directory
+		loadedLib = nil
+		t.Skip("IXDCGM library installed")
+	}
+	if !errors.Is(err, ErrLibraryNotFound) {
+		t.Errorf("openIxDcgmLib returned %v, want ErrLibraryNotFound", err)
+	}
+	var loadErr *LibraryLoadError
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("openIxDcgmLib returned %T, want *LibraryLoadError", err)
+	}
+	var paths []string
+	for _, a := range loadErr.Attempts {
+		paths = append(paths, a.Path)
+		if a.Err == "" {
+			t.Errorf("attempt of %s has no error", a.Path)
+		}
+	}
+	if want := ixdcgmLibCandidates(); !reflect.DeepEqual(paths, want) {
+		t.Errorf("attempts %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+)
+
+// LogSeverity is the logging severity of the ix-hostengine
+type LogSeverity int
+
+const (
+	LogNone  LogSeverity = C.DcgmLoggingSeverityNone    // No logging
+	LogFatal LogSeverity = C.DcgmLoggingSeverityFatal   // Fatal errors
+	LogError LogSeverity = C.DcgmLoggingSeverityError   // Errors
+	LogWarn  LogSeverity = C.DcgmLoggingSeverityWarning // Warnings
+	LogInfo  LogSeverity = C.DcgmLoggingSeverityInfo    // Informative, will generate medium logs
+	LogDebug LogSeverity = C.DcgmLoggingSeverityDebug   // Debug infomation, will generate large logs
+	LogVerb  LogSeverity = C.DcgmLoggingSeverityVerbose // Verbose debugging information, will generate more large logs
+)
+
+func (s LogSeverity) String() string {
+	switch s {
+	case LogNone:
+		return "LogNone"
+	case LogFatal:
+		return "LogFatal"
+	case LogError:
+		return "LogError"
+	case LogWarn:
+		return "LogWarn"
+	case LogInfo:
+		return "LogInfo"
+	case LogDebug:
+		return "LogDebug"
+	case LogVerb:
+		return "LogVerb"
+	}
+	return "unknown"
+}
+
+func (s LogSeverity) valid() bool {
+	return s >= LogNone && s <= LogVerb
+}
+
+// ParseLogSeverity parses a log severity given on the command line, case insensitively.
+// Both the names used by ix-hostengine, e.g. "NONE", "ERROR", "WARNING" or "VERB",
+// and the names of the constants, e.g. "LogNone" or "LogWarn", are accepted.
+func ParseLogSeverity(s string) (LogSeverity, error) {
+	switch strings.ToUpper(strings.TrimPrefix(strings.TrimPrefix(s, "Log"), "log")) {
+	case "NONE":
+		return LogNone, nil
+	case "FATAL":
+		return LogFatal, nil
+	case "ERROR":
+		return LogError, nil
+	case "WARN", "WARNING":
+		return LogWarn, nil
+	case "INFO":
+		return LogInfo, nil
+	case "DEBUG":
+		return LogDebug, nil
+	case "VERB", "VERBOSE":
+		return LogVerb, nil
+	}
+	return LogNone, fmt.Errorf("invalid log level: %s, supported log levels: "+
+		"NONE, FATAL, ERROR, WARN, INFO, DEBUG, VERB", s)
+}
+
+// LogTarget is a logger of the ix-hostengine
+type LogTarget int
+
+const (
+	LogTargetBase   LogTarget = 0 // The main log of the ix-hostengine
+	LogTargetSyslog LogTarget = 1 // The messages the ix-hostengine sends to syslog
+)
+
+func (t LogTarget) String() string {
+	switch t {
+	case LogTargetBase:
+		return "BASE"
+	case LogTargetSyslog:
+		return "SYSLOG"
+	}
+	return "unknown"
+}
+
+// ParseLogTarget parses a logger name given on the command line, "BASE" or "SYSLOG", case insensitively
+func ParseLogTarget(s string) (LogTarget, error) {
+	switch strings.ToUpper(s) {
+	case "BASE":
+		return LogTargetBase, nil
+	case "SYSLOG":
+		return LogTargetSyslog, nil
+	}
+	return LogTargetBase, fmt.Errorf("invalid logger: %s, supported loggers: BASE, SYSLOG", s)
+}
+
+// SetHostengineLogSeverity sets the severity of a logger of the ix-hostengine of the default client
+func SetHostengineLogSeverity(target LogTarget, severity LogSeverity) error {
+	return defaultClient.SetHostengineLogSeverity(target, severity)
+}
+
+// SetHostengineLogSeverity sets the severity of a logger of the ix-hostengine at runtime,
+// e.g. to raise its verbosity while debugging without restarting it
+func (c *Client) SetHostengineLogSeverity(target LogTarget, severity LogSeverity) error {
+	if target != LogTargetBase && target != LogTargetSyslog {
+		return fmt.Errorf("invalid logger: %d", target)
+	}
+	if !severity.valid() {
+		return fmt.Errorf("invalid log level: %d", severity)
+	}
+	h, release, err := c.libHandle()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	logging := C.dcgmSettingsSetLoggingSeverity_t{
+		targetLogger:   C.int(target),
+		targetSeverity: C.DcgmLoggingSeverity_t(severity),
+	}
+	result := C.dcgmHostengineSetLoggingSeverity(h, &logging)
+	if err := errorString(result); err != nil {
+		return fmt.Errorf("Error setting %s log severity of ix-hostengine to %s: %w", target, severity, err)
+	}
+	return nil
+}
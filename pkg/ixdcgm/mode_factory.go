@@ -43,7 +43,17 @@ type Interface interface {
 	Start(args ...string) (DcgmHandle, error)
 }
 
+// mode is implemented by the modes of this package, which are started with a validated Config
+type mode interface {
+	Interface
+	start(cfg Config) (DcgmHandle, error)
+}
+
 func New(m int) (Interface, error) {
+	return newMode(m)
+}
+
+func newMode(m int) (mode, error) {
 	switch m {
 	case Embedded:
 		return &embedded{}, nil
@@ -56,6 +66,18 @@ func New(m int) (Interface, error) {
 	}
 }
 
-var _ Interface = (*embedded)(nil)
-var _ Interface = (*standalone)(nil)
-var _ Interface = (*startHostengine)(nil)
+var _ mode = (*embedded)(nil)
+var _ mode = (*standalone)(nil)
+var _ mode = (*startHostengine)(nil)
+
+// startWithArgs starts mode m with the positional args of Init
+func startWithArgs(m mode, id int, args ...string) (DcgmHandle, error) {
+	cfg, err := configFromArgs(id, args...)
+	if err != nil {
+		return DcgmHandle{}, err
+	}
+	if err = cfg.validate(); err != nil {
+		return DcgmHandle{}, fmt.Errorf("invalid ixdcgm config: %w", err)
+	}
+	return m.start(cfg)
+}
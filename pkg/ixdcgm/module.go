@@ -0,0 +1,161 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// ModuleId identifies a module of the ix-hostengine
+type ModuleId uint
+
+const (
+	ModuleCore       ModuleId = C.DcgmModuleIdCore       // Core module, always loaded
+	ModuleNvSwitch   ModuleId = C.DcgmModuleIdNvSwitch   // Switch module
+	ModuleVGPU       ModuleId = C.DcgmModuleIdVGPU       // vGPU module
+	ModuleIntrospect ModuleId = C.DcgmModuleIdIntrospect // Introspection module
+	ModuleHealth     ModuleId = C.DcgmModuleIdHealth     // Health module
+	ModulePolicy     ModuleId = C.DcgmModuleIdPolicy     // Policy module
+	ModuleConfig     ModuleId = C.DcgmModuleIdConfig     // Config module
+	ModuleDiag       ModuleId = C.DcgmModuleIdDiag       // GPU diagnostic module
+	ModuleProfiling  ModuleId = C.DcgmModuleIdProfiling  // Profiling module
+	ModuleSysmon     ModuleId = C.DcgmModuleIdSysmon     // System monitoring module
+
+	moduleCount ModuleId = C.DcgmModuleIdCount
+)
+
+// ModuleStatus is the load status of a module. Modules are loaded lazily, so they are
+// ModuleStatusNotLoaded until they are used.
+type ModuleStatus uint
+
+const (
+	ModuleStatusNotLoaded  ModuleStatus = C.DcgmModuleStatusNotLoaded  // Module has not been loaded yet
+	ModuleStatusDenylisted ModuleStatus = C.DcgmModuleStatusDenylisted // Module is on the deny list and cannot be loaded
+	ModuleStatusFailed     ModuleStatus = C.DcgmModuleStatusFailed     // Loading the module failed
+	ModuleStatusLoaded     ModuleStatus = C.DcgmModuleStatusLoaded     // Module has been loaded
+	ModuleStatusUnloaded   ModuleStatus = C.DcgmModuleStatusUnloaded   // Module has been unloaded during shutdown
+	ModuleStatusPaused     ModuleStatus = C.DcgmModuleStatusPaused     // Module is loaded but paused
+)
+
+func (s ModuleStatus) String() string {
+	switch s {
+	case ModuleStatusNotLoaded:
+		return "NotLoaded"
+	case ModuleStatusDenylisted:
+		return "Denylisted"
+	case ModuleStatusFailed:
+		return "Failed"
+	case ModuleStatusLoaded:
+		return "Loaded"
+	case ModuleStatusUnloaded:
+		return "Unloaded"
+	case ModuleStatusPaused:
+		return "Paused"
+	}
+	return "Unknown"
+}
+
+// ModuleInfo describes a module of the ix-hostengine and its load status
+type ModuleInfo struct {
+	Id     ModuleId
+	Name   string
+	Status ModuleStatus
+}
+
+// ModuleIdToName returns the name of the module, IXDCGM must be initialized
+func ModuleIdToName(id ModuleId) (string, error) {
+	libMu.Lock()
+	defer libMu.Unlock()
+	if loadedLib == nil {
+		return "", fmt.Errorf("Error getting name of module %d: %w", id, errLibNotLoaded())
+	}
+
+	var name *C.char
+	result := C.dcgmModuleIdToName(C.dcgmModuleId_t(id), &name)
+	if err := errorString(result); err != nil {
+		return "", fmt.Errorf("Error getting name of module %d: %w", id, err)
+	}
+	return C.GoString(name), nil
+}
+
+// DenylistModule adds the module to the deny list of the ix-hostengine of the default client
+func DenylistModule(id ModuleId) error {
+	return defaultClient.DenylistModule(id)
+}
+
+// DenylistModule adds the module to the deny list of the ix-hostengine, so that it is never loaded.
+// A module which is already loaded cannot be deny listed.
+func (c *Client) DenylistModule(id ModuleId) error {
+	if id == ModuleCore {
+		return fmt.Errorf("core module cannot be added to the deny list")
+	}
+	h, release, err := c.libHandle()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	result := C.dcgmModuleDenylist(h, C.dcgmModuleId_t(id))
+	if err := errorString(result); err != nil {
+		return fmt.Errorf("Error adding module %d to the deny list: %w", id, err)
+	}
+	return nil
+}
+
+// GetModuleStatuses returns the modules of the ix-hostengine of the default client and their load status
+func GetModuleStatuses() ([]ModuleInfo, error) {
+	return defaultClient.GetModuleStatuses()
+}
+
+// GetModuleStatuses returns the modules of the ix-hostengine and their load status
+func (c *Client) GetModuleStatuses() ([]ModuleInfo, error) {
+	h, release, err := c.libHandle()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var statuses C.dcgmModuleGetStatuses_t
+	statuses.version = makeVersion1(unsafe.Sizeof(statuses))
+
+	result := C.dcgmModuleGetStatuses(h, &statuses)
+	if err := errorString(result); err != nil {
+		return nil, fmt.Errorf("Error getting module statuses: %w", err)
+	}
+
+	modules := make([]ModuleInfo, 0, statuses.numStatuses)
+	for i := 0; i < int(statuses.numStatuses) && i < len(statuses.statuses); i++ {
+		id := ModuleId(statuses.statuses[i].id)
+		name, err := ModuleIdToName(id)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, ModuleInfo{
+			Id:     id,
+			Name:   name,
+			Status: ModuleStatus(statuses.statuses[i].status),
+		})
+	}
+	return modules, nil
+}
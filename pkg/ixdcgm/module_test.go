@@ -0,0 +1,31 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"errors"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestModuleIdToNameWithoutLibrary(t *testing.T) {
+	if _, err := ixdcgm.ModuleIdToName(ixdcgm.ModuleHealth); !errors.Is(err, ixdcgm.ErrUninitialized) {
+		t.Errorf("ModuleIdToName without the library returned %v, want ErrUninitialized", err)
+	}
+}
@@ -23,7 +23,7 @@ package ixdcgm
 #include "include/dcgm_structs.h"
 
 // wrapper for go callback function
-extern int violationPolicyNotify(void* p);
+extern fpRecvUpdates violationPolicyNotifier(int slot);
 extern int voidPolicyCallback(void* p);
 */
 import "C"
@@ -31,7 +31,6 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
-	"log"
 	"math/rand"
 	"sync"
 	"time"
@@ -106,9 +105,11 @@ const (
 	xidPolicyIndex
 )
 
+// policyConditionParam is the parameter of a policy condition, set in C.dcgmPolicy_t.parms[Index]
 type policyConditionParam struct {
-	typ   uint32
-	value uint32
+	Index policyIndex `json:"index"`
+	Type  uint32      `json:"type"`
+	Value uint32      `json:"value"`
 }
 
 // DbePolicyCondition contains details about a Double-bit ECC error
@@ -163,86 +164,145 @@ type XidPolicyCondition struct {
 	GpuId uint
 }
 
+// policyListener receives the violations of its conditions on its GPUs
+type policyListener struct {
+	conditions C.dcgmPolicyCondition_t
+	gpuIds     map[uint]bool
+	violations chan PolicyViolation
+}
+
+// policyListenerSlots is the number of violation callbacks in callback.c, i.e. the number of
+// policy listeners all the clients can run at the same time
+const policyListenerSlots = 128
+
 var (
-	policyChanOnce sync.Once
-	policyMapOnce  sync.Once
+	// policyListeners are the running listeners of all the clients by slot. Every registration is
+	// given the callback of its own slot, so that a violation reported for it reaches only its listener.
+	policyListenersMu sync.RWMutex
+	policyListeners   [policyListenerSlots]*policyListener
+)
 
-	// callbacks maps PolicyViolation channels with policy
-	// captures C callback() value for each violation condition
-	callbacks   map[string]chan PolicyViolation
-	conChanLcks map[string]*sync.Mutex
+// addPolicyListener gives the listener a free slot
+func addPolicyListener(l *policyListener) (int, error) {
+	policyListenersMu.Lock()
+	defer policyListenersMu.Unlock()
+	for slot, listener := range policyListeners {
+		if listener == nil {
+			policyListeners[slot] = l
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("too many policy listeners, at most %d can run at the same time", policyListenerSlots)
+}
 
-	// paramMap maps C.dcgmPolicy_t.parms index and limits
-	// to be used in setPolicy() for setting user selected policies
-	paramMap map[policyIndex]policyConditionParam
+// removePolicyListener stops the delivery to the listener of the slot, its channel can be closed once it returns
+func removePolicyListener(slot int) {
+	policyListenersMu.Lock()
+	defer policyListenersMu.Unlock()
+	policyListeners[slot] = nil
+}
 
-	registerCh = make(chan struct{})
-)
+// deliverPolicyViolation sends the violation to the listener of the slot if it listens for its condition and GPU,
+// the violation is dropped if the listener is not read fast enough
+func deliverPolicyViolation(slot int, violation PolicyViolation) {
+	bit := policyConditionBit(violation.Condition)
+	gpuId, ok := policyViolationGpu(violation)
+	if bit == 0 || !ok || slot < 0 || slot >= policyListenerSlots {
+		return
+	}
 
-func makePolicyChannels(gpuCnt int) {
-	policyChanCap := PolicyChanCapMultiplier * (gpuCnt + 1)
-	policyChanOnce.Do(func() {
-		callbacks = make(map[string]chan PolicyViolation)
-		callbacks["dbe"] = make(chan PolicyViolation, policyChanCap)
-		callbacks["pcie"] = make(chan PolicyViolation, policyChanCap)
-		callbacks["maxrtpg"] = make(chan PolicyViolation, policyChanCap)
-		callbacks["thermal"] = make(chan PolicyViolation, policyChanCap)
-		callbacks["power"] = make(chan PolicyViolation, policyChanCap)
-		callbacks["xid"] = make(chan PolicyViolation, policyChanCap)
-
-		conChanLcks = make(map[string]*sync.Mutex)
-		conChanLcks["dbe"] = &sync.Mutex{}
-		conChanLcks["pcie"] = &sync.Mutex{}
-		conChanLcks["maxrtpg"] = &sync.Mutex{}
-		conChanLcks["thermal"] = &sync.Mutex{}
-		conChanLcks["power"] = &sync.Mutex{}
-		conChanLcks["xid"] = &sync.Mutex{}
-	})
+	policyListenersMu.RLock()
+	defer policyListenersMu.RUnlock()
+	l := policyListeners[slot]
+	if l == nil || l.conditions&bit == 0 || !l.gpuIds[gpuId] {
+		return
+	}
+	if len(l.violations) == cap(l.violations)-1 {
+		getLogger().Warn("The violation channel is almost full, please read it as soon as possible",
+			"capacity", cap(l.violations))
+	}
+	select {
+	case l.violations <- violation:
+	default:
+		getLogger().Error("The violation channel is already full, new messages will be discarded",
+			"capacity", cap(l.violations), "condition", string(violation.Condition))
+	}
 }
 
-func makePolicyParamsMap(params *PolicyConditionParams) {
+func policyConditionBit(con policyCondition) C.dcgmPolicyCondition_t {
+	switch con {
+	case DbePolicy:
+		return C.DCGM_POLICY_COND_DBE
+	case PCIePolicy:
+		return C.DCGM_POLICY_COND_PCI
+	case MaxRtPgPolicy:
+		return C.DCGM_POLICY_COND_MAX_PAGES_RETIRED
+	case ThermalPolicy:
+		return C.DCGM_POLICY_COND_THERMAL
+	case PowerPolicy:
+		return C.DCGM_POLICY_COND_POWER
+	case XidPolicy:
+		return C.DCGM_POLICY_COND_XID
+	}
+	return 0
+}
+
+func policyViolationGpu(violation PolicyViolation) (uint, bool) {
+	switch data := violation.Data.(type) {
+	case DbePolicyCondition:
+		return data.GpuId, true
+	case PciPolicyCondition:
+		return data.GpuId, true
+	case RetiredPagesPolicyCondition:
+		return data.GpuId, true
+	case ThermalPolicyCondition:
+		return data.GpuId, true
+	case PowerPolicyCondition:
+		return data.GpuId, true
+	case XidPolicyCondition:
+		return data.GpuId, true
+	}
+	return 0, false
+}
+
+// makePolicyParams returns the conditions enabled by params and their parameters
+func makePolicyParams(params *PolicyConditionParams) (C.dcgmPolicyCondition_t, []policyConditionParam) {
 	const (
 		policyFieldTypeBool = 0
 		policyFieldTypeLong = 1
 		policyBoolValue     = 1
 	)
 
-	policyMapOnce.Do(func() {
-		paramMap = make(map[policyIndex]policyConditionParam)
-
-		paramMap[dbePolicyIndex] = policyConditionParam{
-			typ:   policyFieldTypeBool,
-			value: policyBoolValue,
-		}
-
-		paramMap[pciePolicyIndex] = policyConditionParam{
-			typ:   policyFieldTypeBool,
-			value: policyBoolValue,
-		}
-
-		paramMap[maxRtPgPolicyIndex] = policyConditionParam{
-			typ:   policyFieldTypeLong,
-			value: params.MaxRtPgPolicyThreshold,
-		}
-
-		paramMap[thermalPolicyIndex] = policyConditionParam{
-			typ:   policyFieldTypeLong,
-			value: params.ThermalPolicyThreshold,
-		}
-
-		paramMap[powerPolicyIndex] = policyConditionParam{
-			typ:   policyFieldTypeLong,
-			value: params.PowerPolicyThreshold,
-		}
-
-		paramMap[xidPolicyIndex] = policyConditionParam{
-			typ:   policyFieldTypeBool,
-			value: policyBoolValue,
-		}
-	})
+	var condition C.dcgmPolicyCondition_t
+	var paramList []policyConditionParam
+	if params.DbePolicyEnabled {
+		condition |= C.DCGM_POLICY_COND_DBE
+		paramList = append(paramList, policyConditionParam{Index: dbePolicyIndex, Type: policyFieldTypeBool, Value: policyBoolValue})
+	}
+	if params.PCIePolicyEnabled {
+		condition |= C.DCGM_POLICY_COND_PCI
+		paramList = append(paramList, policyConditionParam{Index: pciePolicyIndex, Type: policyFieldTypeBool, Value: policyBoolValue})
+	}
+	if params.MaxRtPgPolicyEnabled {
+		condition |= C.DCGM_POLICY_COND_MAX_PAGES_RETIRED
+		paramList = append(paramList, policyConditionParam{Index: maxRtPgPolicyIndex, Type: policyFieldTypeLong, Value: params.MaxRtPgPolicyThreshold})
+	}
+	if params.ThermalPolicyEnabled {
+		condition |= C.DCGM_POLICY_COND_THERMAL
+		paramList = append(paramList, policyConditionParam{Index: thermalPolicyIndex, Type: policyFieldTypeLong, Value: params.ThermalPolicyThreshold})
+	}
+	if params.PowerPolicyEnabled {
+		condition |= C.DCGM_POLICY_COND_POWER
+		paramList = append(paramList, policyConditionParam{Index: powerPolicyIndex, Type: policyFieldTypeLong, Value: params.PowerPolicyThreshold})
+	}
+	if params.XidPolicyEnabled {
+		condition |= C.DCGM_POLICY_COND_XID
+		paramList = append(paramList, policyConditionParam{Index: xidPolicyIndex, Type: policyFieldTypeBool, Value: policyBoolValue})
+	}
+	return condition, paramList
 }
 
-func setPolicy(groupId GroupHandle, condition C.dcgmPolicyCondition_t, paramList []policyIndex) (err error) {
+func (b cgoBackend) policySet(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t, paramList []policyConditionParam) (err error) {
 	var policy C.dcgmPolicy_t
 	policy.version = makeVersion1(unsafe.Sizeof(policy))
 	policy.mode = C.dcgmPolicyMode_t(C.DCGM_OPERATION_MODE_AUTO)
@@ -251,31 +311,30 @@ func setPolicy(groupId GroupHandle, condition C.dcgmPolicyCondition_t, paramList
 	policy.validation = C.DCGM_POLICY_VALID_NONE
 	policy.condition = condition
 
-	// iterate on paramMap for given policy conditions
-	for _, key := range paramList {
-		conditionParam, exists := paramMap[policyIndex(key)]
-		if !exists {
+	for _, conditionParam := range paramList {
+		key := conditionParam.Index
+		if key < 0 || int(key) >= len(policy.parms) {
 			return fmt.Errorf("Error: Invalid Policy condition, %v does not exist", key)
 		}
 		// set policy condition parameters
 		// set condition type (bool or longlong)
-		policy.parms[key].tag = conditionParam.typ
+		policy.parms[key].tag = conditionParam.Type
 
 		// set condition val (violation threshold)
 		// policy.parms.val is a C union type
 		// cgo docs: Go doesn't have support for C's union type
 		// C union types are represented as a Go byte array
-		binary.LittleEndian.PutUint32(policy.parms[key].val[:], conditionParam.value)
+		binary.LittleEndian.PutUint32(policy.parms[key].val[:], conditionParam.Value)
 	}
 
 	var statusHandle C.dcgmStatus_t
 
-	result := C.dcgmPolicySet(handle.handle, groupId.handle, &policy, statusHandle)
+	result := C.dcgmPolicySet(b.handle, groupId, &policy, statusHandle)
 	if err = errorString(result); err != nil {
-		return fmt.Errorf("Error setting policies: %s", err)
+		return fmt.Errorf("Error setting policies: %w", err)
 	}
 
-	log.Println("Policy successfully set.")
+	getLogger().Info("Policy successfully set", "groupId", uintptr(groupId), "condition", uint(condition))
 
 	return
 }
@@ -290,34 +349,41 @@ func validatePolicy(p *PolicyConditionParams) error {
 	return nil
 }
 
-func registerPolicyForGpus(ctx context.Context, params *PolicyConditionParams, gpuIds ...uint) (<-chan PolicyViolation, error) {
-	groupId, err := CreateGroup(fmt.Sprintf("PolicyGroup_%d", rand.Uint64()))
+func (c *Client) registerPolicyForGpus(ctx context.Context, params *PolicyConditionParams, gpuIds ...uint) (<-chan PolicyViolation, error) {
+	groupId, err := c.temporaryGroup(ctx, fmt.Sprintf("PolicyGroup_%d", rand.Uint64()), gpuIds)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create policy group, err: %v", err)
+		return nil, fmt.Errorf("failed to create policy group, err: %w", err)
 	}
+	return c.registerPolicyOnOwnGroup(ctx, groupId, params)
+}
 
-	go func() {
-		<-ctx.Done()
-		select {
-		case <-registerCh: // Wait the policy is unregistered
-			_ = DestroyGroup(groupId)
-		case <-time.After(500 * time.Millisecond):
-			_ = DestroyGroup(groupId)
-		}
-	}()
-
-	for _, gpuId := range gpuIds {
-		err = AddToGroup(groupId, gpuId)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add gpu %d to policy group, err: %v", gpuId, err)
-		}
+func (c *Client) registerPolicyForAllGpus(ctx context.Context, params *PolicyConditionParams) (<-chan PolicyViolation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+	groupId, err := c.createGroup(fmt.Sprintf("PolicyGroup_%d", rand.Uint64()), GroupDefault, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create policy group, err: %w", err)
+	}
+	return c.registerPolicyOnOwnGroup(ctx, groupId, params)
+}
 
-	return registerPolicy(ctx, groupId, params)
+// registerPolicyOnOwnGroup registers the policy on a temporary group created for it alone: the ix-hostengine
+// keeps a single policy per group and unregisters all the callbacks of a condition at once, so that
+// the registrations sharing a group would replace and unregister each other's policy.
+func (c *Client) registerPolicyOnOwnGroup(ctx context.Context, groupId GroupHandle, params *PolicyConditionParams) (<-chan PolicyViolation, error) {
+	// the group is destroyed once the policy is unregistered
+	violation, err := c.registerPolicy(ctx, groupId, params, func() { c.destroyTemporaryGroup(groupId) })
+	if err != nil {
+		c.destroyTemporaryGroup(groupId)
+		return nil, err
+	}
+	return violation, nil
 }
 
-// registerPolicy sets GPU usage and error policies and notifies in case of any violations on GPUs within a specific group
-func registerPolicy(ctx context.Context, groupId GroupHandle, params *PolicyConditionParams) (<-chan PolicyViolation, error) {
+// registerPolicy sets GPU usage and error policies and notifies in case of any violations on GPUs within a specific group,
+// which must not be shared with any other registration. unregistered, if not nil, is called once the policy is unregistered.
+func (c *Client) registerPolicy(ctx context.Context, groupId GroupHandle, params *PolicyConditionParams, unregistered func()) (<-chan PolicyViolation, error) {
 	var err error
 	if params == nil {
 		return nil, fmt.Errorf("PolicyConditionParams is required")
@@ -326,113 +392,98 @@ func registerPolicy(ctx context.Context, groupId GroupHandle, params *PolicyCond
 		return nil, err
 	}
 
-	grpInfo, err := GetGroupInfo(groupId)
+	grpInfo, err := c.GetGroupInfo(groupId)
 	if err != nil {
-		return nil, fmt.Errorf("Error getting group info for group %v: %v", groupId, err)
+		return nil, fmt.Errorf("Error getting group info for group %v: %w", groupId, err)
 	}
 	gpuCnt := len(grpInfo.EntityList)
-
-	// init policy globals for internal API
-	makePolicyChannels(gpuCnt)
-	makePolicyParamsMap(params)
-
-	// make a list of policy conditions for setting their parameters
-	var paramKeys []policyIndex
-	// get all conditions to be set in setPolicy()
-	var condition C.dcgmPolicyCondition_t = 0
-	// get length of enabled condition types
-	var conTypes int = 0
-	if params.DbePolicyEnabled {
-		conTypes++
-		paramKeys = append(paramKeys, dbePolicyIndex)
-		condition |= C.DCGM_POLICY_COND_DBE
-	}
-	if params.PCIePolicyEnabled {
-		conTypes++
-		paramKeys = append(paramKeys, pciePolicyIndex)
-		condition |= C.DCGM_POLICY_COND_PCI
-	}
-	if params.MaxRtPgPolicyEnabled {
-		conTypes++
-		paramKeys = append(paramKeys, maxRtPgPolicyIndex)
-		condition |= C.DCGM_POLICY_COND_MAX_PAGES_RETIRED
-	}
-	if params.ThermalPolicyEnabled {
-		conTypes++
-		paramKeys = append(paramKeys, thermalPolicyIndex)
-		condition |= C.DCGM_POLICY_COND_THERMAL
+	gpuIds := make(map[uint]bool, gpuCnt)
+	for _, entity := range grpInfo.EntityList {
+		if entity.EntityGroupId == FE_GPU {
+			gpuIds[entity.EntityId] = true
+		}
 	}
-	if params.PowerPolicyEnabled {
-		conTypes++
-		paramKeys = append(paramKeys, powerPolicyIndex)
-		condition |= C.DCGM_POLICY_COND_POWER
+
+	condition, paramList := makePolicyParams(params)
+	listener := &policyListener{
+		conditions: condition,
+		gpuIds:     gpuIds,
+		violations: make(chan PolicyViolation, PolicyChanCapMultiplier*len(paramList)*(gpuCnt+1)),
 	}
-	if params.XidPolicyEnabled {
-		conTypes++
-		paramKeys = append(paramKeys, xidPolicyIndex)
-		condition |= C.DCGM_POLICY_COND_XID
+	slot, err := addPolicyListener(listener)
+	if err != nil {
+		return nil, err
 	}
 
-	if err = setPolicy(groupId, condition, paramKeys); err != nil {
+	err = c.withBackend(func(b backend) error {
+		grpId := c.res.groupId(groupId)
+		if err := b.policySet(grpId, condition, paramList); err != nil {
+			return err
+		}
+
+		getLogger().Debug("Listening for violations", "groupId", groupId.GetHandle(), "condition", uint(condition))
+		if err := b.policyRegister(grpId, condition, slot); err != nil {
+			return err
+		}
+		c.res.addPolicy(trackedPolicy{group: groupId, condition: condition, paramList: paramList, slot: slot})
+		return nil
+	})
+	if err != nil {
+		removePolicyListener(slot)
 		return nil, err
 	}
 
-	log.Println("Listening for violations...")
-	result := C.dcgmPolicyRegister(handle.handle, groupId.handle,
+	go func() {
+		<-ctx.Done()
+		getLogger().Debug("Unregistering policy violation", "groupId", groupId.GetHandle(), "condition", uint(condition))
+		// the slot is freed once the hostengine no longer reports violations for it
+		registered := c.unregisterPolicy(groupId, condition, slot)
+		removePolicyListener(slot)
+		close(listener.violations)
+		if registered && unregistered != nil {
+			unregistered()
+		}
+	}()
+
+	return listener.violations, nil
+}
+
+func (b cgoBackend) policyRegister(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t, slot int) error {
+	result := C.dcgmPolicyRegister(b.handle, groupId,
 		C.dcgmPolicyCondition_t(condition),
-		C.fpRecvUpdates(C.violationPolicyNotify),
+		C.violationPolicyNotifier(C.int(slot)),
 		C.fpRecvUpdates(C.voidPolicyCallback),
 	)
-	if err = errorString(result); err != nil {
-		return nil, &DcgmError{msg: C.GoString(C.errorString(result)), Code: result}
+	if err := errorString(result); err != nil {
+		return fmt.Errorf("Error registering policy: %w", err)
 	}
+	return nil
+}
 
-	vioChanCap := conTypes * (gpuCnt + 1)
-	violation := make(chan PolicyViolation, vioChanCap)
-
-	go func() {
-		defer func() {
-			log.Println("unregister policy violation...")
-			unregisterPolicy(groupId, condition)
-			close(violation)
-			close(registerCh)
-		}()
-		for {
-			if len(violation) == vioChanCap {
-				log.Println("Error: The violation channel is already full. New messages will be discarded.")
-				continue
-			} else if len(violation) == vioChanCap-1 {
-				log.Println("Warning: The violation channel is almost full. Please read it as soon as possible.")
-			}
-
-			select {
-			case dbe := <-callbacks["dbe"]:
-				violation <- dbe
-			case pcie := <-callbacks["pcie"]:
-				violation <- pcie
-			case maxrtpg := <-callbacks["maxrtpg"]:
-				violation <- maxrtpg
-			case thermal := <-callbacks["thermal"]:
-				violation <- thermal
-			case power := <-callbacks["power"]:
-				violation <- power
-			case xid := <-callbacks["xid"]:
-				violation <- xid
-			case <-ctx.Done():
-				return
-			}
+// unregisterPolicy unregisters the policy, it returns false if the policy was already dropped
+// with its group or by closing the client
+func (c *Client) unregisterPolicy(groupId GroupHandle, condition C.dcgmPolicyCondition_t, slot int) bool {
+	// the policies are forgotten when the client is closed, in which case f is not called
+	var registered bool
+	err := c.withBackend(func(b backend) error {
+		if registered = c.res.removePolicy(slot); !registered {
+			return nil
 		}
-	}()
-
-	return violation, nil
+		return b.policyUnregister(c.res.groupId(groupId), condition)
+	})
+	if registered && err != nil {
+		getLogger().Error("Error unregistering policy", "groupId", groupId.GetHandle(), "condition", uint(condition),
+			"returnCode", int(ReturnCodeOf(err)), "error", err)
+	}
+	return registered
 }
 
-func unregisterPolicy(groupId GroupHandle, condition C.dcgmPolicyCondition_t) {
-	result := C.dcgmPolicyUnregister(handle.handle, groupId.handle, condition)
-
+func (b cgoBackend) policyUnregister(groupId C.dcgmGpuGrp_t, condition C.dcgmPolicyCondition_t) error {
+	result := C.dcgmPolicyUnregister(b.handle, groupId, condition)
 	if err := errorString(result); err != nil {
-		log.Println(fmt.Errorf("error unregistering policy: %s", err))
+		return fmt.Errorf("Error unregistering policy: %w", err)
 	}
+	return nil
 }
 
 func createTimeStamp(t C.longlong) time.Time {
@@ -464,10 +515,11 @@ func VoidPolicyCallback(data unsafe.Pointer) int {
 	return 0
 }
 
-// ViolationPolicyRegistration is a go callback function for dcgmPolicyRegister() wrapped in C.violationPolicyNotify()
+// ViolationPolicyRegistration is a go callback function for dcgmPolicyRegister() wrapped in the
+// C.violationPolicyNotifier() of the slot of the registration
 //
 //export ViolationPolicyRegistration
-func ViolationPolicyRegistration(data unsafe.Pointer) int {
+func ViolationPolicyRegistration(data unsafe.Pointer, slot C.int) int {
 	// log.Println("A policy violation is coming ...")
 	var con policyCondition
 	var timestamp time.Time
@@ -528,38 +580,10 @@ func ViolationPolicyRegistration(data unsafe.Pointer) int {
 		}
 	}
 
-	err := PolicyViolation{
+	deliverPolicyViolation(int(slot), PolicyViolation{
 		Condition: con,
 		Timestamp: timestamp,
 		Data:      val,
-	}
-
-	switch con {
-	case DbePolicy:
-		writeToCallbacks("dbe", err)
-	case PCIePolicy:
-		writeToCallbacks("pcie", err)
-	case MaxRtPgPolicy:
-		writeToCallbacks("maxrtpg", err)
-	case ThermalPolicy:
-		writeToCallbacks("thermal", err)
-	case PowerPolicy:
-		writeToCallbacks("power", err)
-	case XidPolicy:
-		writeToCallbacks("xid", err)
-	}
+	})
 	return 0
 }
-
-func writeToCallbacks(con string, vioErr PolicyViolation) {
-	conChanLcks[con].Lock()
-	defer conChanLcks[con].Unlock()
-
-	if len(callbacks[con]) == cap(callbacks[con]) {
-		log.Printf("Error: The channel of %s condition is already full. New messages will be discarded.\n", con)
-		return
-	} else if len(callbacks[con]) == cap(callbacks[con])-1 {
-		log.Printf("Warning: The channel of %s condition is almost full. Please read it as soon as possible.\n", con)
-	}
-	callbacks[con] <- vioErr
-}
@@ -0,0 +1,64 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestPolicyParamsOfEachRegistration(t *testing.T) {
+	fake := NewFakeBackend(2)
+	client := NewFakeClient(fake)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	thresholds := []uint32{90, 70, 80}
+	for i, threshold := range thresholds {
+		params := &PolicyConditionParams{ThermalPolicyEnabled: true, ThermalPolicyThreshold: threshold}
+		var err error
+		if i == 0 {
+			_, err = client.ListenForPolicyViolationsForGPUs(ctx, params, 0)
+		} else {
+			// the listeners of all the GPUs share no group either
+			_, err = client.ListenForPolicyViolationsForAllGPUs(ctx, params)
+		}
+		if err != nil {
+			t.Fatalf("listening with threshold %d failed: %v", threshold, err)
+		}
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.policies) != len(thresholds) {
+		t.Fatalf("%d policies registered, want %d", len(fake.policies), len(thresholds))
+	}
+	slots := make(map[int]bool)
+	for i, p := range fake.policies {
+		want := []policyConditionParam{{Index: thermalPolicyIndex, Type: 1, Value: thresholds[i]}}
+		if got := fake.policySets[p.groupId]; !reflect.DeepEqual(got, want) {
+			t.Errorf("policy %d set with %+v, want %+v", i, got, want)
+		}
+		slots[p.slot] = true
+	}
+	if len(slots) != len(thresholds) {
+		t.Errorf("policies registered with the slots %v", slots)
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func receiveViolation(t *testing.T, violations <-chan ixdcgm.PolicyViolation) ixdcgm.PolicyViolation {
+	t.Helper()
+	select {
+	case v, ok := <-violations:
+		if !ok {
+			t.Fatal("violation channel closed")
+		}
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("no violation received")
+	}
+	return ixdcgm.PolicyViolation{}
+}
+
+func waitClosed(t *testing.T, violations <-chan ixdcgm.PolicyViolation) {
+	t.Helper()
+	for {
+		select {
+		case _, ok := <-violations:
+			if !ok {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("violation channel not closed once the context is done")
+		}
+	}
+}
+
+func TestListenForPolicyViolationsForGPUs(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	params := &ixdcgm.PolicyConditionParams{XidPolicyEnabled: true, ThermalPolicyEnabled: true}
+	violations, err := client.ListenForPolicyViolationsForGPUs(ctx, params, 1)
+	if err != nil {
+		t.Fatalf("ListenForPolicyViolationsForGPUs failed: %v", err)
+	}
+
+	xid := ixdcgm.PolicyViolation{
+		Condition: ixdcgm.XidPolicy,
+		Timestamp: time.Unix(1700000000, 0),
+		Data:      ixdcgm.XidPolicyCondition{ErrNum: 43, GpuId: 1},
+	}
+	if !fake.InjectPolicyViolation(xid) {
+		t.Fatal("XID violation of a listened GPU not delivered")
+	}
+	if got := receiveViolation(t, violations); got != xid {
+		t.Errorf("received %+v, want %+v", got, xid)
+	}
+
+	if fake.InjectPolicyViolation(ixdcgm.PolicyViolation{
+		Condition: ixdcgm.XidPolicy,
+		Data:      ixdcgm.XidPolicyCondition{ErrNum: 43, GpuId: 0},
+	}) {
+		t.Error("violation of a GPU which is not listened delivered")
+	}
+	if fake.InjectPolicyViolation(ixdcgm.PolicyViolation{
+		Condition: ixdcgm.PowerPolicy,
+		Data:      ixdcgm.PowerPolicyCondition{PowerViolation: 300, GpuId: 1},
+	}) {
+		t.Error("violation of a condition which is not enabled delivered")
+	}
+
+	cancel()
+	waitClosed(t, violations)
+	if n := fake.GroupCount(); n != 0 {
+		t.Errorf("%d groups left once the listener is done", n)
+	}
+	if fake.InjectPolicyViolation(xid) {
+		t.Error("violation delivered once the listener is done")
+	}
+}
+
+func TestListenForPolicyViolationsForAllGPUs(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(4)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	violations, err := client.ListenForPolicyViolationsForAllGPUs(ctx, &ixdcgm.PolicyConditionParams{DbePolicyEnabled: true})
+	if err != nil {
+		t.Fatalf("ListenForPolicyViolationsForAllGPUs failed: %v", err)
+	}
+
+	for gpuId := uint(0); gpuId < 4; gpuId++ {
+		dbe := ixdcgm.PolicyViolation{
+			Condition: ixdcgm.DbePolicy,
+			Data:      ixdcgm.DbePolicyCondition{Location: "Device", NumErrors: 1, GpuId: gpuId},
+		}
+		if !fake.InjectPolicyViolation(dbe) {
+			t.Fatalf("DBE violation of gpu %d not delivered", gpuId)
+		}
+		if got := receiveViolation(t, violations); got != dbe {
+			t.Errorf("received %+v, want %+v", got, dbe)
+		}
+	}
+
+	cancel()
+	waitClosed(t, violations)
+}
+
+func TestListenForPolicyViolationsWithoutPolicy(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(1)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	_, err := client.ListenForPolicyViolationsForGPUs(context.Background(), &ixdcgm.PolicyConditionParams{}, 0)
+	if err == nil {
+		t.Fatal("listening without any policy enabled succeeded")
+	}
+	if n := fake.GroupCount(); n != 0 {
+		t.Errorf("%d groups left after the listener failed", n)
+	}
+}
+
+func expectNoViolation(t *testing.T, violations <-chan ixdcgm.PolicyViolation, listener string) {
+	t.Helper()
+	select {
+	case v := <-violations:
+		t.Errorf("%s received %+v", listener, v)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestPolicyViolationsOfOtherRegistrations(t *testing.T) {
+	fakeA := ixdcgm.NewFakeBackend(2)
+	clientA := ixdcgm.NewFakeClient(fakeA)
+	defer clientA.Close()
+	fakeB := ixdcgm.NewFakeBackend(2)
+	clientB := ixdcgm.NewFakeClient(fakeB)
+	defer clientB.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	params := &ixdcgm.PolicyConditionParams{XidPolicyEnabled: true}
+	first, err := clientA.ListenForPolicyViolationsForGPUs(ctx, params, 1)
+	if err != nil {
+		t.Fatalf("ListenForPolicyViolationsForGPUs failed: %v", err)
+	}
+	second, err := clientA.ListenForPolicyViolationsForAllGPUs(ctx, params)
+	if err != nil {
+		t.Fatalf("ListenForPolicyViolationsForAllGPUs failed: %v", err)
+	}
+	other, err := clientB.ListenForPolicyViolationsForGPUs(ctx, params, 1)
+	if err != nil {
+		t.Fatalf("ListenForPolicyViolationsForGPUs of another client failed: %v", err)
+	}
+
+	// both registrations of client A cover gpu 1, each of them receives the violation once
+	xid := ixdcgm.PolicyViolation{
+		Condition: ixdcgm.XidPolicy,
+		Timestamp: time.Unix(1700000000, 0),
+		Data:      ixdcgm.XidPolicyCondition{ErrNum: 43, GpuId: 1},
+	}
+	if !fakeA.InjectPolicyViolation(xid) {
+		t.Fatal("XID violation of a listened GPU not delivered")
+	}
+	for name, violations := range map[string]<-chan ixdcgm.PolicyViolation{"first": first, "second": second} {
+		if got := receiveViolation(t, violations); got != xid {
+			t.Errorf("%s listener received %+v, want %+v", name, got, xid)
+		}
+		expectNoViolation(t, violations, name+" listener")
+	}
+	expectNoViolation(t, other, "listener of another client")
+
+	if !fakeB.InjectPolicyViolation(xid) {
+		t.Fatal("XID violation of another client not delivered")
+	}
+	if got := receiveViolation(t, other); got != xid {
+		t.Errorf("listener of another client received %+v, want %+v", got, xid)
+	}
+	expectNoViolation(t, first, "first listener")
+	expectNoViolation(t, second, "second listener")
+
+	cancel()
+	waitClosed(t, first)
+	waitClosed(t, second)
+	waitClosed(t, other)
+}
+
+func TestPolicyListenersOfSameGroupAndCondition(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	defer cancelFirst()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first, err := client.ListenForPolicyViolationsForAllGPUs(firstCtx,
+		&ixdcgm.PolicyConditionParams{ThermalPolicyEnabled: true, ThermalPolicyThreshold: 90})
+	if err != nil {
+		t.Fatalf("ListenForPolicyViolationsForAllGPUs failed: %v", err)
+	}
+	second, err := client.ListenForPolicyViolationsForAllGPUs(ctx,
+		&ixdcgm.PolicyConditionParams{ThermalPolicyEnabled: true, ThermalPolicyThreshold: 70})
+	if err != nil {
+		t.Fatalf("ListenForPolicyViolationsForAllGPUs failed: %v", err)
+	}
+
+	// closing the first listener keeps the registration of the second one
+	cancelFirst()
+	waitClosed(t, first)
+
+	thermal := ixdcgm.PolicyViolation{
+		Condition: ixdcgm.ThermalPolicy,
+		Timestamp: time.Unix(1700000000, 0),
+		Data:      ixdcgm.ThermalPolicyCondition{ThermalViolation: 75, GpuId: 0},
+	}
+	if !fake.InjectPolicyViolation(thermal) {
+		t.Fatal("thermal violation not delivered once the other listener is closed")
+	}
+	if got := receiveViolation(t, second); got != thermal {
+		t.Errorf("received %+v, want %+v", got, thermal)
+	}
+
+	cancel()
+	waitClosed(t, second)
+	if n := client.GetResourceCounts().TemporaryGroups; n != 0 {
+		t.Errorf("%d policy groups left once the listeners are closed", n)
+	}
+}
@@ -25,6 +25,7 @@ package ixdcgm
 import "C"
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -36,39 +37,59 @@ type DeviceProcessInfo struct {
 	UsedGpuMemory uint64 // MiB
 }
 
-func getDeviceRunningProcesses(gpuId uint) ([]DeviceProcessInfo, error) {
-	cnt, pids, usedMemoryBytes, err := ixdcgmGetDeviceRunningProcesses(gpuId)
+// processMemory is the GPU memory used by a process
+type processMemory struct {
+	Pid             uint64
+	UsedMemoryBytes uint64
+}
+
+func (c *Client) getDeviceRunningProcesses(ctx context.Context, gpuId uint) ([]DeviceProcessInfo, error) {
+	b := c.backend()
+	processes, err := callCtx(ctx, func() ([]processMemory, error) {
+		return b.getDeviceRunningProcesses(gpuId)
+	})
 	if err != nil {
 		return nil, err
 	}
-	InfoCount := int(uint32(cnt))
-	infos := make([]DeviceProcessInfo, InfoCount)
-	for i := 0; i < InfoCount; i++ {
-		infos[i].Pid = uint64(pids[i])
-		infos[i].Name = getPidName(uint64(pids[i]))
-		infos[i].UsedGpuMemory = uint64(usedMemoryBytes[i]) / 1024 / 1024
+	infos := make([]DeviceProcessInfo, len(processes))
+	for i, p := range processes {
+		infos[i].Pid = p.Pid
+		infos[i].Name = getPidName(p.Pid)
+		infos[i].UsedGpuMemory = p.UsedMemoryBytes / 1024 / 1024
 	}
 	return infos, nil
 }
 
-func ixdcgmGetDeviceRunningProcesses(gpuId uint) (cnt C.uint32_t, pids []C.uint64_t, usedMemoryBytes []C.uint64_t, err error) {
+func (b cgoBackend) getDeviceRunningProcesses(gpuId uint) ([]processMemory, error) {
+	cnt, pids, usedMemoryBytes, err := b.ixdcgmGetDeviceRunningProcesses(gpuId)
+	if err != nil {
+		return nil, err
+	}
+	processes := make([]processMemory, int(uint32(cnt)))
+	for i := range processes {
+		processes[i] = processMemory{Pid: uint64(pids[i]), UsedMemoryBytes: uint64(usedMemoryBytes[i])}
+	}
+	return processes, nil
+}
+
+func (b cgoBackend) ixdcgmGetDeviceRunningProcesses(gpuId uint) (cnt C.uint32_t, pids []C.uint64_t, usedMemoryBytes []C.uint64_t, err error) {
 	cnt = 1
 	for i := 0; i < 2; i++ {
 		pids = make([]C.uint64_t, cnt)
 		usedMemoryBytes = make([]C.uint64_t, cnt)
-		ret := C.ixdcgmGetDeviceRunningProcesses(C.ulong(handle.handle), C.uint(gpuId), &cnt, &pids[0], &usedMemoryBytes[0])
+		ret := C.ixdcgmGetDeviceRunningProcesses(C.ulong(b.handle), C.uint(gpuId), &cnt, &pids[0], &usedMemoryBytes[0])
 		if ret == C.IXDCGM_RET_OK {
 			err = nil
 			return
 		} else if ret == C.IXDCGM_RET_INSUFFICIENT_SIZE {
-			fmt.Printf("INSUFFICIENT_SIZE Warnnig: the needed buffer size is %d\n", uint32(cnt))
+			getLogger().Debug("Insufficient buffer size for running processes, retrying", "gpuId", gpuId, "neededSize", uint32(cnt))
 			continue
 		} else {
-			err = ixdcgmErrorString(ret)
+			err = fmt.Errorf("Error getting running processes of gpu %d: %w", gpuId, ixdcgmErrorString(ret))
 			return
 		}
 	}
-	err = fmt.Errorf("failed to call ixdcgm api with the needed buffer size %d", uint32(cnt))
+	err = fmt.Errorf("failed to call ixdcgm api with the needed buffer size %d: %w", uint32(cnt), ErrInsufficientSize)
 	return
 }
 
@@ -0,0 +1,190 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"testing"
+)
+
+// reconnectTo replaces the backend of the client as a reconnect to a restarted hostengine does
+func reconnectTo(t *testing.T, c *Client, f *FakeBackend) {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.be = f
+	if err := c.res.restore(c.backendLocked()); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+}
+
+func TestReconnectForeignGroupWithOldId(t *testing.T) {
+	client := NewFakeClient(NewFakeBackend(2))
+	defer client.Close()
+
+	own, err := client.CreateGroup("own")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %v", err)
+	}
+	if err = client.AddToGroup(own, 0); err != nil {
+		t.Fatalf("AddToGroup failed: %v", err)
+	}
+	oldId := client.res.groupId(own)
+	if got := own.GetHandle(); got != uintptr(oldId) {
+		t.Errorf("GetHandle = %d, want the id %d of the group on the ix-hostengine", got, oldId)
+	}
+
+	// the restarted hostengine gives the old id of the group to a group created by someone else
+	restarted := NewFakeBackend(2)
+	foreignId, err := restarted.groupCreate(GroupEmpty, "foreign")
+	if err != nil {
+		t.Fatalf("groupCreate failed: %v", err)
+	}
+	if foreignId != oldId {
+		t.Fatalf("foreign group created with id %d, want the old id %d", foreignId, oldId)
+	}
+	if err = restarted.groupAddDevice(foreignId, 1); err != nil {
+		t.Fatalf("groupAddDevice failed: %v", err)
+	}
+	reconnectTo(t, client, restarted)
+
+	groups, err := client.ListGroups()
+	if err != nil {
+		t.Fatalf("ListGroups failed: %v", err)
+	}
+	var foreign GroupHandle
+	names := make(map[string]int)
+	for _, g := range groups {
+		names[g.GroupName]++
+		wantGpu := map[string]uint{"own": 0, "foreign": 1}[g.GroupName]
+		if len(g.EntityList) != 1 || g.EntityList[0].EntityId != wantGpu {
+			t.Errorf("group %s holds %+v, want gpu %d", g.GroupName, g.EntityList, wantGpu)
+		}
+		if g.GroupName == "foreign" {
+			foreign = g.Handle
+		}
+	}
+	if len(groups) != 2 || names["own"] != 1 || names["foreign"] != 1 || foreign == own {
+		t.Fatalf("groups %+v listed, want own and foreign", groups)
+	}
+	if got, want := own.GetHandle(), uintptr(client.res.groupId(own)); got != want || got == uintptr(oldId) {
+		t.Errorf("GetHandle of the restored group = %d, want its new id %d", got, want)
+	}
+	if got := foreign.GetHandle(); got != uintptr(foreignId) {
+		t.Errorf("GetHandle of the foreign group = %d, want %d", got, foreignId)
+	}
+
+	if err = client.DestroyGroup(foreign); err != nil {
+		t.Fatalf("DestroyGroup of the foreign group failed: %v", err)
+	}
+	info, err := client.GetGroupInfo(own)
+	if err != nil {
+		t.Fatalf("GetGroupInfo of the own group failed once the foreign group is destroyed: %v", err)
+	}
+	if info.GroupName != "own" {
+		t.Errorf("own group is %s", info.GroupName)
+	}
+	if _, exists := restarted.groups[foreignId]; exists {
+		t.Error("foreign group not destroyed")
+	}
+}
+
+func TestReconnectFieldGroupHandles(t *testing.T) {
+	client := NewFakeClient(NewFakeBackend(1))
+	defer client.Close()
+
+	fg, err := client.FieldGroupCreate("fields", []Short{DCGM_FI_DEV_GPU_TEMP})
+	if err != nil {
+		t.Fatalf("FieldGroupCreate failed: %v", err)
+	}
+	if id := client.res.fieldGroupId(fg); fg.handle != id {
+		t.Errorf("field group handle holds the id %d, want its id %d on the ix-hostengine", fg.handle, id)
+	}
+
+	restarted := NewFakeBackend(1)
+	if _, err = restarted.fieldGroupCreate("foreign", []Short{DCGM_FI_DEV_POWER_USAGE}); err != nil {
+		t.Fatalf("fieldGroupCreate failed: %v", err)
+	}
+	reconnectTo(t, client, restarted)
+
+	restarted.mu.Lock()
+	defer restarted.mu.Unlock()
+	fields := restarted.fieldGroups[client.res.fieldGroupId(fg)]
+	if len(fields) != 1 || fields[0] != DCGM_FI_DEV_GPU_TEMP {
+		t.Errorf("field group handle resolves to the fields %v", fields)
+	}
+}
+
+func TestReconnectFailedRestore(t *testing.T) {
+	fake := NewFakeBackend(1)
+	client := NewFakeClient(fake)
+	defer client.Close()
+
+	group, err := client.CreateGroup("own")
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %v", err)
+	}
+	if err = client.AddToGroup(group, 0); err != nil {
+		t.Fatalf("AddToGroup failed: %v", err)
+	}
+	fg, err := client.FieldGroupCreate("fields", []Short{DCGM_FI_DEV_GPU_TEMP})
+	if err != nil {
+		t.Fatalf("FieldGroupCreate failed: %v", err)
+	}
+	if err = client.WatchFieldsWithGroup(fg, group); err != nil {
+		t.Fatalf("WatchFieldsWithGroup failed: %v", err)
+	}
+
+	// the fields are also watched on a group created by someone else, which is gone once the hostengine restarted
+	foreignId, err := fake.groupCreate(GroupDefault, "foreign")
+	if err != nil {
+		t.Fatalf("groupCreate failed: %v", err)
+	}
+	foreign := GroupHandle{handle: foreignId}
+	if err = client.WatchFieldsWithGroup(fg, foreign); err != nil {
+		t.Fatalf("WatchFieldsWithGroup of the foreign group failed: %v", err)
+	}
+	oldGroupId, oldFieldGroupId := client.res.groupId(group), client.res.fieldGroupId(fg)
+
+	restarted := NewFakeBackend(1)
+	client.mu.Lock()
+	client.be = restarted
+	err = client.res.restore(client.backendLocked())
+	client.mu.Unlock()
+	if err == nil {
+		t.Fatal("restore succeeded although the foreign group is gone")
+	}
+	if groups, fieldGroups := restarted.GroupCount(), restarted.FieldGroupCount(); groups != 0 || fieldGroups != 0 {
+		t.Errorf("failed restore left %d groups and %d field groups", groups, fieldGroups)
+	}
+	if client.res.groupId(group) != oldGroupId || client.res.fieldGroupId(fg) != oldFieldGroupId {
+		t.Error("failed restore replaced the ids of the groups")
+	}
+
+	// once the foreign watch is dropped, the next attempt restores everything once
+	client.res.removeWatch(foreign, fg)
+	reconnectTo(t, client, restarted)
+	if groups, fieldGroups := restarted.GroupCount(), restarted.FieldGroupCount(); groups != 1 || fieldGroups != 1 {
+		t.Errorf("restore created %d groups and %d field groups, want 1 each", groups, fieldGroups)
+	}
+	restarted.mu.Lock()
+	watched := restarted.watched[fakeFieldKey{0, DCGM_FI_DEV_GPU_TEMP}]
+	restarted.mu.Unlock()
+	if !watched {
+		t.Error("watch not restored")
+	}
+}
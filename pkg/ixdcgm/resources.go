@@ -0,0 +1,453 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ResourceCounts is the number of groups and field groups a client holds on the ix-hostengine,
+// which limits the number of groups and field groups of all its clients to 64 each
+type ResourceCounts struct {
+	Groups      int
+	FieldGroups int
+
+	// TemporaryGroups and TemporaryFieldGroups are the groups and field groups the library created for
+	// the calls in progress, e.g. GetDeviceStatus, or for the running policy listeners.
+	// They are destroyed by the calls, on success as on error, and should not grow over time.
+	TemporaryGroups      int
+	TemporaryFieldGroups int
+}
+
+// GetResourceCounts returns the number of groups and field groups held by the default client
+func GetResourceCounts() ResourceCounts {
+	return defaultClient.GetResourceCounts()
+}
+
+// GetResourceCounts returns the number of groups and field groups held by the client, to monitor leaks.
+// They are all destroyed when the client is closed.
+func (c *Client) GetResourceCounts() ResourceCounts {
+	return c.res.counts()
+}
+
+type trackedGroup struct {
+	name      string
+	groupType GroupType
+	added     []GroupEntityPair // entities added to the entities of groupType
+	removed   []GroupEntityPair // entities of groupType removed from the group
+	temporary bool              // created by the library for a single call
+	created   C.dcgmGpuGrp_t    // group id the group was created with, kept by its handle
+	current   atomic.Uintptr    // group id on the current connection, also read by GroupHandle.GetHandle
+}
+
+func (g *trackedGroup) id() C.dcgmGpuGrp_t {
+	return C.dcgmGpuGrp_t(g.current.Load())
+}
+
+type trackedFieldGroup struct {
+	name      string
+	fields    []Short
+	temporary bool             // created by the library for a single call
+	current   C.dcgmFieldGrp_t // field group id on the current connection
+}
+
+type trackedWatch struct {
+	group          GroupHandle
+	fieldGroup     FieldGrpHandle
+	updateFreq     int64
+	maxKeepAge     float64
+	maxKeepSamples int32
+}
+
+type trackedPolicy struct {
+	group     GroupHandle
+	condition C.dcgmPolicyCondition_t
+	paramList []policyConditionParam
+	slot      int // slot of the policy listener
+}
+
+// clientResources records the groups, field groups, watches, health watches and policies
+// set up through a client, so that they can be set up again after the client reconnects
+// and destroyed when it is closed. The handles of the groups and field groups created through
+// the client point to what is recorded for them, which maps them to their id on the current connection,
+// so that they never alias a group created by someone else with the same id after a reconnect.
+type clientResources struct {
+	mu          sync.Mutex
+	groups      map[*trackedGroup]struct{}
+	fieldGroups map[*trackedFieldGroup]struct{}
+	watches     []trackedWatch
+	health      map[GroupHandle]HealthSystem
+	policies    []trackedPolicy
+}
+
+func (r *clientResources) addGroup(id C.dcgmGpuGrp_t, name string, groupType GroupType, temporary bool) GroupHandle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.groups == nil {
+		r.groups = make(map[*trackedGroup]struct{})
+	}
+
+	grp := &trackedGroup{name: name, groupType: groupType, temporary: temporary, created: id}
+	grp.current.Store(uintptr(id))
+	r.groups[grp] = struct{}{}
+	return GroupHandle{handle: id, group: grp}
+}
+
+// trackedGroup returns the recorded group of the handle, nil for the groups not created by the client
+func (r *clientResources) trackedGroup(g GroupHandle) *trackedGroup {
+	if _, exists := r.groups[g.group]; exists {
+		return g.group
+	}
+	return nil
+}
+
+func (r *clientResources) addGroupEntity(g GroupHandle, entity GroupEntityPair) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if grp := r.trackedGroup(g); grp != nil {
+		var found bool
+		if grp.removed, found = withoutEntity(grp.removed, entity); !found {
+			grp.added = append(grp.added, entity)
+		}
+	}
+}
+
+func (r *clientResources) removeGroupEntity(g GroupHandle, entity GroupEntityPair) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if grp := r.trackedGroup(g); grp != nil {
+		var found bool
+		if grp.added, found = withoutEntity(grp.added, entity); !found {
+			grp.removed = append(grp.removed, entity)
+		}
+	}
+}
+
+// withoutEntity removes entity from entities, it returns false if entities does not hold it
+func withoutEntity(entities []GroupEntityPair, entity GroupEntityPair) ([]GroupEntityPair, bool) {
+	for i, e := range entities {
+		if e == entity {
+			return append(entities[:i], entities[i+1:]...), true
+		}
+	}
+	return entities, false
+}
+
+func (r *clientResources) removeGroup(g GroupHandle) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.groups, g.group)
+	delete(r.health, g)
+
+	watches := r.watches[:0]
+	for _, w := range r.watches {
+		if w.group != g {
+			watches = append(watches, w)
+		}
+	}
+	r.watches = watches
+
+	policies := r.policies[:0]
+	for _, p := range r.policies {
+		if p.group != g {
+			policies = append(policies, p)
+		}
+	}
+	r.policies = policies
+}
+
+// groupId returns the id of the group on the current connection,
+// groups which are not created by the client, e.g. DCGM_GROUP_ALL_GPUS, are kept as is.
+func (r *clientResources) groupId(g GroupHandle) C.dcgmGpuGrp_t {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if grp := r.trackedGroup(g); grp != nil {
+		return grp.id()
+	}
+	return g.handle
+}
+
+// groupHandle returns the handle of the group of id on the current connection,
+// which only holds the id for the groups which are not created by the client
+func (r *clientResources) groupHandle(id C.dcgmGpuGrp_t) GroupHandle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for grp := range r.groups {
+		if grp.id() == id {
+			return GroupHandle{handle: grp.created, group: grp}
+		}
+	}
+	return GroupHandle{handle: id}
+}
+
+func (r *clientResources) addFieldGroup(id C.dcgmFieldGrp_t, name string, fields []Short, temporary bool) FieldGrpHandle {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fieldGroups == nil {
+		r.fieldGroups = make(map[*trackedFieldGroup]struct{})
+	}
+
+	fg := &trackedFieldGroup{
+		name:      name,
+		fields:    append([]Short(nil), fields...),
+		temporary: temporary,
+		current:   id,
+	}
+	r.fieldGroups[fg] = struct{}{}
+	return FieldGrpHandle{handle: id, fieldGroup: fg}
+}
+
+func (r *clientResources) removeFieldGroup(fg FieldGrpHandle) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.fieldGroups, fg.fieldGroup)
+
+	watches := r.watches[:0]
+	for _, w := range r.watches {
+		if w.fieldGroup != fg {
+			watches = append(watches, w)
+		}
+	}
+	r.watches = watches
+}
+
+func (r *clientResources) fieldGroupId(fg FieldGrpHandle) C.dcgmFieldGrp_t {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.fieldGroups[fg.fieldGroup]; exists {
+		return fg.fieldGroup.current
+	}
+	return fg.handle
+}
+
+func (r *clientResources) addWatch(w trackedWatch) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.watches = append(r.watches, w)
+}
+
+func (r *clientResources) removeWatch(g GroupHandle, fg FieldGrpHandle) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	watches := r.watches[:0]
+	for _, w := range r.watches {
+		if w.group != g || w.fieldGroup != fg {
+			watches = append(watches, w)
+		}
+	}
+	r.watches = watches
+}
+
+func (r *clientResources) setHealth(g GroupHandle, systems HealthSystem) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.health == nil {
+		r.health = make(map[GroupHandle]HealthSystem)
+	}
+	r.health[g] = systems
+}
+
+func (r *clientResources) addPolicy(p trackedPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policies = append(r.policies, p)
+}
+
+// removePolicy forgets the policy of the listener slot, it returns false if the policy is unknown
+func (r *clientResources) removePolicy(slot int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, p := range r.policies {
+		if p.slot == slot {
+			r.policies = append(r.policies[:i], r.policies[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (r *clientResources) counts() ResourceCounts {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var counts ResourceCounts
+	for grp := range r.groups {
+		counts.Groups += 1
+		if grp.temporary {
+			counts.TemporaryGroups += 1
+		}
+	}
+	for fg := range r.fieldGroups {
+		counts.FieldGroups += 1
+		if fg.temporary {
+			counts.TemporaryFieldGroups += 1
+		}
+	}
+	return counts
+}
+
+// destroyAll destroys all the recorded groups and field groups on b and forgets all the resources
+func (r *clientResources) destroyAll(b backend) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for grp := range r.groups {
+		if err := b.groupDestroy(grp.id()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to destroy group %s: %w", grp.name, err))
+		}
+	}
+	for fg := range r.fieldGroups {
+		if err := b.fieldGroupDestroy(fg.current); err != nil {
+			errs = append(errs, fmt.Errorf("failed to destroy field group %s: %w", fg.name, err))
+		}
+	}
+
+	r.groups = nil
+	r.fieldGroups = nil
+	r.watches = nil
+	r.health = nil
+	r.policies = nil
+	return errors.Join(errs...)
+}
+
+// restore sets up all the recorded resources again on the backend of the new connection.
+// The ids on the new connection are only kept once everything is restored, on error the groups,
+// field groups and policies set up by this attempt are destroyed so that the next one starts over.
+func (r *clientResources) restore(b backend) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	groupIds := make(map[*trackedGroup]C.dcgmGpuGrp_t, len(r.groups))
+	fieldGroupIds := make(map[*trackedFieldGroup]C.dcgmFieldGrp_t, len(r.fieldGroups))
+	var registered []trackedPolicy
+	defer func() {
+		if err != nil {
+			r.rollback(b, groupIds, fieldGroupIds, registered)
+		}
+	}()
+
+	for grp := range r.groups {
+		id, err := b.groupCreate(grp.groupType, grp.name)
+		if err != nil {
+			return fmt.Errorf("failed to restore group %s: %w", grp.name, err)
+		}
+		groupIds[grp] = id
+		for _, e := range grp.removed {
+			if e.EntityGroupId == FE_GPU {
+				err = b.groupRemoveDevice(id, e.EntityId)
+			} else {
+				err = b.groupRemoveEntity(id, e.EntityGroupId, e.EntityId)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to restore the removal of %s %d from group %s: %w", e.EntityGroupId, e.EntityId, grp.name, err)
+			}
+		}
+		for _, e := range grp.added {
+			if e.EntityGroupId == FE_GPU {
+				err = b.groupAddDevice(id, e.EntityId)
+			} else {
+				err = b.groupAddEntity(id, e.EntityGroupId, e.EntityId)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to restore %s %d of group %s: %w", e.EntityGroupId, e.EntityId, grp.name, err)
+			}
+		}
+	}
+
+	for fg := range r.fieldGroups {
+		id, err := b.fieldGroupCreate(fg.name, fg.fields)
+		if err != nil {
+			return fmt.Errorf("failed to restore field group %s: %w", fg.name, err)
+		}
+		fieldGroupIds[fg] = id
+	}
+
+	newGroup := func(g GroupHandle) C.dcgmGpuGrp_t {
+		if id, exists := groupIds[g.group]; exists {
+			return id
+		}
+		return g.handle
+	}
+
+	for _, w := range r.watches {
+		fgId := w.fieldGroup.handle
+		if id, exists := fieldGroupIds[w.fieldGroup.fieldGroup]; exists {
+			fgId = id
+		}
+		if err := b.watchFields(newGroup(w.group), fgId, w.updateFreq, w.maxKeepAge, w.maxKeepSamples); err != nil {
+			return fmt.Errorf("failed to restore field watches: %w", err)
+		}
+	}
+
+	for g, systems := range r.health {
+		if err := b.healthSet(newGroup(g), systems); err != nil {
+			return fmt.Errorf("failed to restore health watches: %w", err)
+		}
+	}
+
+	for _, p := range r.policies {
+		grpId := newGroup(p.group)
+		if err := b.policySet(grpId, p.condition, p.paramList); err != nil {
+			return fmt.Errorf("failed to restore policy: %w", err)
+		}
+		if err := b.policyRegister(grpId, p.condition, p.slot); err != nil {
+			return fmt.Errorf("failed to restore policy registration: %w", err)
+		}
+		registered = append(registered, p)
+	}
+
+	for grp, id := range groupIds {
+		grp.current.Store(uintptr(id))
+	}
+	for fg, id := range fieldGroupIds {
+		fg.current = id
+	}
+	return nil
+}
+
+// rollback destroys what a failed restore set up on b, r.mu must be held
+func (r *clientResources) rollback(b backend, groupIds map[*trackedGroup]C.dcgmGpuGrp_t,
+	fieldGroupIds map[*trackedFieldGroup]C.dcgmFieldGrp_t, registered []trackedPolicy) {
+	for _, p := range registered {
+		grpId := p.group.handle
+		if id, exists := groupIds[p.group.group]; exists {
+			grpId = id
+		}
+		if err := b.policyUnregister(grpId, p.condition); err != nil {
+			getLogger().Warn("Failed to unregister a policy of a failed restore", "error", err)
+		}
+	}
+	for grp, id := range groupIds {
+		if err := b.groupDestroy(id); err != nil {
+			getLogger().Warn("Failed to destroy a group of a failed restore", "group", grp.name, "error", err)
+		}
+	}
+	for fg, id := range fieldGroupIds {
+		if err := b.fieldGroupDestroy(id); err != nil {
+			getLogger().Warn("Failed to destroy a field group of a failed restore", "fieldGroup", fg.name, "error", err)
+		}
+	}
+}
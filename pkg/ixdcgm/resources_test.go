@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"context"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestNoLeakOnError(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	// GPU 7 does not exist, adding it to a group fails
+	if _, err := client.GetDeviceStatus(7); err == nil {
+		t.Error("GetDeviceStatus of an unknown GPU succeeded")
+	}
+	if _, err := client.HealthCheckByGpuId(7); err == nil {
+		t.Error("HealthCheckByGpuId of an unknown GPU succeeded")
+	}
+	params := &ixdcgm.PolicyConditionParams{XidPolicyEnabled: true}
+	if _, err := client.ListenForPolicyViolationsForGPUs(context.Background(), params, 0, 7); err == nil {
+		t.Error("ListenForPolicyViolationsForGPUs of an unknown GPU succeeded")
+	}
+
+	fieldGrp, err := client.FieldGroupCreate("leakFields", []ixdcgm.Short{ixdcgm.DCGM_FI_DEV_GPU_TEMP})
+	if err != nil {
+		t.Fatalf("FieldGroupCreate failed: %v", err)
+	}
+	if _, err = client.WatchFields([]uint{0, 7}, fieldGrp, "leakGroup"); err == nil {
+		t.Error("WatchFields of an unknown GPU succeeded")
+	}
+	if err = client.FieldGroupDestroy(fieldGrp); err != nil {
+		t.Fatalf("FieldGroupDestroy failed: %v", err)
+	}
+
+	if counts := client.GetResourceCounts(); counts != (ixdcgm.ResourceCounts{}) {
+		t.Errorf("GetResourceCounts after the failed calls = %+v", counts)
+	}
+	if n := fake.GroupCount(); n != 0 {
+		t.Errorf("%d groups left after the failed calls", n)
+	}
+	if n := fake.FieldGroupCount(); n != 0 {
+		t.Errorf("%d field groups left after the failed calls", n)
+	}
+}
+
+func TestGetResourceCounts(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	client := ixdcgm.NewFakeClient(fake)
+
+	if _, err := client.CreateGroup("userGroup"); err != nil {
+		t.Fatalf("CreateGroup failed: %v", err)
+	}
+	if _, err := client.FieldGroupCreate("userFields", []ixdcgm.Short{ixdcgm.DCGM_FI_DEV_GPU_TEMP}); err != nil {
+		t.Fatalf("FieldGroupCreate failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	params := &ixdcgm.PolicyConditionParams{XidPolicyEnabled: true}
+	if _, err := client.ListenForPolicyViolationsForGPUs(ctx, params, 1); err != nil {
+		t.Fatalf("ListenForPolicyViolationsForGPUs failed: %v", err)
+	}
+
+	want := ixdcgm.ResourceCounts{Groups: 2, FieldGroups: 1, TemporaryGroups: 1}
+	if counts := client.GetResourceCounts(); counts != want {
+		t.Errorf("GetResourceCounts = %+v, want %+v", counts, want)
+	}
+
+	// the groups and field groups left are destroyed on close
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if n := fake.GroupCount(); n != 0 {
+		t.Errorf("%d groups left after Close", n)
+	}
+	if n := fake.FieldGroupCount(); n != 0 {
+		t.Errorf("%d field groups left after Close", n)
+	}
+	if counts := client.GetResourceCounts(); counts != (ixdcgm.ResourceCounts{}) {
+		t.Errorf("GetResourceCounts after Close = %+v", counts)
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SelectHints tunes the selection of SelectGpus
+type SelectHints struct {
+	// IgnoreHealth selects unhealthy GPUs too, only healthy GPUs are selected by default
+	IgnoreHealth bool
+}
+
+// SelectGpus picks the best n GPUs among candidates for a job, see Client.SelectGpus
+func SelectGpus(candidates []uint, n int, hints SelectHints) ([]uint, error) {
+	return defaultClient.SelectGpus(candidates, n, hints)
+}
+
+// SelectGpus picks the best n GPUs among candidates for a job according to their topology: the GPUs connected
+// by the fastest links, preferably on the same board, avoiding the paths across CPUs. All the GPUs are candidates
+// if candidates is empty. Fewer than n GPUs are returned if there are not enough candidates, in ascending order.
+//
+// When the ix-hostengine does not support the selection, the GPUs are selected from their topology by the
+// library, without checking their health.
+func (c *Client) SelectGpus(candidates []uint, n int, hints SelectHints) ([]uint, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("Error selecting %d gpus: %w", n, ErrBadParam)
+	}
+
+	var inputMask uint64
+	for _, gpuId := range candidates {
+		if gpuId >= C.DCGM_MAX_NUM_DEVICES {
+			return nil, fmt.Errorf("Error selecting gpus: no gpu %d: %w", gpuId, ErrBadParam)
+		}
+		inputMask |= 1 << gpuId
+	}
+	var hintFlags uint64 = C.DCGM_TOPO_HINT_F_NONE
+	if hints.IgnoreHealth {
+		hintFlags |= C.DCGM_TOPO_HINT_F_IGNOREHEALTH
+	}
+
+	b := c.backend()
+	outputMask, err := b.selectGpusByTopology(inputMask, uint32(n), hintFlags)
+	if errors.Is(err, ErrNotSupported) {
+		getLogger().Debug("Selecting gpus by topology is not supported by the hostengine, scoring their topology", "error", err)
+		return selectGpusByScore(b, candidates, n)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var gpuIds []uint
+	for gpuId := uint(0); gpuId < C.DCGM_MAX_NUM_DEVICES; gpuId++ {
+		if outputMask&(1<<gpuId) != 0 {
+			gpuIds = append(gpuIds, gpuId)
+		}
+	}
+	return gpuIds, nil
+}
+
+func (b cgoBackend) selectGpusByTopology(inputMask uint64, numGpus uint32, hintFlags uint64) (uint64, error) {
+	var outputMask C.uint64_t
+	result := C.dcgmSelectGpusByTopology(b.handle, C.uint64_t(inputMask), C.uint32_t(numGpus), &outputMask, C.uint64_t(hintFlags))
+	if err := errorString(result); err != nil {
+		return 0, fmt.Errorf("Error selecting gpus by topology: %w", err)
+	}
+	return uint64(outputMask), nil
+}
+
+// selectScore scores a set of GPUs: the rank of its slowest link first, then the sum of the ranks of its links
+type selectScore struct {
+	slowest int
+	total   int
+}
+
+func (s selectScore) better(other selectScore) bool {
+	return s.slowest > other.slowest || s.slowest == other.slowest && s.total > other.total
+}
+
+// selectGpusByScore selects n GPUs among candidates from their P2P links. Starting from each candidate, the
+// GPU with the best links to the GPUs already selected is added until there are n, the best set is returned.
+func selectGpusByScore(b backend, candidates []uint, n int) ([]uint, error) {
+	if len(candidates) == 0 {
+		var err error
+		if candidates, err = b.getSupportedDevices(); err != nil {
+			return nil, err
+		}
+	}
+	candidates = append([]uint(nil), candidates...)
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+	if n >= len(candidates) {
+		return candidates, nil
+	}
+
+	rank, err := linkRanks(b, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	var best []int
+	var bestScore selectScore
+	for seed := range candidates {
+		chosen := []int{seed}
+		score := selectScore{slowest: math.MaxInt}
+		for len(chosen) < n {
+			next, nextScore := -1, selectScore{}
+			for i := range candidates {
+				if containsIndex(chosen, i) {
+					continue
+				}
+				s := selectScore{slowest: math.MaxInt}
+				for _, j := range chosen {
+					s.slowest = min(s.slowest, rank[i][j])
+					s.total += rank[i][j]
+				}
+				if next < 0 || s.better(nextScore) {
+					next, nextScore = i, s
+				}
+			}
+			chosen = append(chosen, next)
+			score.slowest = min(score.slowest, nextScore.slowest)
+			score.total += nextScore.total
+		}
+		if best == nil || score.better(bestScore) {
+			best, bestScore = chosen, score
+		}
+	}
+
+	gpuIds := make([]uint, len(best))
+	for i, idx := range best {
+		gpuIds[i] = candidates[idx]
+	}
+	sort.Slice(gpuIds, func(i, j int) bool { return gpuIds[i] < gpuIds[j] })
+	return gpuIds, nil
+}
+
+// linkRanks ranks the links between every two candidates in the order of P2PLinkType, the links between
+// GPUs on the same board are ranked at least as P2PLinkSameBoard
+func linkRanks(b backend, candidates []uint) ([][]int, error) {
+	index := make(map[uint]int, len(candidates))
+	for i, gpuId := range candidates {
+		index[gpuId] = i
+	}
+
+	rank := make([][]int, len(candidates))
+	for i, gpuId := range candidates {
+		rank[i] = make([]int, len(candidates))
+		links, err := b.getDeviceTopology(gpuId)
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range links {
+			if j, exists := index[link.GPU]; exists {
+				rank[i][j] = int(link.Link)
+			}
+		}
+	}
+
+	for i := range candidates {
+		for j := i + 1; j < len(candidates); j++ {
+			if rank[i][j] >= int(P2PLinkSameBoard) {
+				continue
+			}
+			onSameBoard, err := b.getDeviceOnSameBoard(candidates[i], candidates[j])
+			if err != nil {
+				return nil, err
+			}
+			if onSameBoard {
+				rank[i][j] = int(P2PLinkSameBoard)
+				rank[j][i] = int(P2PLinkSameBoard)
+			}
+		}
+	}
+	return rank, nil
+}
+
+func containsIndex(indexes []int, i int) bool {
+	for _, idx := range indexes {
+		if idx == i {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"reflect"
+	"testing"
+)
+
+// DCGM_TOPOLOGY_* bits of the paths reported by dcgmGetDeviceTopology
+const (
+	topologyCPU     = 0x10
+	topologySystem  = 0x20
+	topologyIXLink1 = 0x0100
+	topologyIXLink2 = 0x0200
+)
+
+// pathsBackend reports the topology of its GPUs as the IXDCGM library does, from combined paths
+type pathsBackend struct {
+	*FakeBackend
+	paths map[[2]uint]uint
+}
+
+func (b pathsBackend) getDeviceTopology(gpuId uint) ([]P2PLink, error) {
+	var paths []gpuPath
+	for peer := uint(0); peer < uint(len(b.gpus)); peer++ {
+		if peer == gpuId {
+			continue
+		}
+		path, exists := b.paths[[2]uint{gpuId, peer}]
+		if !exists {
+			path = b.paths[[2]uint{peer, gpuId}]
+		}
+		paths = append(paths, gpuPath{gpuId: peer, path: path})
+	}
+	return topologyLinks(paths), nil
+}
+
+func TestTopologyLinksOfCombinedPaths(t *testing.T) {
+	links := topologyLinks([]gpuPath{
+		{gpuId: 1, path: topologySystem},
+		{gpuId: 2, path: topologyIXLink2 | topologySystem},
+		{gpuId: 3, path: topologyIXLink1 | topologyCPU},
+	})
+	want := []P2PLink{
+		{GPU: 1, Link: P2PLinkCrossCPU},
+		{GPU: 2, Link: P2PLinkIXLINK2},
+		{GPU: 3, Link: P2PLinkIXLINK1},
+	}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("topologyLinks = %+v, want %+v", links, want)
+	}
+}
+
+func TestSelectGpusByScoreOfCombinedPaths(t *testing.T) {
+	b := pathsBackend{
+		FakeBackend: NewFakeBackend(4),
+		paths: map[[2]uint]uint{
+			{0, 1}: topologySystem,
+			{0, 2}: topologySystem,
+			{0, 3}: topologySystem,
+			{1, 2}: topologyCPU,
+			{1, 3}: topologyIXLink1 | topologySystem,
+			{2, 3}: topologyIXLink2 | topologySystem,
+		},
+	}
+
+	for _, tc := range []struct {
+		n    int
+		want []uint
+	}{
+		{n: 2, want: []uint{2, 3}},
+		{n: 3, want: []uint{1, 2, 3}},
+	} {
+		gpuIds, err := selectGpusByScore(b, nil, tc.n)
+		if err != nil || !reflect.DeepEqual(gpuIds, tc.want) {
+			t.Errorf("selectGpusByScore(%d) = %v, %v, want %v", tc.n, gpuIds, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	selectorAll         = "all"
+	selectorUuidPrefix  = "uuid:"
+	selectorBusIdPrefix = "bus:"
+	selectorSeparator   = ","
+
+	defaultPciDomain = "00000000:"
+)
+
+// ResolveSelector returns the ids of the GPUs selected by selector, see Client.ResolveSelector
+func ResolveSelector(selector string) ([]uint, error) {
+	return defaultClient.ResolveSelector(selector)
+}
+
+// ResolveSelector returns the ids of the GPUs selected by selector, in the order they are selected and
+// without duplicates. The selector is a comma separated list of:
+//   - "all": all the supported GPUs
+//   - a GPU id, e.g. "0", or a range of GPU ids, e.g. "2-3"
+//   - "uuid:" followed by the UUID of a GPU, e.g. "uuid:GPU-6d2ec5fa-f293-57a3-9f2c-335f78120578"
+//   - "bus:" followed by the PCI bus ID of a GPU, e.g. "bus:00000000:8A:00.0" or "bus:8A:00.0"
+//
+// Selecting a GPU which does not exist returns an error wrapping ErrBadParam, selecting a GPU
+// which is not supported by IXDCGM returns an error wrapping ErrGpuNotSupported.
+func (c *Client) ResolveSelector(selector string) ([]uint, error) {
+	b := c.backend()
+	all, err := b.getAllDevices()
+	if err != nil {
+		return nil, err
+	}
+	supported, err := b.getSupportedDevices()
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[uint]bool, len(all))
+	for _, gpuId := range all {
+		exists[gpuId] = true
+	}
+	isSupported := make(map[uint]bool, len(supported))
+	for _, gpuId := range supported {
+		isSupported[gpuId] = true
+	}
+
+	// the attributes are only read for the selectors by UUID or bus ID
+	var attributes map[uint]deviceAttributes
+	findGpu := func(term string, match func(attr deviceAttributes) bool) (uint, error) {
+		if attributes == nil {
+			attributes = make(map[uint]deviceAttributes, len(all))
+			for _, gpuId := range all {
+				attr, err := b.getDeviceAttributes(gpuId)
+				if err != nil {
+					return 0, err
+				}
+				attributes[gpuId] = attr
+			}
+		}
+		for _, gpuId := range all {
+			if match(attributes[gpuId]) {
+				return gpuId, nil
+			}
+		}
+		return 0, fmt.Errorf("invalid GPU selector %q: no GPU %s: %w", selector, term, ErrBadParam)
+	}
+
+	var gpuIds []uint
+	selected := make(map[uint]bool)
+	add := func(gpuId uint) error {
+		if !isSupported[gpuId] {
+			return fmt.Errorf("invalid GPU selector %q: GPU %d: %w", selector, gpuId, ErrGpuNotSupported)
+		}
+		if !selected[gpuId] {
+			selected[gpuId] = true
+			gpuIds = append(gpuIds, gpuId)
+		}
+		return nil
+	}
+
+	for _, term := range strings.Split(selector, selectorSeparator) {
+		term = strings.TrimSpace(term)
+		switch {
+		case strings.EqualFold(term, selectorAll):
+			for _, gpuId := range supported {
+				_ = add(gpuId)
+			}
+
+		case strings.HasPrefix(term, selectorUuidPrefix):
+			uuid := strings.TrimPrefix(term, selectorUuidPrefix)
+			gpuId, err := findGpu(term, func(attr deviceAttributes) bool {
+				return strings.EqualFold(attr.Uuid, uuid)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err = add(gpuId); err != nil {
+				return nil, err
+			}
+
+		case strings.HasPrefix(term, selectorBusIdPrefix):
+			busId := normalizeBusId(strings.TrimPrefix(term, selectorBusIdPrefix))
+			gpuId, err := findGpu(term, func(attr deviceAttributes) bool {
+				return normalizeBusId(attr.BusId) == busId
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err = add(gpuId); err != nil {
+				return nil, err
+			}
+
+		default:
+			first, last, err := parseGpuRange(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid GPU selector %q: %v: %w", selector, err, ErrBadParam)
+			}
+			for gpuId := first; gpuId <= last; gpuId++ {
+				if !exists[gpuId] {
+					return nil, fmt.Errorf("invalid GPU selector %q: no GPU %d: %w", selector, gpuId, ErrBadParam)
+				}
+				if err = add(gpuId); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return gpuIds, nil
+}
+
+// parseGpuRange parses a GPU id, e.g. "0", or a range of GPU ids, e.g. "2-3"
+func parseGpuRange(term string) (first, last uint, err error) {
+	from, to, isRange := strings.Cut(term, "-")
+	if !isRange {
+		to = from
+	}
+	firstId, err := strconv.ParseUint(strings.TrimSpace(from), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%q is neither a GPU id nor a range of GPU ids", term)
+	}
+	lastId, err := strconv.ParseUint(strings.TrimSpace(to), 10, 32)
+	if err != nil || lastId < firstId {
+		return 0, 0, fmt.Errorf("%q is neither a GPU id nor a range of GPU ids", term)
+	}
+	return uint(firstId), uint(lastId), nil
+}
+
+// normalizeBusId upper cases a PCI bus ID and adds the default domain when it has none
+func normalizeBusId(busId string) string {
+	busId = strings.ToUpper(strings.TrimSpace(busId))
+	if strings.Count(busId, ":") == 1 {
+		busId = defaultPciDomain + busId
+	}
+	return busId
+}
+
+// GroupFromSelector creates a group of the GPUs selected by selector, see Client.GroupFromSelector
+func GroupFromSelector(groupName, selector string) (GroupHandle, error) {
+	return defaultClient.GroupFromSelector(groupName, selector)
+}
+
+// GroupFromSelector creates a group of the GPUs selected by selector, the syntax of which is described by
+// ResolveSelector. Nothing is created when the selector is invalid.
+func (c *Client) GroupFromSelector(groupName, selector string) (GroupHandle, error) {
+	gpuIds, err := c.ResolveSelector(selector)
+	if err != nil {
+		return GroupHandle{}, err
+	}
+
+	group, err := c.CreateGroup(groupName)
+	if err != nil {
+		return GroupHandle{}, err
+	}
+	for _, gpuId := range gpuIds {
+		if err = c.AddToGroup(group, gpuId); err != nil {
+			if destroyErr := c.DestroyGroup(group); destroyErr != nil {
+				getLogger().Warn("Failed to destroy group", "group", groupName, "error", destroyErr)
+			}
+			return GroupHandle{}, err
+		}
+	}
+	return group, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestResolveSelector(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(4)
+	if err := fake.SetGPU(3, ixdcgm.FakeGPU{Uuid: "GPU-unsupported", BusId: "00000000:04:00.0", Unsupported: true}); err != nil {
+		t.Fatal(err)
+	}
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	for _, tc := range []struct {
+		selector string
+		want     []uint
+		err      error
+	}{
+		{selector: "all", want: []uint{0, 1, 2}},
+		{selector: "2, 0-1,1", want: []uint{2, 0, 1}},
+		{selector: "uuid:gpu-00000000-0000-0000-0000-000000000001", want: []uint{1}},
+		{selector: "bus:00000000:03:00.0,bus:01:00.0", want: []uint{2, 0}},
+		{selector: "1-3", err: ixdcgm.ErrGpuNotSupported},
+		{selector: "uuid:GPU-unsupported", err: ixdcgm.ErrGpuNotSupported},
+		{selector: "7", err: ixdcgm.ErrBadParam},
+		{selector: "bus:00000000:8A:00.0", err: ixdcgm.ErrBadParam},
+		{selector: "2-1", err: ixdcgm.ErrBadParam},
+		{selector: "0,", err: ixdcgm.ErrBadParam},
+		{selector: "gpu0", err: ixdcgm.ErrBadParam},
+	} {
+		gpuIds, err := client.ResolveSelector(tc.selector)
+		if tc.err != nil {
+			if !errors.Is(err, tc.err) {
+				t.Errorf("ResolveSelector(%q) returned %v, %v, want an error wrapping %v", tc.selector, gpuIds, err, tc.err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(gpuIds, tc.want) {
+			t.Errorf("ResolveSelector(%q) = %v, %v, want %v", tc.selector, gpuIds, err, tc.want)
+		}
+	}
+}
+
+func TestGroupFromSelector(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(3)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	group, err := client.GroupFromSelector("selected", "0,2")
+	if err != nil {
+		t.Fatalf("GroupFromSelector failed: %v", err)
+	}
+	info, err := client.GetGroupInfo(group)
+	if err != nil {
+		t.Fatalf("GetGroupInfo failed: %v", err)
+	}
+	if len(info.EntityList) != 2 || info.EntityList[0].EntityId != 0 || info.EntityList[1].EntityId != 2 {
+		t.Errorf("group holds %v, want GPUs 0 and 2", info.EntityList)
+	}
+	if err = client.DestroyGroup(group); err != nil {
+		t.Fatalf("DestroyGroup failed: %v", err)
+	}
+
+	if _, err = client.GroupFromSelector("invalid", "0,5"); !errors.Is(err, ixdcgm.ErrBadParam) {
+		t.Errorf("GroupFromSelector of an unknown GPU returned %v, want ErrBadParam", err)
+	}
+	if n := fake.GroupCount(); n != 0 {
+		t.Errorf("%d groups left, want none", n)
+	}
+}
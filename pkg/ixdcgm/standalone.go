@@ -25,53 +25,58 @@ package ixdcgm
 import "C"
 import (
 	"fmt"
-	"strconv"
 	"unsafe"
 )
 
 type standalone struct {
+	handle DcgmHandle
+	cfg    Config
 }
 
 func (s *standalone) Shutdown() error {
-	result := C.dcgmDisconnect(handle.handle)
+	result := C.dcgmDisconnect(s.handle.handle)
 	if err := errorString(result); err != nil {
-		return fmt.Errorf("Error disconnecting from ix-hostengine: %s", err)
-	}
-
-	result = C.dcgmShutdown()
-	if err := errorString(result); err != nil {
-		return fmt.Errorf("failed to shutdown dcgm: %v", err)
+		return fmt.Errorf("Error disconnecting from ix-hostengine: %w", err)
 	}
 	return nil
 }
 
 func (s *standalone) Start(args ...string) (DcgmHandle, error) {
-	fmt.Println("Start ixdcgm based on Standalone mode.")
+	return startWithArgs(s, Standalone, args...)
+}
 
-	if len(args) < 2 {
-		return DcgmHandle{}, fmt.Errorf("missing dcgm address or port")
-	}
-	result := C.dcgmInit()
-	if err := errorString(result); err != nil {
-		return DcgmHandle{}, fmt.Errorf("failed to initialize dcgm: %v", err)
-	}
+func (s *standalone) start(cfg Config) (DcgmHandle, error) {
+	getLogger().Info("Start ixdcgm based on Standalone mode", "address", cfg.Address, "unixSocket", cfg.UnixSocket)
+
+	s.cfg = cfg
+	return s.connect()
+}
+
+// reconnect drops the current connection and connects to the same ix-hostengine again
+func (s *standalone) reconnect() (DcgmHandle, error) {
+	// the connection is already broken, so the result does not matter
+	_ = C.dcgmDisconnect(s.handle.handle)
+	s.handle = DcgmHandle{}
+	return s.connect()
+}
+
+func (s *standalone) connect() (DcgmHandle, error) {
 	var cHandler C.dcgmHandle_t
-	addr := string2Char(args[0])
+	addr := string2Char(s.cfg.Address)
 	defer freeCString(addr)
 
 	var connectParams C.dcgmConnectV2Params_v2
 	connectParams.version = C.uint(makeVersion2(unsafe.Sizeof(connectParams)))
-
-	sck, err := strconv.ParseInt(args[1], 10, 32)
-	if err != nil {
-		return DcgmHandle{}, fmt.Errorf("failed to parse dcgm sock: %v", err)
+	connectParams.timeoutMs = C.uint(s.cfg.Timeout.Milliseconds())
+	if s.cfg.UnixSocket {
+		connectParams.addressIsUnixSocket = C.uint(1)
 	}
-	connectParams.addressIsUnixSocket = C.uint(sck)
 
-	result = C.dcgmConnect_v2(addr, &connectParams, &cHandler)
-	if err = errorString(result); err != nil {
-		return DcgmHandle{}, fmt.Errorf("failed to connect dcgm: %v", err)
+	result := C.dcgmConnect_v2(addr, &connectParams, &cHandler)
+	if err := errorString(result); err != nil {
+		return DcgmHandle{}, fmt.Errorf("failed to connect dcgm: %w", err)
 	}
 
-	return DcgmHandle{handle: cHandler}, nil
+	s.handle = DcgmHandle{handle: cHandler}
+	return s.handle, nil
 }
@@ -26,98 +26,104 @@ import "C"
 import (
 	"fmt"
 	"os"
-	"os/exec"
-	"syscall"
+	"path/filepath"
 	"unsafe"
 )
 
-var (
-	hostengineAsChildPid int
-	startHostengineDir   = "/tmp"
-)
+const hostengineSocketName = "ix-hostengine.socket"
+
+var startHostengineDir = "/tmp"
 
 type startHostengine struct {
+	handle  DcgmHandle
+	cfg     Config
+	dir     string // private directory holding the socket of the ix-hostengine
+	process *hostengineProcess
 }
 
-func (s *startHostengine) Shutdown() (err error) {
-	if err = s.disconnect(); err != nil {
-		return
-	}
+func (s *startHostengine) Shutdown() error {
+	err := s.disconnect()
 
 	// terminate ix-hostengine
-	cmd := exec.Command("ix-hostengine", "--term")
-	cmd.Env = append(os.Environ(),
-		"PATH="+os.Getenv("PATH=")+":"+ixdcgmBinDir,
-		"LD_LIBRARY_PATH="+os.Getenv("LD_LIBRARY_PATH")+":"+ixdcgmLibDir,
-	)
-	if err = cmd.Run(); err != nil {
-		return fmt.Errorf("Error terminating ix-hostengine: %s", err)
+	if perr := s.process.stop(); perr != nil {
+		err = fmt.Errorf("Error terminating ix-hostengine: %w", perr)
+	} else {
+		getLogger().Info("Successfully terminated ix-hostengine")
 	}
-	fmt.Println("Successfully terminated ix-hostengine.")
-
-	return syscall.Kill(hostengineAsChildPid, syscall.SIGKILL)
+	if rerr := os.RemoveAll(s.dir); rerr != nil && err == nil {
+		err = rerr
+	}
+	return err
 }
 
 func (s *startHostengine) disconnect() (err error) {
-	result := C.dcgmDisconnect(handle.handle)
+	result := C.dcgmDisconnect(s.handle.handle)
 	if err = errorString(result); err != nil {
-		return fmt.Errorf("Error disconnecting from ix-hostengine: %s", err)
-	}
-
-	result = C.dcgmShutdown()
-	if err = errorString(result); err != nil {
-		return fmt.Errorf("Error shutting down IXDCGM: %s", err)
+		return fmt.Errorf("Error disconnecting from ix-hostengine: %w", err)
 	}
 	return
 }
 
-func (s startHostengine) Start(args ...string) (DcgmHandle, error) {
-	fmt.Println("Start ixdcgm based on StartHostengine mode.")
+func (s *startHostengine) Start(args ...string) (DcgmHandle, error) {
+	return startWithArgs(s, StartHostengine, args...)
+}
+
+func (s *startHostengine) start(cfg Config) (DcgmHandle, error) {
+	getLogger().Info("Start ixdcgm based on StartHostengine mode")
 
-	os.Setenv("PATH", os.Getenv("PATH=")+":"+ixdcgmBinDir)
-	bin, err := exec.LookPath("ix-hostengine")
+	uptDirMu.Lock()
+	parent := startHostengineDir
+	uptDirMu.Unlock()
+	dir, err := os.MkdirTemp(parent, "ixdcgm")
 	if err != nil {
-		return DcgmHandle{}, fmt.Errorf("Error finding ix-hostengine: %s", err)
+		return DcgmHandle{}, fmt.Errorf("Error creating socket directory in %s directory: %w", parent, err)
+	}
+
+	process, err := newHostengineProcess(filepath.Join(dir, hostengineSocketName), cfg)
+	if err == nil {
+		err = process.start()
 	}
-	var procAttr syscall.ProcAttr
-	procAttr.Files = []uintptr{
-		uintptr(syscall.Stdin),
-		uintptr(syscall.Stdout),
-		uintptr(syscall.Stderr)}
-	procAttr.Sys = &syscall.SysProcAttr{Setpgid: true}
-	procAttr.Env = []string{"LD_LIBRARY_PATH=" + os.Getenv("LD_LIBRARY_PATH") + ":" + ixdcgmLibDir}
-
-	dir := startHostengineDir
-	socketFile, err := os.CreateTemp(dir, "ixdcgm")
 	if err != nil {
-		return DcgmHandle{}, fmt.Errorf("Error creating socket file in %s directory: %s", dir, err)
+		os.RemoveAll(dir)
+		return DcgmHandle{}, fmt.Errorf("Error starting ix-hostengine: %w", err)
 	}
 
-	socketPath := socketFile.Name()
-	defer os.Remove(socketPath)
-	connectArg := "--domain-socket"
-	hostengineAsChildPid, err = syscall.ForkExec(bin, []string{bin, connectArg, socketPath}, &procAttr)
+	s.cfg = cfg
+	s.dir = dir
+	s.process = process
+	h, err := s.connect()
 	if err != nil {
-		return DcgmHandle{}, fmt.Errorf("Error fork-execing ix-hostengine: %s", err)
-	}
-	result := C.dcgmInit()
-	if err = errorString(result); err != nil {
-		return DcgmHandle{}, fmt.Errorf("Error initializing IXDCGM: %s", err)
+		_ = process.stop()
+		os.RemoveAll(dir)
+		return DcgmHandle{}, err
 	}
+	return h, nil
+}
+
+// reconnect connects again to the ix-hostengine, which is restarted if it died
+func (s *startHostengine) reconnect() (DcgmHandle, error) {
+	// the connection is already broken, so the result does not matter
+	_ = C.dcgmDisconnect(s.handle.handle)
+	s.handle = DcgmHandle{}
+	return s.connect()
+}
 
+func (s *startHostengine) connect() (DcgmHandle, error) {
 	var cHandle C.dcgmHandle_t
 	var connectParams C.dcgmConnectV2Params_v2
 	connectParams.version = makeVersion2(unsafe.Sizeof(connectParams))
+	connectParams.timeoutMs = C.uint(s.cfg.Timeout.Milliseconds())
 	connectParams.addressIsUnixSocket = C.uint(1)
-	cSockPath := C.CString(socketPath)
+	cSockPath := C.CString(s.process.socketPath)
 	defer freeCString(cSockPath)
 
-	result = C.dcgmConnect_v2(cSockPath, &connectParams, &cHandle)
-	if err = errorString(result); err != nil {
-		return DcgmHandle{}, fmt.Errorf("Error connecting to ix-hostengine: %s", err)
+	result := C.dcgmConnect_v2(cSockPath, &connectParams, &cHandle)
+	if err := errorString(result); err != nil {
+		return DcgmHandle{}, fmt.Errorf("Error connecting to ix-hostengine: %w", err)
 	}
 
-	return DcgmHandle{handle: cHandle}, nil
+	s.handle = DcgmHandle{handle: cHandle}
+	return s.handle, nil
 }
 
 func SetStartHostengineDir(dir string) error {
@@ -0,0 +1,223 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+
+	"github.com/creasty/defaults"
+)
+
+const connectionEventChanCap = 16
+
+// ConnectionState is the state of the connection to the ix-hostengine reported by the supervisor
+type ConnectionState int
+
+const (
+	ConnectionConnected    ConnectionState = iota // The connection is re-established and the client's resources are restored
+	ConnectionLost                                // The connection to the ix-hostengine is not valid any longer
+	ConnectionReconnecting                        // A reconnect attempt failed and will be retried after a backoff
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionConnected:
+		return "Connected"
+	case ConnectionLost:
+		return "Lost"
+	case ConnectionReconnecting:
+		return "Reconnecting"
+	}
+	return "Unknown"
+}
+
+// ConnectionEvent reports a change of the connection state
+type ConnectionEvent struct {
+	State ConnectionState
+	Time  time.Time
+	// Attempt is the number of failed reconnect attempts so far
+	Attempt int
+	// Err is the error which caused the state change, nil for ConnectionConnected
+	Err error
+}
+
+type SupervisorParams struct {
+	// CheckInterval specifies how often the health of the ix-hostengine is checked. Default is 5s.
+	CheckInterval time.Duration `default:"5s"`
+
+	// InitialBackoff specifies how long to wait before retrying a failed reconnect. Default is 1s.
+	InitialBackoff time.Duration `default:"1s"`
+
+	// MaxBackoff specifies the maximum wait between reconnect attempts, the backoff doubles
+	// after each failed attempt until it reaches MaxBackoff. Default is 1m.
+	MaxBackoff time.Duration `default:"1m"`
+}
+
+// reconnecter is implemented by the modes which are able to reconnect to the ix-hostengine
+type reconnecter interface {
+	reconnect() (DcgmHandle, error)
+}
+
+type supervisor struct {
+	cancel context.CancelFunc
+}
+
+// Supervise watches the connection of the default client, see Client.Supervise
+func Supervise(ctx context.Context, params *SupervisorParams) (<-chan ConnectionEvent, error) {
+	return defaultClient.Supervise(ctx, params)
+}
+
+// Supervise periodically checks the health of the ix-hostengine the client is connected to.
+// Once the connection is found not valid any longer, e.g. because the ix-hostengine restarted,
+// the client reconnects with an exponential backoff and sets up again the groups, field groups,
+// watches, health watches and policies created through it, handles of which stay valid.
+// Changes of the connection state are sent on the returned channel, which is closed when
+// ctx is done or the client is closed. Only Standalone and StartHostengine modes are supported,
+// in StartHostengine mode the client reconnects once the ix-hostengine child process is restarted.
+func (c *Client) Supervise(ctx context.Context, params *SupervisorParams) (<-chan ConnectionEvent, error) {
+	if params == nil {
+		params = &SupervisorParams{}
+	}
+	if err := defaults.Set(params); err != nil {
+		return nil, err
+	}
+	if params.CheckInterval <= 0 || params.InitialBackoff <= 0 || params.MaxBackoff < params.InitialBackoff {
+		return nil, fmt.Errorf("bad parameters: intervals must be positive and MaxBackoff not less than InitialBackoff")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil, errNotConnected()
+	}
+	if _, ok := c.conn.(reconnecter); !ok {
+		return nil, fmt.Errorf("connection supervision is only supported in Standalone and StartHostengine modes")
+	}
+	if c.supervisor != nil {
+		return nil, fmt.Errorf("connection is already supervised")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &supervisor{cancel: cancel}
+	c.supervisor = s
+
+	events := make(chan ConnectionEvent, connectionEventChanCap)
+	go func() {
+		defer func() {
+			cancel()
+			c.mu.Lock()
+			if c.supervisor == s {
+				c.supervisor = nil
+			}
+			c.mu.Unlock()
+			close(events)
+		}()
+		c.supervise(ctx, params, events)
+	}()
+
+	return events, nil
+}
+
+func (c *Client) supervise(ctx context.Context, params *SupervisorParams, events chan<- ConnectionEvent) {
+	ticker := time.NewTicker(params.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		h, release, err := c.libHandle()
+		if err == nil {
+			err = hostengineIsHealthy(h)
+			release()
+		}
+		if !errors.Is(err, ErrConnectionNotValid) {
+			continue
+		}
+		sendConnectionEvent(events, ConnectionEvent{State: ConnectionLost, Time: time.Now(), Err: err})
+
+		backoff := params.InitialBackoff
+		for attempt := 1; ; attempt++ {
+			if err = c.reconnect(); err == nil {
+				sendConnectionEvent(events, ConnectionEvent{State: ConnectionConnected, Time: time.Now(), Attempt: attempt - 1})
+				break
+			}
+			sendConnectionEvent(events, ConnectionEvent{State: ConnectionReconnecting, Time: time.Now(), Attempt: attempt, Err: err})
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > params.MaxBackoff {
+				backoff = params.MaxBackoff
+			}
+		}
+	}
+}
+
+// reconnect connects the client again and restores the resources set up through it
+func (c *Client) reconnect() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return errNotConnected()
+	}
+
+	h, err := c.conn.(reconnecter).reconnect()
+	if err != nil {
+		return err
+	}
+	c.handle = h
+	return c.res.restore(c.backendLocked())
+}
+
+func hostengineIsHealthy(h C.dcgmHandle_t) error {
+	var health C.dcgmHostengineHealth_t
+	health.version = makeVersion1(unsafe.Sizeof(health))
+
+	result := C.dcgmHostengineIsHealthy(h, &health)
+	if err := errorString(result); err != nil {
+		return err
+	}
+	if health.overallHealth != 0 {
+		return fmt.Errorf("ix-hostengine is not healthy, code: %d", uint(health.overallHealth))
+	}
+	return nil
+}
+
+func sendConnectionEvent(events chan<- ConnectionEvent, event ConnectionEvent) {
+	select {
+	case events <- event:
+	default:
+		getLogger().Error("The connection event channel is already full, new events will be discarded", "state", event.State)
+	}
+}
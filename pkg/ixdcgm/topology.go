@@ -24,8 +24,11 @@ package ixdcgm
 */
 import "C"
 import (
+	"context"
 	"fmt"
 	"unsafe"
+
+	"github.com/bits-and-blooms/bitset"
 )
 
 type P2PLinkType uint
@@ -114,42 +117,192 @@ func getP2PLink(path uint) P2PLinkType {
 	return P2PLinkUnknown
 }
 
-func getBusid(gpuid uint) (string, error) {
-	var device C.dcgmDeviceAttributes_v3
-	device.version = makeVersion3(unsafe.Sizeof(device))
+// p2pLinkOfPath returns the IXLink part of a path if the GPUs are connected through IXLinks, its PCI part otherwise
+func p2pLinkOfPath(path uint) P2PLinkType {
+	if ixlink := path & 0xFFFFFF00; ixlink != 0 {
+		return getP2PLink(ixlink)
+	}
+	return getP2PLink(path & 0xFF)
+}
 
-	result := C.dcgmGetDeviceAttributes(handle.handle, C.uint(gpuid), &device)
-	if err := errorString(result); err != nil {
-		return "", fmt.Errorf("Error getting device busid: %s", err)
+func (c *Client) getDeviceTopology(ctx context.Context, gpuid uint) (links []P2PLink, err error) {
+	b := c.backend()
+	links, err = callCtx(ctx, func() ([]P2PLink, error) {
+		return b.getDeviceTopology(gpuid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	attr, err := callCtx(ctx, func() (deviceAttributes, error) {
+		return b.getDeviceAttributes(gpuid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error getting device busid: %w", err)
+	}
+	for i := range links {
+		links[i].BusID = attr.BusId
 	}
-	return *stringPtr(&device.identifiers.pciBusId[0]), nil
+	return
 }
 
-func getDeviceTopology(gpuid uint) (links []P2PLink, err error) {
+func (b cgoBackend) getDeviceTopology(gpuid uint) (links []P2PLink, err error) {
 	var topology C.dcgmDeviceTopology_v1
 	topology.version = makeVersion1(unsafe.Sizeof(topology))
 
-	result := C.dcgmGetDeviceTopology(handle.handle, C.uint(gpuid), &topology)
-	if result == C.DCGM_ST_NOT_SUPPORTED {
-		return links, fmt.Errorf("DcgmGetDeviceTopology is not supported")
+	result := C.dcgmGetDeviceTopology(b.handle, C.uint(gpuid), &topology)
+	if err = errorString(result); err != nil {
+		return links, fmt.Errorf("Error getting topology of gpu %d: %w", gpuid, err)
+	}
+
+	paths := make([]gpuPath, topology.numGpus)
+	for i := range paths {
+		paths[i] = gpuPath{gpuId: uint(topology.gpuPaths[i].gpuId), path: uint(topology.gpuPaths[i].path)}
+	}
+	return topologyLinks(paths), nil
+}
+
+// gpuPath is the path to a GPU reported by dcgmGetDeviceTopology, a bitmask of its PCI and IXLink parts
+type gpuPath struct {
+	gpuId uint
+	path  uint
+}
+
+func topologyLinks(paths []gpuPath) []P2PLink {
+	links := make([]P2PLink, len(paths))
+	for i, p := range paths {
+		links[i] = P2PLink{GPU: p.gpuId, Link: p2pLinkOfPath(p.path)}
+	}
+	return links
+}
+
+// GroupTopology is the topology of the GPUs of a group, to place the processes of a multi-GPU job
+type GroupTopology struct {
+	// CPUAffinity is the CPUs with affinity to all the GPUs of the group, e.g. "0-19,40-59", "N/A" if there is none
+	CPUAffinity string
+	// NUMAAffinity is the NUMA nodes with affinity to any GPU of the group, e.g. "0-1", "N/A" if it is unknown
+	NUMAAffinity string
+	// NumaOptimal is false if the GPUs of the group do not all have the same CPU affinity
+	NumaOptimal bool
+	// SlowestPath is the slowest P2P link between two GPUs of the group, P2PLinkUnknown if it holds a single GPU
+	SlowestPath P2PLinkType
+	// IXLinkConnected tells whether every two GPUs of the group are connected through IXLinks
+	IXLinkConnected bool
+}
+
+// groupTopology is the topology of a group as returned by the backend
+type groupTopology struct {
+	CpuAffinityMask []uint64
+	NumaOptimal     bool
+	SlowestPath     P2PLinkType
+}
+
+// GetGroupTopology returns the topology of the GPUs of a group, see Client.GetGroupTopology
+func GetGroupTopology(groupId GroupHandle) (GroupTopology, error) {
+	return defaultClient.GetGroupTopology(groupId)
+}
+
+// GetGroupTopology returns the combined affinity of the GPUs of a group and how they are connected to each other
+func (c *Client) GetGroupTopology(groupId GroupHandle) (GroupTopology, error) {
+	return c.getGroupTopology(context.Background(), groupId)
+}
+
+// GetGroupTopologyCtx is GetGroupTopology returning ctx.Err() as soon as ctx is done
+func GetGroupTopologyCtx(ctx context.Context, groupId GroupHandle) (GroupTopology, error) {
+	return defaultClient.GetGroupTopologyCtx(ctx, groupId)
+}
+
+// GetGroupTopologyCtx is GetGroupTopology returning ctx.Err() as soon as ctx is done
+func (c *Client) GetGroupTopologyCtx(ctx context.Context, groupId GroupHandle) (GroupTopology, error) {
+	return c.getGroupTopology(ctx, groupId)
+}
+
+func (c *Client) getGroupTopology(ctx context.Context, groupId GroupHandle) (GroupTopology, error) {
+	topology, err := callCtx(ctx, func() (topology groupTopology, err error) {
+		err = c.withBackend(func(b backend) (err error) {
+			topology, err = b.getGroupTopology(c.res.groupId(groupId))
+			return err
+		})
+		return topology, err
+	})
+	if err != nil {
+		return GroupTopology{}, err
 	}
-	if result != C.DCGM_ST_OK {
-		return links, &DcgmError{msg: C.GoString(C.errorString(result)), Code: result}
+
+	ret := GroupTopology{
+		CPUAffinity:     affinityString(bitset.From(topology.CpuAffinityMask)),
+		NUMAAffinity:    "N/A",
+		NumaOptimal:     topology.NumaOptimal,
+		SlowestPath:     topology.SlowestPath,
+		IXLinkConnected: topology.SlowestPath >= P2PLinkIXLINK1,
 	}
 
-	busid, err := getBusid(gpuid)
+	numaAffinity, err := c.getGroupNumaAffinity(ctx, groupId)
 	if err != nil {
-		return
+		getLogger().Warn("Error getting numa affinity of group, set NUMA Affinity to N/A", "error", err)
+	} else {
+		ret.NUMAAffinity = numaAffinity
 	}
+	return ret, nil
+}
 
-	for i := uint(0); i < uint(topology.numGpus); i++ {
-		gpu := topology.gpuPaths[i].gpuId
-		p2pLink := P2PLink{
-			GPU:   uint(gpu),
-			BusID: busid,
-			Link:  getP2PLink(uint(topology.gpuPaths[i].path)),
+// getGroupNumaAffinity returns the union of the NUMA affinities of the GPUs of the group
+func (c *Client) getGroupNumaAffinity(ctx context.Context, groupId GroupHandle) (string, error) {
+	info, err := c.GetGroupInfo(groupId)
+	if err != nil {
+		return "", err
+	}
+	var gpuIds []uint
+	for _, e := range info.EntityList {
+		if e.EntityGroupId == FE_GPU {
+			gpuIds = append(gpuIds, e.EntityId)
 		}
-		links = append(links, p2pLink)
 	}
-	return
+	if len(gpuIds) == 0 {
+		return "N/A", nil
+	}
+
+	affFields, err := affinityFields("NUMA")
+	if err != nil {
+		return "", err
+	}
+	release, err := c.watchTemporary(ctx, "GrpNUMAAff", gpuIds, affFields)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	nodes := &bitset.BitSet{}
+	for _, gpuId := range gpuIds {
+		values, err := c.GetLatestValuesForFieldsCtx(ctx, gpuId, affFields)
+		if err != nil {
+			return "", fmt.Errorf("Error getting NUMA affinity: %w", err)
+		}
+		mask, valid := affinityMask(values)
+		if !valid {
+			return "N/A", nil
+		}
+		nodes.InPlaceUnion(mask)
+	}
+	return affinityString(nodes), nil
+}
+
+func (b cgoBackend) getGroupTopology(groupId C.dcgmGpuGrp_t) (groupTopology, error) {
+	var topology C.dcgmGroupTopology_v1
+	topology.version = makeVersion1(unsafe.Sizeof(topology))
+
+	result := C.dcgmGetGroupTopology(b.handle, groupId, &topology)
+	if err := errorString(result); err != nil {
+		return groupTopology{}, fmt.Errorf("Error getting topology of group: %w", err)
+	}
+
+	mask := make([]uint64, len(topology.groupCpuAffinityMask))
+	for i, word := range topology.groupCpuAffinityMask {
+		mask[i] = uint64(word)
+	}
+	return groupTopology{
+		CpuAffinityMask: mask,
+		NumaOptimal:     topology.numaOptimalFlag != 0,
+		SlowestPath:     p2pLinkOfPath(uint(topology.slowestPath)),
+	}, nil
 }
@@ -0,0 +1,152 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestGetDeviceTopology(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(4)
+	for gpuId, board := range []int{0, 0, 1, 1} {
+		if err := fake.SetGPU(uint(gpuId), ixdcgm.FakeGPU{BusId: "00000000:0A:00.0", Board: board}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fake.SetP2PLink(0, 2, ixdcgm.P2PLinkIXLINK2)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	links, err := client.GetDeviceTopology(0)
+	if err != nil {
+		t.Fatalf("GetDeviceTopology failed: %v", err)
+	}
+	want := []ixdcgm.P2PLink{
+		{GPU: 1, BusID: "00000000:0A:00.0", Link: ixdcgm.P2PLinkSameBoard},
+		{GPU: 2, BusID: "00000000:0A:00.0", Link: ixdcgm.P2PLinkIXLINK2},
+		{GPU: 3, BusID: "00000000:0A:00.0", Link: ixdcgm.P2PLinkSameCPU},
+	}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("GetDeviceTopology = %+v, want %+v", links, want)
+	}
+	if got := links[1].Link.PCIPaths(); got != "IX2" {
+		t.Errorf("PCIPaths of an IXLink with two hops = %s, want IX2", got)
+	}
+
+	sameBoard, err := client.GetDeviceOnSameBoard(2, 3)
+	if err != nil || !sameBoard {
+		t.Errorf("GetDeviceOnSameBoard(2, 3) = %v, %v, want true", sameBoard, err)
+	}
+	sameBoard, err = client.GetDeviceOnSameBoard(1, 2)
+	if err != nil || sameBoard {
+		t.Errorf("GetDeviceOnSameBoard(1, 2) = %v, %v, want false", sameBoard, err)
+	}
+}
+
+func TestGetGroupTopology(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(3)
+	fake.SetP2PLink(0, 1, ixdcgm.P2PLinkIXLINK2)
+	fake.SetP2PLink(0, 2, ixdcgm.P2PLinkIXLINK1)
+	fake.SetP2PLink(1, 2, ixdcgm.P2PLinkCrossCPU)
+	// CPUs 0-7 for GPU 0, CPUs 4-11 for GPUs 1 and 2; NUMA node 0 for GPU 0, node 1 for GPUs 1 and 2
+	fake.SetFieldInt64(0, ixdcgm.DCGM_FI_DEV_CPU_AFFINITY_0, 0xff)
+	fake.SetFieldInt64(0, ixdcgm.DCGM_FI_DEV_MEM_AFFINITY_0, 0x1)
+	for _, gpuId := range []uint{1, 2} {
+		fake.SetFieldInt64(gpuId, ixdcgm.DCGM_FI_DEV_CPU_AFFINITY_0, 0xff0)
+		fake.SetFieldInt64(gpuId, ixdcgm.DCGM_FI_DEV_MEM_AFFINITY_0, 0x2)
+	}
+	for _, gpuId := range []uint{0, 1, 2} {
+		for _, field := range []ixdcgm.Short{
+			ixdcgm.DCGM_FI_DEV_CPU_AFFINITY_1, ixdcgm.DCGM_FI_DEV_CPU_AFFINITY_2, ixdcgm.DCGM_FI_DEV_CPU_AFFINITY_3,
+			ixdcgm.DCGM_FI_DEV_MEM_AFFINITY_1, ixdcgm.DCGM_FI_DEV_MEM_AFFINITY_2, ixdcgm.DCGM_FI_DEV_MEM_AFFINITY_3,
+		} {
+			fake.SetFieldInt64(gpuId, field, 0)
+		}
+	}
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	pair, err := client.GroupFromSelector("pair", "0-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	topology, err := client.GetGroupTopology(pair)
+	if err != nil {
+		t.Fatalf("GetGroupTopology failed: %v", err)
+	}
+	want := ixdcgm.GroupTopology{
+		CPUAffinity:     "4-7",
+		NUMAAffinity:    "0-1",
+		NumaOptimal:     false,
+		SlowestPath:     ixdcgm.P2PLinkIXLINK2,
+		IXLinkConnected: true,
+	}
+	if topology != want {
+		t.Errorf("GetGroupTopology of GPUs 0 and 1 = %+v, want %+v", topology, want)
+	}
+
+	all, err := client.GetGroupTopology(ixdcgm.GroupAllGPUs())
+	if err != nil {
+		t.Fatalf("GetGroupTopology failed: %v", err)
+	}
+	if all.SlowestPath != ixdcgm.P2PLinkCrossCPU || all.IXLinkConnected {
+		t.Errorf("GetGroupTopology of all the GPUs = %+v, want a SYS path without IXLink", all)
+	}
+
+	if counts := client.GetResourceCounts(); counts.TemporaryGroups != 0 || counts.TemporaryFieldGroups != 0 {
+		t.Errorf("GetResourceCounts after GetGroupTopology = %+v, want no temporary group", counts)
+	}
+}
+
+func TestSelectGpus(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(4)
+	for gpuId, board := range []int{0, 0, 1, 1} {
+		if err := fake.SetGPU(uint(gpuId), ixdcgm.FakeGPU{Board: board}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fake.SetP2PLink(2, 3, ixdcgm.P2PLinkIXLINK2)
+	fake.SetP2PLink(0, 2, ixdcgm.P2PLinkCrossCPU)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	// the fake does not select GPUs, they are selected from their topology
+	for _, tc := range []struct {
+		candidates []uint
+		n          int
+		want       []uint
+	}{
+		{n: 2, want: []uint{2, 3}},
+		{candidates: []uint{0, 1, 2}, n: 2, want: []uint{0, 1}},
+		{n: 3, want: []uint{1, 2, 3}},
+		{candidates: []uint{2, 0}, n: 3, want: []uint{0, 2}},
+	} {
+		gpuIds, err := client.SelectGpus(tc.candidates, tc.n, ixdcgm.SelectHints{})
+		if err != nil || !reflect.DeepEqual(gpuIds, tc.want) {
+			t.Errorf("SelectGpus(%v, %d) = %v, %v, want %v", tc.candidates, tc.n, gpuIds, err, tc.want)
+		}
+	}
+
+	if _, err := client.SelectGpus(nil, 0, ixdcgm.SelectHints{}); !errors.Is(err, ixdcgm.ErrBadParam) {
+		t.Errorf("SelectGpus of no GPU returned %v, want ErrBadParam", err)
+	}
+}
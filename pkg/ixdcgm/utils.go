@@ -46,13 +46,6 @@ func stringPtr(c *C.char) *string {
 	return &s
 }
 
-type DcgmError struct {
-	msg  string         // description of error
-	Code C.dcgmReturn_t // dcgmReturn_t value of error
-}
-
-func (e *DcgmError) Error() string { return e.msg }
-
 func makeVersion1(struct_type uintptr) C.uint {
 	version := C.uint(struct_type | 1<<24)
 	return version
@@ -82,16 +75,14 @@ func errorString(result C.dcgmReturn_t) error {
 	if result == C.DCGM_ST_OK {
 		return nil
 	}
-	err := C.GoString(C.errorString(result))
-	return fmt.Errorf("%v", err)
+	return &DcgmError{msg: C.GoString(C.errorString(result)), Code: ReturnCode(result)}
 }
 
 func ixdcgmErrorString(result C.ixdcgmReturn_t) error {
 	if result == C.IXDCGM_RET_OK {
 		return nil
 	}
-	err := C.GoString(C.ixdcgmErrorString(result))
-	return fmt.Errorf("%v", err)
+	return &DcgmError{msg: C.GoString(C.ixdcgmErrorString(result)), Code: ReturnCode(result)}
 }
 
 func string2Char(c string) *C.char {
@@ -106,18 +97,6 @@ func cChar2String(c *C.char) string {
 	return C.GoString(c)
 }
 
-func removeBytesSpaces(originalBytes []byte) string {
-	lastNonZeroIndex := len(originalBytes) - 1
-	for ; lastNonZeroIndex >= 0; lastNonZeroIndex-- {
-		if originalBytes[lastNonZeroIndex] != 0 {
-			break
-		}
-	}
-	cleanedBytes := originalBytes[:lastNonZeroIndex+1]
-
-	return string(cleanedBytes)
-}
-
 // convertBitsetStr converts a set of numbers in string format to a range representation.
 // input sample: "{0,1,2,3,6,10,11,12,13}"
 // output sample: "0-3,6,10-13"
@@ -179,7 +158,7 @@ func convertBitsetStr(input string) (output string) {
 func parseDirPath(path string) (string, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return "", fmt.Errorf("Error to parse dir path %s, err: %v", path, err)
+		return "", fmt.Errorf("Error to parse dir path %s, err: %w", path, err)
 	}
 	return absPath, nil
 }
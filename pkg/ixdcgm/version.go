@@ -0,0 +1,260 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// SupportedDriverVersion is the IX driver version the package is compatible with
+const SupportedDriverVersion = "4.2.0"
+
+// VersionInfo describes the build of the IXDCGM library or of the ix-hostengine
+type VersionInfo struct {
+	Version       string
+	Arch          string
+	BuildId       string
+	Commit        string
+	Author        string
+	Branch        string
+	BuildType     string
+	BuildDate     string
+	BuildPlatform string
+
+	// Fields holds all the key/value pairs of the build info, including the ones above
+	Fields map[string]string
+	// Raw is the build info as returned by IXDCGM, e.g. "version:4.2.0;arch:x86_64;..."
+	Raw string
+}
+
+// parseVersionInfo parses the semicolon separated key/value pairs of the raw build info,
+// only the first colon of a pair separates the key from the value
+func parseVersionInfo(raw string) VersionInfo {
+	info := VersionInfo{Fields: make(map[string]string), Raw: raw}
+	for _, pair := range strings.Split(raw, ";") {
+		key, value, found := strings.Cut(pair, ":")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		info.Fields[key] = value
+
+		switch key {
+		case "version":
+			info.Version = value
+		case "arch":
+			info.Arch = value
+		case "buildid":
+			info.BuildId = value
+		case "commit":
+			info.Commit = value
+		case "author":
+			info.Author = value
+		case "branch":
+			info.Branch = value
+		case "buildtype":
+			info.BuildType = value
+		case "builddate":
+			info.BuildDate = value
+		case "buildplatform":
+			info.BuildPlatform = value
+		}
+	}
+	return info
+}
+
+// GetLibraryVersionInfo describes the build of the loaded IXDCGM library, IXDCGM must be initialized
+func GetLibraryVersionInfo() (VersionInfo, error) {
+	libMu.Lock()
+	defer libMu.Unlock()
+	if loadedLib == nil {
+		return VersionInfo{}, fmt.Errorf("Error getting library version info: %w", errLibNotLoaded())
+	}
+
+	var versionInfo C.dcgmVersionInfo_t
+	versionInfo.version = makeVersion2(unsafe.Sizeof(versionInfo))
+
+	result := C.dcgmVersionInfo(&versionInfo)
+	if err := errorString(result); err != nil {
+		return VersionInfo{}, fmt.Errorf("Error getting library version info: %w", err)
+	}
+	return parseVersionInfo(C.GoString(&versionInfo.rawBuildInfoString[0])), nil
+}
+
+// GetHostengineVersionInfo describes the build of the ix-hostengine the default client is connected to
+func GetHostengineVersionInfo() (VersionInfo, error) {
+	return defaultClient.GetHostengineVersionInfo()
+}
+
+// GetHostengineVersionInfo describes the build of the ix-hostengine the client is connected to
+func (c *Client) GetHostengineVersionInfo() (VersionInfo, error) {
+	h, release, err := c.libHandle()
+	if err != nil {
+		return VersionInfo{}, err
+	}
+	defer release()
+
+	var versionInfo C.dcgmVersionInfo_t
+	versionInfo.version = makeVersion2(unsafe.Sizeof(versionInfo))
+
+	result := C.dcgmHostengineVersionInfo(h, &versionInfo)
+	if err := errorString(result); err != nil {
+		return VersionInfo{}, fmt.Errorf("Error getting ix-hostengine version info: %w", err)
+	}
+	return parseVersionInfo(C.GoString(&versionInfo.rawBuildInfoString[0])), nil
+}
+
+// VersionCheck selects what happens when incompatible versions are found once connected
+type VersionCheck int
+
+const (
+	VersionCheckNone   VersionCheck = iota // Versions are not checked
+	VersionCheckWarn                       // Incompatible versions are logged as a warning
+	VersionCheckStrict                     // Incompatible versions make the connection fail, unknown ones are logged
+)
+
+// IncompatibleVersionError reports a version of the ix-hostengine or of the IX driver
+// which is not compatible with the IXDCGM library
+type IncompatibleVersionError struct {
+	Component string // "ix-hostengine" or "driver"
+	Version   string // the version found
+	Expected  string // the version the library is compatible with
+}
+
+func (e *IncompatibleVersionError) Error() string {
+	return fmt.Sprintf("incompatible %s version %s, expected %s", e.Component, e.Version, e.Expected)
+}
+
+// ErrVersionUnknown is wrapped by the errors of CheckCompatibility when a version cannot be
+// fetched or parsed, which does not mean the versions are incompatible
+var ErrVersionUnknown = errors.New("IXDCGM version unknown")
+
+// applyVersionCheck logs the result of CheckCompatibility according to mode,
+// it returns the error the connection is refused with in VersionCheckStrict mode
+func applyVersionCheck(mode VersionCheck, err error) error {
+	if err == nil || mode == VersionCheckNone {
+		return nil
+	}
+	var incompatible *IncompatibleVersionError
+	if !errors.As(err, &incompatible) {
+		getLogger().Warn("Failed to check IXDCGM versions", "error", err)
+		return nil
+	}
+	if mode == VersionCheckStrict {
+		return err
+	}
+	getLogger().Warn("Incompatible IXDCGM versions", "error", err)
+	return nil
+}
+
+// CheckCompatibility checks the versions of the default client, see Client.CheckCompatibility
+func CheckCompatibility() error {
+	return defaultClient.CheckCompatibility()
+}
+
+// CheckCompatibility checks that the ix-hostengine and the IX driver have the same major and
+// minor version as the IXDCGM library and SupportedDriverVersion respectively.
+// Each mismatch is reported as an *IncompatibleVersionError, use errors.As to find them,
+// and each version which cannot be fetched or parsed as an error wrapping ErrVersionUnknown.
+func (c *Client) CheckCompatibility() error {
+	var errs []error
+	hostengine, err := c.GetHostengineVersionInfo()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%w: %w", ErrVersionUnknown, err))
+	}
+	lib, err := GetLibraryVersionInfo()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%w: %w", ErrVersionUnknown, err))
+	}
+	if len(errs) == 0 {
+		errs = append(errs, checkVersion("ix-hostengine", hostengine.Version, lib.Version))
+	}
+
+	gpus, err := c.getSupportedDevices()
+	if err != nil {
+		return errors.Join(append(errs, fmt.Errorf("%w: %w", ErrVersionUnknown, err))...)
+	}
+	// all the GPUs of a node are driven by the same driver
+	if len(gpus) > 0 {
+		driver, err := c.getDriverVersion(gpus[0])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %w", ErrVersionUnknown, err))
+		} else {
+			errs = append(errs, checkVersion("driver", driver, SupportedDriverVersion))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkVersion returns an *IncompatibleVersionError if version does not have the major and minor
+// version of expected, and an error wrapping ErrVersionUnknown if either cannot be parsed
+func checkVersion(component, version, expected string) error {
+	if _, _, ok := parseMajorMinor(version); !ok {
+		return fmt.Errorf("%w: invalid %s version %q", ErrVersionUnknown, component, version)
+	}
+	if _, _, ok := parseMajorMinor(expected); !ok {
+		return fmt.Errorf("%w: invalid expected %s version %q", ErrVersionUnknown, component, expected)
+	}
+	if !sameMajorMinor(version, expected) {
+		return &IncompatibleVersionError{Component: component, Version: version, Expected: expected}
+	}
+	return nil
+}
+
+func (c *Client) getDriverVersion(gpuId uint) (string, error) {
+	attr, err := c.backend().getDeviceAttributes(gpuId)
+	if err != nil {
+		return "", fmt.Errorf("Error getting driver version: %w", err)
+	}
+	return attr.DriverVersion, nil
+}
+
+// parseMajorMinor parses the major and minor numbers of a dotted version, e.g. 4 and 2 for "4.2.0"
+func parseMajorMinor(v string) (major, minor int, ok bool) {
+	parts := strings.SplitN(strings.TrimSpace(v), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil || major < 0 {
+		return 0, 0, false
+	}
+	minor, err = strconv.Atoi(parts[1])
+	if err != nil || minor < 0 {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// sameMajorMinor reports whether the two dotted versions have the same major and minor version,
+// versions which cannot be parsed are never the same
+func sameMajorMinor(v1, v2 string) bool {
+	major1, minor1, ok1 := parseMajorMinor(v1)
+	major2, minor2, ok2 := parseMajorMinor(v2)
+	return ok1 && ok2 && major1 == major2 && minor1 == minor2
+}
@@ -0,0 +1,128 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseVersionInfo(t *testing.T) {
+	tests := []struct {
+		raw     string
+		version string
+		fields  map[string]string
+	}{
+		{"", "", map[string]string{}},
+		{"version:4.2.0;arch:x86_64", "4.2.0", map[string]string{"version": "4.2.0", "arch": "x86_64"}},
+		{" version : 4.2.0 ; commit:abc", "4.2.0", map[string]string{"version": "4.2.0", "commit": "abc"}},
+		// only the first colon separates the key from the value
+		{"builddate:2024-01-01 10:00:00;version:4.2", "4.2", map[string]string{"builddate": "2024-01-01 10:00:00", "version": "4.2"}},
+		// the pairs without a colon are ignored
+		{"garbage;version:4.2.0;;", "4.2.0", map[string]string{"version": "4.2.0"}},
+		{"version", "", map[string]string{}},
+	}
+	for _, tt := range tests {
+		info := parseVersionInfo(tt.raw)
+		if info.Version != tt.version || !reflect.DeepEqual(info.Fields, tt.fields) || info.Raw != tt.raw {
+			t.Errorf("parseVersionInfo(%q) = %+v, want version %q and fields %v", tt.raw, info, tt.version, tt.fields)
+		}
+	}
+}
+
+func TestSameMajorMinor(t *testing.T) {
+	tests := []struct {
+		v1, v2 string
+		same   bool
+	}{
+		{"4.2.0", "4.2.0", true},
+		{"4.2.0", "4.2.1", true},
+		{"4.2", "4.2.0", true},
+		{"4.2.0", "4.3.0", false},
+		{"4.2.0", "5.2.0", false},
+		{"", "", false},
+		{"", "4.2.0", false},
+		{"4", "4.2.0", false},
+		{"4.x", "4.x", false},
+		{"a.b.c", "a.b.c", false},
+		{"-1.2", "-1.2", false},
+	}
+	for _, tt := range tests {
+		if same := sameMajorMinor(tt.v1, tt.v2); same != tt.same {
+			t.Errorf("sameMajorMinor(%q, %q) = %t, want %t", tt.v1, tt.v2, same, tt.same)
+		}
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	var incompatible *IncompatibleVersionError
+	if err := checkVersion("driver", "4.2.1", "4.2.0"); err != nil {
+		t.Errorf("checkVersion of the same minor version = %v", err)
+	}
+	if err := checkVersion("driver", "4.3.0", "4.2.0"); !errors.As(err, &incompatible) || incompatible.Version != "4.3.0" {
+		t.Errorf("checkVersion of another minor version = %v, want an IncompatibleVersionError", err)
+	}
+	for _, version := range []string{"", "4", "unknown"} {
+		err := checkVersion("driver", version, "4.2.0")
+		if !errors.Is(err, ErrVersionUnknown) || errors.As(err, &incompatible) {
+			t.Errorf("checkVersion of %q = %v, want ErrVersionUnknown", version, err)
+		}
+	}
+}
+
+func TestApplyVersionCheck(t *testing.T) {
+	incompatible := &IncompatibleVersionError{Component: "driver", Version: "4.3.0", Expected: "4.2.0"}
+	unknown := errors.Join(ErrVersionUnknown, errors.New("connection lost"))
+	tests := []struct {
+		mode    VersionCheck
+		err     error
+		refused bool
+	}{
+		{VersionCheckStrict, nil, false},
+		{VersionCheckStrict, incompatible, true},
+		{VersionCheckStrict, errors.Join(unknown, incompatible), true},
+		// a version which cannot be fetched does not refuse the connection
+		{VersionCheckStrict, unknown, false},
+		{VersionCheckWarn, incompatible, false},
+		{VersionCheckWarn, unknown, false},
+		{VersionCheckNone, incompatible, false},
+	}
+	for _, tt := range tests {
+		if err := applyVersionCheck(tt.mode, tt.err); (err != nil) != tt.refused {
+			t.Errorf("applyVersionCheck(%d, %v) = %v, want refused %t", tt.mode, tt.err, err, tt.refused)
+		}
+	}
+}
+
+func TestCheckCompatibilityWithoutLibrary(t *testing.T) {
+	if _, err := GetLibraryVersionInfo(); !errors.Is(err, ErrUninitialized) {
+		t.Errorf("GetLibraryVersionInfo without the library returned %v, want ErrUninitialized", err)
+	}
+
+	client := NewFakeClient(NewFakeBackend(1))
+	defer client.Close()
+	err := client.CheckCompatibility()
+	if !errors.Is(err, ErrVersionUnknown) {
+		t.Errorf("CheckCompatibility of a fake client returned %v, want ErrVersionUnknown", err)
+	}
+	var incompatible *IncompatibleVersionError
+	if errors.As(err, &incompatible) {
+		t.Errorf("CheckCompatibility of a fake client reported %v", incompatible)
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm
+
+/*
+#include "include/dcgm_agent.h"
+#include "include/dcgm_structs.h"
+
+extern int valuesSinceEnumeration(dcgm_field_entity_group_t entityGroupId, dcgm_field_eid_t entityId,
+                                  dcgmFieldValue_v1 *values, int numValues, void *userData);
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime/cgo"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// Sample is a value of a field of an entity read by a Watcher
+type Sample struct {
+	Entity GroupEntityPair
+	Field  Short
+	Ts     time.Time
+	Value  Value
+}
+
+// entityValue is a field value of an entity returned by getValuesSince
+type entityValue struct {
+	entity GroupEntityPair
+	value  FieldValue_v1
+}
+
+func (v entityValue) sample() Sample {
+	return Sample{Entity: v.entity, Field: Short(v.value.FieldId), Ts: time.UnixMicro(v.value.Ts), Value: v.value.TypedValue()}
+}
+
+// Watcher watches fields of entities until it is closed and delivers every new value of the fields on a channel
+type Watcher struct {
+	c        *Client
+	group    GroupHandle
+	fieldGrp FieldGrpHandle
+	interval time.Duration
+	since    int64 // timestamp the next values are read since, only used by run
+
+	samples   chan Sample
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewWatcher watches fields of entities with the default client, see Client.NewWatcher
+func NewWatcher(entities []GroupEntityPair, fields []Short, interval, maxKeepAge time.Duration, maxKeepSamples int32) (*Watcher, error) {
+	return defaultClient.NewWatcher(entities, fields, interval, maxKeepAge, maxKeepSamples)
+}
+
+// NewWatcherCtx is NewWatcher returning ctx.Err() when ctx is done before the first update of the fields
+func NewWatcherCtx(ctx context.Context,
+	entities []GroupEntityPair, fields []Short, interval, maxKeepAge time.Duration, maxKeepSamples int32,
+) (*Watcher, error) {
+	return defaultClient.NewWatcherCtx(ctx, entities, fields, interval, maxKeepAge, maxKeepSamples)
+}
+
+// NewWatcher watches fields of entities, which are updated every interval and kept by the hostengine
+// for maxKeepAge or up to maxKeepSamples values, 0 for no limit. The values updated since the previous
+// read are read every interval and delivered by Samples, so that no value is lost as long as they are read
+// before the hostengine drops them. The watcher must be closed by Close before the client.
+func (c *Client) NewWatcher(
+	entities []GroupEntityPair, fields []Short, interval, maxKeepAge time.Duration, maxKeepSamples int32,
+) (*Watcher, error) {
+	return c.NewWatcherCtx(context.Background(), entities, fields, interval, maxKeepAge, maxKeepSamples)
+}
+
+// NewWatcherCtx is NewWatcher returning ctx.Err() when ctx is done before the first update of the fields,
+// nothing is left watched when it fails
+func (c *Client) NewWatcherCtx(ctx context.Context,
+	entities []GroupEntityPair, fields []Short, interval, maxKeepAge time.Duration, maxKeepSamples int32,
+) (_ *Watcher, err error) {
+	if len(entities) == 0 || len(fields) == 0 || interval <= 0 {
+		return nil, fmt.Errorf("a watcher needs entities, fields and a positive interval: %w", newDcgmError(DCGM_ST_BADPARAM))
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		c:        c,
+		interval: interval,
+		samples:  make(chan Sample, len(entities)*len(fields)),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if w.fieldGrp, err = c.FieldGroupCreate(fmt.Sprintf("watcherFields%d", rand.Uint64()), fields); err != nil {
+		return nil, err
+	}
+	if w.group, err = c.CreateGroupWithType(fmt.Sprintf("watcherGrp%d", rand.Uint64()), GroupEmpty); err != nil {
+		_ = c.FieldGroupDestroy(w.fieldGrp)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			w.release()
+		}
+	}()
+	for _, entity := range entities {
+		if err = ctx.Err(); err == nil {
+			err = c.AddEntityToGroup(w.group, entity.EntityGroupId, entity.EntityId)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = c.WatchFieldsWithGroupExCtx(ctx, w.fieldGrp, w.group,
+		interval.Microseconds(), maxKeepAge.Seconds(), maxKeepSamples)
+	if err != nil {
+		return nil, err
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// Samples returns the channel the values are delivered on, which is closed by Close or once the client is closed.
+// Reading is paused while the values are not received.
+func (w *Watcher) Samples() <-chan Sample {
+	return w.samples
+}
+
+// Close stops reading the values, stops watching the fields and destroys the groups of the watcher
+func (w *Watcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		<-w.done
+		w.closeErr = w.release()
+	})
+	return w.closeErr
+}
+
+func (w *Watcher) release() error {
+	var errs []error
+	if err := w.c.UnwatchFields(w.fieldGrp, w.group); err != nil {
+		errs = append(errs, err)
+	}
+	if err := w.c.DestroyGroup(w.group); err != nil {
+		errs = append(errs, err)
+	}
+	if err := w.c.FieldGroupDestroy(w.fieldGrp); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+	defer close(w.samples)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for w.poll() {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll delivers the values updated since the previous poll, it returns false once the watcher is closed
+func (w *Watcher) poll() bool {
+	var values []entityValue
+	next := w.since
+	err := w.c.withBackend(func(b backend) (err error) {
+		values, next, err = b.getValuesSince(w.c.res.groupId(w.group), w.c.res.fieldGroupId(w.fieldGrp), w.since)
+		return err
+	})
+	if errors.Is(err, ErrUninitialized) {
+		getLogger().Warn("Stopped reading the watched fields of a closed client")
+		return false
+	}
+	if err != nil {
+		getLogger().Warn("Failed to read the watched fields", "error", err)
+		return true
+	}
+	w.since = next
+
+	for _, v := range values {
+		select {
+		case w.samples <- v.sample():
+		case <-w.stop:
+			return false
+		}
+	}
+	return true
+}
+
+func (b cgoBackend) getValuesSince(
+	groupId C.dcgmGpuGrp_t, fieldGroupId C.dcgmFieldGrp_t, since int64,
+) ([]entityValue, int64, error) {
+	var values []entityValue
+	h := cgo.NewHandle(&values)
+	defer h.Delete()
+
+	var next C.longlong
+	res := C.dcgmGetValuesSince_v2(b.handle, groupId, fieldGroupId, C.longlong(since), &next,
+		C.dcgmFieldValueEntityEnumeration_f(C.valuesSinceEnumeration), unsafe.Pointer(&h))
+	if err := errorString(res); err != nil {
+		return nil, since, fmt.Errorf("error getting DCGM field values since %d: %w", since, err)
+	}
+	return values, int64(next), nil
+}
+
+// valuesSinceCallback appends the values of an entity to the values of getValuesSince,
+// it is called by dcgmGetValuesSince_v2 through valuesSinceEnumeration
+//
+//export valuesSinceCallback
+func valuesSinceCallback(entityGroupId C.dcgm_field_entity_group_t, entityId C.dcgm_field_eid_t,
+	values *C.dcgmFieldValue_v1, numValues C.int, userData unsafe.Pointer) C.int {
+	dst := (*(*cgo.Handle)(userData)).Value().(*[]entityValue)
+	entity := GroupEntityPair{Field_Entity_Group(entityGroupId), uint(entityId)}
+	// the values are only valid during the callback
+	for _, fv := range toFieldValue(unsafe.Slice(values, int(numValues)), true) {
+		*dst = append(*dst, entityValue{entity, fv})
+	}
+	return 0
+}
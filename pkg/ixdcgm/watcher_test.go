@@ -0,0 +1,115 @@
+/*
+Copyright (c) 2024, Shanghai Iluvatar CoreX Semiconductor Co., Ltd.
+All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may
+not use this file except in compliance with the License. You may obtain
+a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ixdcgm_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gitee.com/deep-spark/go-ixdcgm/pkg/ixdcgm"
+)
+
+func TestWatcher(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(2)
+	fake.SetFieldInt64(0, ixdcgm.DCGM_FI_DEV_GPU_TEMP, 40, 41)
+	fake.SetFieldInt64(1, ixdcgm.DCGM_FI_DEV_GPU_TEMP, 50)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	gpus := []ixdcgm.GroupEntityPair{{EntityGroupId: ixdcgm.FE_GPU, EntityId: 0}, {EntityGroupId: ixdcgm.FE_GPU, EntityId: 1}}
+	fields := []ixdcgm.Short{ixdcgm.DCGM_FI_DEV_GPU_TEMP}
+	w, err := client.NewWatcher(gpus, fields, 10*time.Millisecond, time.Minute, 0)
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+
+	// every scripted value is delivered once and in order, then the fake updates the field with its last value
+	scripts := map[ixdcgm.GroupEntityPair][]int64{gpus[0]: {40, 41}, gpus[1]: {50}}
+	received := make(map[ixdcgm.GroupEntityPair]int)
+	lastTs := make(map[ixdcgm.GroupEntityPair]time.Time)
+	done := func() bool {
+		for entity, script := range scripts {
+			if received[entity] <= len(script) {
+				return false
+			}
+		}
+		return true
+	}
+	timeout := time.After(5 * time.Second)
+	for !done() {
+		select {
+		case s := <-w.Samples():
+			script, exists := scripts[s.Entity]
+			if !exists || s.Field != ixdcgm.DCGM_FI_DEV_GPU_TEMP {
+				t.Fatalf("unexpected sample of field %d of %+v", s.Field, s.Entity)
+			}
+			want := script[min(received[s.Entity], len(script)-1)]
+			if v, ok := s.Value.Int64(); !ok || v != want {
+				t.Fatalf("sample %d of %+v = %d, %t, want %d", received[s.Entity], s.Entity, v, ok, want)
+			}
+			if !s.Ts.After(lastTs[s.Entity]) {
+				t.Fatalf("sample %d of %+v at %v, not after the previous one at %v", received[s.Entity], s.Entity, s.Ts, lastTs[s.Entity])
+			}
+			received[s.Entity]++
+			lastTs[s.Entity] = s.Ts
+		case <-timeout:
+			t.Fatalf("values not delivered, received %v", received)
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	for range w.Samples() {
+	}
+	if n := fake.GroupCount(); n != 0 {
+		t.Errorf("%d groups left after Close", n)
+	}
+	if n := fake.FieldGroupCount(); n != 0 {
+		t.Errorf("%d field groups left after Close", n)
+	}
+	values, err := client.GetLatestValuesForFields(0, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[0].Status != ixdcgm.DCGM_ST_NOT_WATCHED {
+		t.Errorf("status of the field once the watcher is closed = %d, want DCGM_ST_NOT_WATCHED", values[0].Status)
+	}
+}
+
+func TestWatcherInvalid(t *testing.T) {
+	fake := ixdcgm.NewFakeBackend(1)
+	client := ixdcgm.NewFakeClient(fake)
+	defer client.Close()
+
+	gpu := ixdcgm.GroupEntityPair{EntityGroupId: ixdcgm.FE_GPU, EntityId: 0}
+	if _, err := client.NewWatcher([]ixdcgm.GroupEntityPair{gpu}, nil, time.Second, 0, 0); !errors.Is(err, ixdcgm.ErrBadParam) {
+		t.Errorf("NewWatcher without fields returned %v, want ErrBadParam", err)
+	}
+
+	// the groups are destroyed when an entity cannot be watched
+	cpu := ixdcgm.GroupEntityPair{EntityGroupId: ixdcgm.FE_CPU, EntityId: 0}
+	fields := []ixdcgm.Short{ixdcgm.DCGM_FI_DEV_GPU_TEMP}
+	if _, err := client.NewWatcher([]ixdcgm.GroupEntityPair{gpu, cpu}, fields, time.Second, 0, 0); err == nil {
+		t.Error("NewWatcher of a CPU succeeded")
+	}
+	if fake.GroupCount() != 0 || fake.FieldGroupCount() != 0 {
+		t.Errorf("%d groups and %d field groups left after NewWatcher failed", fake.GroupCount(), fake.FieldGroupCount())
+	}
+}